@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AttachmentsOptions describes an attachments extraction (fonts, cover art, ...)
+type AttachmentsOptions struct {
+	InputFileName string
+	OutputDir     string
+	Overwrite     bool
+}
+
+func buildAttachmentsDir(inputFileName string) string {
+	return strings.TrimSuffix(inputFileName, path.Ext(inputFileName)) + "_attachments"
+}
+
+func extractAttachments(options AttachmentsOptions) error {
+	mkvInfo, identifyErr := identifyFile(options.InputFileName, 0, nil)
+	if identifyErr != nil {
+		return identifyErr
+	}
+	if len(mkvInfo.Attachments) == 0 {
+		logrus.
+			WithField("inputFileName", options.InputFileName).
+			Error("File has no attachments")
+		return errors.New("file has no attachments")
+	}
+	if options.OutputDir == "" {
+		options.OutputDir = buildAttachmentsDir(options.InputFileName)
+	}
+	if mkdirErr := os.MkdirAll(options.OutputDir, 0755); mkdirErr != nil {
+		return mkdirErr
+	}
+	policy := ConflictPolicyFail
+	if options.Overwrite {
+		policy = ConflictPolicyOverwrite
+	}
+	args := []string{options.InputFileName, "attachments"}
+	for _, attachment := range mkvInfo.Attachments {
+		// Attachment names come from the file, never let them escape the output directory
+		fileName := path.Base(strings.ReplaceAll(attachment.FileName, "\\", "/"))
+		if fileName == "." || fileName == "/" || fileName == "" {
+			fileName = fmt.Sprintf("attachment%d", attachment.Id)
+		}
+		outFileName, _, conflictErr := resolveOutputFileName(path.Join(options.OutputDir, fileName), policy)
+		if conflictErr != nil {
+			logrus.WithError(conflictErr).Error("Output file already exists")
+			return conflictErr
+		}
+		args = append(args, fmt.Sprintf("%d:%s", attachment.Id, outFileName))
+	}
+	cmd := exec.Command("mkvextract", args...)
+	logrus.
+		WithField("cmd", cmd.String()).
+		Debug("Running mkvextract")
+	output, cmdErr := cmd.CombinedOutput()
+	if cmdErr != nil {
+		logrus.
+			WithField("cmd", cmd).
+			WithError(cmdErr).
+			Error("Error executing extract command")
+		fmt.Println(string(output))
+		return cmdErr
+	}
+	logrus.
+		WithField("outputDir", options.OutputDir).
+		WithField("attachments", len(mkvInfo.Attachments)).
+		Info("Attachments extracted")
+	return nil
+}
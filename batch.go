@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// collectInputFiles returns the MKV file itself, or every MKV file below a directory
+func collectInputFiles(input string) ([]string, error) {
+	ifs, statErr := os.Stat(input)
+	if statErr != nil {
+		logrus.
+			WithError(statErr).
+			WithField("inputFileName", input).
+			Errorf("File does not exist: %s", input)
+		return nil, statErr
+	}
+	if !ifs.IsDir() {
+		if !isMKVFile(input) {
+			logrus.
+				WithField("inputFileName", input).
+				Error("File is not an MKV file")
+			return nil, errors.New("file is not an MKV file")
+		}
+		return []string{input}, nil
+	}
+	inputFileNames := []string{}
+	walkErr := filepath.WalkDir(input, func(fileName string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && isMKVFile(fileName) {
+			inputFileNames = append(inputFileNames, filepath.ToSlash(fileName))
+		}
+		return nil
+	})
+	if walkErr != nil {
+		logrus.
+			WithError(walkErr).
+			WithField("inputDir", input).
+			Error("Error scanning directory")
+		return nil, walkErr
+	}
+	sort.Strings(inputFileNames)
+	logrus.
+		WithField("inputDir", input).
+		WithField("files", len(inputFileNames)).
+		Info("Found MKV files")
+	return inputFileNames, nil
+}
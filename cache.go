@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/sirupsen/logrus"
+)
+
+// IdentifyCache stores `mkvmerge -J` output keyed by path, size and
+// modification time, so unchanged files are not identified again
+type IdentifyCache struct {
+	dir string
+}
+
+func defaultCacheDir() string {
+	cacheDir, dirErr := os.UserCacheDir()
+	if dirErr != nil {
+		return ""
+	}
+	return path.Join(cacheDir, "gmmmkvsubsextract", "identify")
+}
+
+func newIdentifyCache(dir string) (*IdentifyCache, error) {
+	if dir == "" {
+		dir = defaultCacheDir()
+	}
+	if dir == "" {
+		return nil, nil
+	}
+	if mkdirErr := os.MkdirAll(dir, 0755); mkdirErr != nil {
+		return nil, mkdirErr
+	}
+	return &IdentifyCache{dir: dir}, nil
+}
+
+func (cache *IdentifyCache) entryFileName(inputFileName string) (string, bool) {
+	ifs, statErr := os.Stat(inputFileName)
+	if statErr != nil {
+		return "", false
+	}
+	key := fmt.Sprintf("%s|%d|%d", absolutePath(inputFileName), ifs.Size(), ifs.ModTime().UnixNano())
+	sum := sha256.Sum256([]byte(key))
+	return path.Join(cache.dir, hex.EncodeToString(sum[:])+".json"), true
+}
+
+func (cache *IdentifyCache) Get(inputFileName string) ([]byte, bool) {
+	if cache == nil {
+		return nil, false
+	}
+	entryFileName, ok := cache.entryFileName(inputFileName)
+	if !ok {
+		return nil, false
+	}
+	data, readErr := os.ReadFile(entryFileName)
+	if readErr != nil {
+		return nil, false
+	}
+	logrus.
+		WithField("inputFileName", inputFileName).
+		Debug("Using cached identification")
+	return data, true
+}
+
+func (cache *IdentifyCache) Put(inputFileName string, data []byte) {
+	if cache == nil {
+		return
+	}
+	entryFileName, ok := cache.entryFileName(inputFileName)
+	if !ok {
+		return
+	}
+	if writeErr := os.WriteFile(entryFileName, data, 0644); writeErr != nil {
+		logrus.
+			WithError(writeErr).
+			WithField("cacheFileName", entryFileName).
+			Warn("Error writing identify cache")
+	}
+}
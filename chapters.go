@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ChaptersOptions describes a chapters extraction
+type ChaptersOptions struct {
+	InputFileName  string
+	OutputFileName string
+	Simple         bool
+	Overwrite      bool
+}
+
+func buildChaptersFileName(inputFileName string) string {
+	return strings.TrimSuffix(inputFileName, path.Ext(inputFileName)) + "_chapters.txt"
+}
+
+func extractChapters(options ChaptersOptions) error {
+	mkvInfo, identifyErr := identifyFile(options.InputFileName, 0, nil)
+	if identifyErr != nil {
+		return identifyErr
+	}
+	if len(mkvInfo.Chapters) == 0 {
+		logrus.
+			WithField("inputFileName", options.InputFileName).
+			Error("File has no chapters")
+		return errors.New("file has no chapters")
+	}
+	if options.OutputFileName == "" {
+		options.OutputFileName = buildChaptersFileName(options.InputFileName)
+	}
+	policy := ConflictPolicyFail
+	if options.Overwrite {
+		policy = ConflictPolicyOverwrite
+	}
+	if _, _, conflictErr := resolveOutputFileName(options.OutputFileName, policy); conflictErr != nil {
+		logrus.WithError(conflictErr).Error("Output file already exists")
+		return conflictErr
+	}
+	args := []string{options.InputFileName, "chapters"}
+	if options.Simple {
+		args = append(args, "--simple")
+	}
+	args = append(args, options.OutputFileName)
+	cmd := exec.Command("mkvextract", args...)
+	logrus.
+		WithField("cmd", cmd.String()).
+		Debug("Running mkvextract")
+	output, cmdErr := cmd.CombinedOutput()
+	if cmdErr != nil {
+		logrus.
+			WithField("cmd", cmd).
+			WithError(cmdErr).
+			Error("Error executing extract command")
+		fmt.Println(string(output))
+		return cmdErr
+	}
+	logrus.
+		WithField("outFileName", options.OutputFileName).
+		Info("Chapters extracted")
+	return nil
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// completionOption is a command line option as seen by the shell
+type completionOption struct {
+	Short       string
+	Long        string
+	Description string
+	TakesValue  bool
+}
+
+// completionCommand is the root command (empty Name) or a subcommand
+type completionCommand struct {
+	Name        string
+	Parent      string
+	Description string
+	Options     []completionOption
+	Subcommands []string
+}
+
+// completionCommands reads options and subcommands from the gocmd flags
+// struct, so completions never drift from the real command line
+func completionCommands(flags interface{}) []completionCommand {
+	flagsType := reflect.TypeOf(flags)
+	if flagsType.Kind() == reflect.Ptr {
+		flagsType = flagsType.Elem()
+	}
+	root := completionCommand{}
+	commands := collectCompletionCommands(flagsType, "", &root)
+	return append([]completionCommand{root}, commands...)
+}
+
+// collectCompletionCommands adds the options of structType to command and
+// returns its subcommands, nested ones (srt shift) after their parent
+func collectCompletionCommands(structType reflect.Type, parent string, command *completionCommand) []completionCommand {
+	commands := []completionCommand{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if name := field.Tag.Get("command"); name != "" {
+			subcommand := completionCommand{Name: name, Parent: parent, Description: field.Tag.Get("description")}
+			nested := collectCompletionCommands(field.Type, name, &subcommand)
+			commands = append(append(commands, subcommand), nested...)
+			command.Subcommands = append(command.Subcommands, name)
+			continue
+		}
+		if option, ok := completionOptionFromField(field); ok {
+			command.Options = append(command.Options, option)
+		}
+	}
+	return commands
+}
+
+func completionOptionFromField(field reflect.StructField) (completionOption, bool) {
+	option := completionOption{
+		Short:       field.Tag.Get("short"),
+		Long:        field.Tag.Get("long"),
+		Description: field.Tag.Get("description"),
+		TakesValue:  field.Type.Kind() != reflect.Bool,
+	}
+	return option, option.Short != "" || option.Long != ""
+}
+
+// completionValues lists the known values of options that take a fixed set
+func completionValues() map[string][]string {
+	languageCodes := map[string]bool{}
+	for code := range twoLetterLanguageCodes {
+		languageCodes[code] = true
+	}
+	for code := range tesseractLanguageCodes {
+		languageCodes[code] = true
+	}
+	languages := []string{}
+	for code := range languageCodes {
+		languages = append(languages, code)
+	}
+	sort.Strings(languages)
+	profiles := []string{}
+	for name := range builtinProfiles {
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+	return map[string][]string{
+		"lang":       languages,
+		"ocr-lang":   languages,
+		"name-style": {string(NameStylePlex), string(NameStyleJellyfin), string(NameStyleKodi)},
+		"layout":     {"next", string(LayoutFlat), string(LayoutSubfolder), string(LayoutMirror)},
+		"profile":    profiles,
+		// Only propedit's --default and --forced take a value; mux's are switches
+		"default": {"yes", "no"},
+		"forced":  {"yes", "no"},
+	}
+}
+
+func optionWords(options []completionOption) string {
+	words := []string{}
+	for _, option := range options {
+		if option.Short != "" {
+			words = append(words, "-"+option.Short)
+		}
+		if option.Long != "" {
+			words = append(words, "--"+option.Long)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// bashOptionPatterns returns the "$cmd:$prev" case patterns matching an
+// option of a command right before the word being completed. Global root
+// options are also accepted after a subcommand.
+func bashOptionPatterns(command completionCommand, option completionOption, global bool) []string {
+	scope := command.Name
+	if global {
+		scope = "*"
+	}
+	patterns := []string{}
+	if option.Short != "" {
+		patterns = append(patterns, scope+":-"+option.Short)
+	}
+	if option.Long != "" {
+		patterns = append(patterns, scope+":--"+option.Long)
+	}
+	return patterns
+}
+
+// bashValueCases returns the case patterns of the options that take a value,
+// per command since an option such as --default is a switch for one command
+// and takes a value for another: those with known values by option name, in
+// the order the options first appear, and those whose value is not known in
+// advance, such as a file name
+func bashValueCases(commands []completionCommand, values map[string][]string) ([]string, map[string][]string, []string) {
+	global := map[string]bool{}
+	for _, option := range globalCompletionOptions(commands[0]) {
+		global[option.Long] = true
+	}
+	names := []string{}
+	valuePatterns := map[string][]string{}
+	filePatterns := []string{}
+	for i, command := range commands {
+		for _, option := range command.Options {
+			if !option.TakesValue {
+				continue
+			}
+			patterns := bashOptionPatterns(command, option, i == 0 && global[option.Long])
+			if _, ok := values[option.Long]; !ok {
+				filePatterns = append(filePatterns, patterns...)
+				continue
+			}
+			if _, ok := valuePatterns[option.Long]; !ok {
+				names = append(names, option.Long)
+			}
+			valuePatterns[option.Long] = append(valuePatterns[option.Long], patterns...)
+		}
+	}
+	return names, valuePatterns, filePatterns
+}
+
+func bashCompletion(program string, commands []completionCommand) string {
+	var script strings.Builder
+	function := "_" + strings.ReplaceAll(program, "-", "_")
+	names := []string{}
+	for _, command := range commands[1:] {
+		names = append(names, command.Name)
+	}
+	fmt.Fprintf(&script, "# bash completion for %s\n", program)
+	fmt.Fprintf(&script, "%s() {\n", function)
+	script.WriteString("    local cur prev cmd word opts\n")
+	script.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	script.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	script.WriteString("    cmd=\"\"\n")
+	script.WriteString("    for word in \"${COMP_WORDS[@]:1:COMP_CWORD-1}\"; do\n")
+	fmt.Fprintf(&script, "        case \"$word\" in\n            %s) cmd=\"$word\" ;;\n        esac\n", strings.Join(names, "|"))
+	script.WriteString("    done\n")
+	script.WriteString("    case \"$cmd:$prev\" in\n")
+	values := completionValues()
+	valueOptions, valuePatterns, filePatterns := bashValueCases(commands, values)
+	for _, long := range valueOptions {
+		fmt.Fprintf(&script, "        %s)\n            COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n            return ;;\n", strings.Join(valuePatterns[long], "|"), strings.Join(values[long], " "))
+	}
+	if len(filePatterns) > 0 {
+		// Let the shell fall back to file name completion for other option values
+		fmt.Fprintf(&script, "        %s)\n            return ;;\n", strings.Join(filePatterns, "|"))
+	}
+	script.WriteString("    esac\n")
+	script.WriteString("    case \"$cmd\" in\n")
+	// Words other than options are only offered where a (sub)command or shell name is expected
+	wordCommands := []string{"completion"}
+	for _, command := range commands[1:] {
+		words := optionWords(append(command.Options, globalCompletionOptions(commands[0])...))
+		if command.Name == "completion" {
+			words = strings.Join(completionShells, " ") + " " + words
+		}
+		if len(command.Subcommands) > 0 {
+			words = strings.Join(command.Subcommands, " ") + " " + words
+			wordCommands = append(wordCommands, command.Name)
+		}
+		fmt.Fprintf(&script, "        %s) opts=\"%s\" ;;\n", command.Name, words)
+	}
+	fmt.Fprintf(&script, "        *) opts=\"%s %s\" ;;\n", optionWords(commands[0].Options), strings.Join(commands[0].Subcommands, " "))
+	script.WriteString("    esac\n")
+	script.WriteString("    case \"$cmd\" in\n")
+	fmt.Fprintf(&script, "        \"\"|%s) COMPREPLY=($(compgen -W \"$opts\" -- \"$cur\")) ;;\n", strings.Join(wordCommands, "|"))
+	script.WriteString("        *) [[ \"$cur\" == -* ]] && COMPREPLY=($(compgen -W \"$opts\" -- \"$cur\")) ;;\n")
+	script.WriteString("    esac\n")
+	script.WriteString("}\n")
+	fmt.Fprintf(&script, "complete -o default -F %s %s\n", function, program)
+	return script.String()
+}
+
+func zshCompletion(program string, commands []completionCommand) string {
+	return fmt.Sprintf("#compdef %s\n# zsh completion for %s, using zsh's bash completion support\nautoload -U +X bashcompinit && bashcompinit\n%s",
+		program, program, bashCompletion(program, commands))
+}
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+func fishCompletion(program string, commands []completionCommand) string {
+	var script strings.Builder
+	values := completionValues()
+	fmt.Fprintf(&script, "# fish completion for %s\n", program)
+	for _, command := range commands[1:] {
+		condition := "__fish_use_subcommand"
+		if command.Parent != "" {
+			condition = fmt.Sprintf("'__fish_seen_subcommand_from %s; and not __fish_seen_subcommand_from %s'", command.Parent, command.Name)
+		}
+		fmt.Fprintf(&script, "complete -c %s -f -n %s -a %s -d %s\n", program, condition, command.Name, fishQuote(command.Description))
+	}
+	fmt.Fprintf(&script, "complete -c %s -f -n '__fish_seen_subcommand_from completion' -a %s\n", program, fishQuote(strings.Join(completionShells, " ")))
+	for _, command := range commands {
+		condition := "__fish_use_subcommand"
+		if command.Name != "" {
+			condition = "'__fish_seen_subcommand_from " + command.Name + "'"
+		}
+		for _, option := range command.Options {
+			fmt.Fprintf(&script, "complete -c %s -n %s", program, condition)
+			if option.Short != "" {
+				fmt.Fprintf(&script, " -s %s", option.Short)
+			}
+			if option.Long != "" {
+				fmt.Fprintf(&script, " -l %s", option.Long)
+			}
+			if option.TakesValue {
+				script.WriteString(" -r")
+				if optionValues, ok := values[option.Long]; ok {
+					fmt.Fprintf(&script, " -f -a %s", fishQuote(strings.Join(optionValues, " ")))
+				}
+			}
+			fmt.Fprintf(&script, " -d %s\n", fishQuote(option.Description))
+		}
+	}
+	return script.String()
+}
+
+// globalCompletionOptions returns the root options that gocmd also accepts after a subcommand
+func globalCompletionOptions(root completionCommand) []completionOption {
+	global := []completionOption{}
+	for _, option := range root.Options {
+		switch option.Long {
+		case "help", "config", "quiet", "verbose", "log-file":
+			global = append(global, option)
+		}
+	}
+	return global
+}
+
+func fishQuote(value string) string {
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(value, `\`, `\\`), "'", `\'`) + "'"
+}
+
+func completionScript(shell string, program string, flags interface{}) (string, error) {
+	commands := completionCommands(flags)
+	switch shell {
+	case "bash":
+		return bashCompletion(program, commands), nil
+	case "zsh":
+		return zshCompletion(program, commands), nil
+	case "fish":
+		return fishCompletion(program, commands), nil
+	}
+	return "", fmt.Errorf("unsupported shell %q (expected bash, zsh or fish)", shell)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Config is the optional JSON configuration file of the CLI
+type Config struct {
+	Profiles        map[string]Profile `json:"profiles"`
+	IgnoreLanguages []string           `json:"ignore_languages"`
+}
+
+// Profile is a named bundle of extraction options. Options given on the
+// command line always win over the ones coming from a profile.
+type Profile struct {
+	Conflict       string `json:"conflict"`
+	OCR            bool   `json:"ocr"`
+	OCRLang        string `json:"ocr_lang"`
+	TessdataDir    string `json:"tessdata_dir"`
+	ToSRT          bool   `json:"to_srt"`
+	ForcedOnly     bool   `json:"forced_only"`
+	DefaultOnly    bool   `json:"default_only"`
+	SkipCommentary bool   `json:"skip_commentary"`
+	SkipSDH        bool   `json:"skip_sdh"`
+	MinEntries     int    `json:"min_entries"`
+	NameMatch      string `json:"name_match"`
+	NameExclude    string `json:"name_exclude"`
+	NameStyle      string `json:"name_style"`
+}
+
+// builtinProfiles are available without a config file and can be replaced
+// by a profile with the same name in the config file
+var builtinProfiles = map[string]Profile{
+	"anime": {
+		Conflict:       "skip",
+		SkipCommentary: true,
+		NameExclude:    `(?i)\bsigns\b|\bsongs\b`,
+	},
+	"plex": {
+		Conflict:       "skip",
+		NameStyle:      "plex",
+		OCR:            true,
+		ToSRT:          true,
+		SkipCommentary: true,
+	},
+}
+
+var conflictPolicyByName = map[string]ConflictPolicy{
+	"fail":      ConflictPolicyFail,
+	"skip":      ConflictPolicySkip,
+	"overwrite": ConflictPolicyOverwrite,
+	"rename":    ConflictPolicyRename,
+}
+
+func defaultConfigFileName() string {
+	configDir, dirErr := os.UserConfigDir()
+	if dirErr != nil {
+		return ""
+	}
+	return path.Join(configDir, "gmmmkvsubsextract", "config.json")
+}
+
+// loadConfig reads the config file. A missing file is only an error when its
+// name was given explicitly.
+func loadConfig(configFileName string) (Config, error) {
+	var config Config
+	explicit := configFileName != ""
+	if !explicit {
+		configFileName = defaultConfigFileName()
+	}
+	if configFileName == "" {
+		return config, nil
+	}
+	data, readErr := os.ReadFile(configFileName)
+	if readErr != nil {
+		if os.IsNotExist(readErr) && !explicit {
+			return config, nil
+		}
+		return config, readErr
+	}
+	if jsonErr := json.Unmarshal(data, &config); jsonErr != nil {
+		return config, fmt.Errorf("error parsing config file %s: %w", configFileName, jsonErr)
+	}
+	return config, nil
+}
+
+func (config Config) Profile(name string) (Profile, error) {
+	if profile, ok := config.Profiles[name]; ok {
+		return profile, nil
+	}
+	if profile, ok := builtinProfiles[name]; ok {
+		return profile, nil
+	}
+	names := []string{}
+	for profileName := range builtinProfiles {
+		names = append(names, profileName)
+	}
+	for profileName := range config.Profiles {
+		if _, ok := builtinProfiles[profileName]; !ok {
+			names = append(names, profileName)
+		}
+	}
+	sort.Strings(names)
+	return Profile{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Apply fills in the options that were not set on the command line
+func (profile Profile) Apply(options ExtractOptions) (ExtractOptions, error) {
+	if profile.Conflict != "" && options.ConflictPolicy == ConflictPolicyFail {
+		policy, ok := conflictPolicyByName[strings.ToLower(profile.Conflict)]
+		if !ok {
+			return options, fmt.Errorf("unknown conflict policy %q in profile", profile.Conflict)
+		}
+		options.ConflictPolicy = policy
+	}
+	options.OCR = options.OCR || profile.OCR
+	if options.OCRLang == "" {
+		options.OCRLang = profile.OCRLang
+	}
+	if options.TessdataDir == "" {
+		options.TessdataDir = profile.TessdataDir
+	}
+	options.ToSRT = options.ToSRT || profile.ToSRT
+	options.Filter.ForcedOnly = options.Filter.ForcedOnly || profile.ForcedOnly
+	options.Filter.DefaultOnly = options.Filter.DefaultOnly || profile.DefaultOnly
+	options.Filter.SkipCommentary = options.Filter.SkipCommentary || profile.SkipCommentary
+	options.Filter.SkipSDH = options.Filter.SkipSDH || profile.SkipSDH
+	if options.Filter.MinEntries == 0 {
+		options.Filter.MinEntries = profile.MinEntries
+	}
+	if options.Filter.NameMatch == nil {
+		nameMatch, nameMatchErr := compileNamePattern("name_match", profile.NameMatch)
+		if nameMatchErr != nil {
+			return options, nameMatchErr
+		}
+		options.Filter.NameMatch = nameMatch
+	}
+	if options.Filter.NameExclude == nil {
+		nameExclude, nameExcludeErr := compileNamePattern("name_exclude", profile.NameExclude)
+		if nameExcludeErr != nil {
+			return options, nameExcludeErr
+		}
+		options.Filter.NameExclude = nameExclude
+	}
+	if profile.NameStyle != "" && options.NameStyle == NameStyleDefault {
+		nameStyle, nameStyleErr := parseNameStyle(profile.NameStyle)
+		if nameStyleErr != nil {
+			return options, nameStyleErr
+		}
+		options.NameStyle = nameStyle
+	}
+	return options, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	CodecIdASS = "S_TEXT/ASS"
+	CodecIdSSA = "S_TEXT/SSA"
+)
+
+func isASSSubtitleCodec(codecId string) bool {
+	return codecId == CodecIdASS || codecId == CodecIdSSA
+}
+
+// convertASSToSRT converts an extracted ASS/SSA file with ffmpeg, the same way the GUI does.
+// Styling and positioning are lost, only the dialogue text and timings are kept.
+func convertASSToSRT(assFileName string, srtFileName string) error {
+	ffmpeg, lookErr := exec.LookPath("ffmpeg")
+	if lookErr != nil {
+		return fmt.Errorf("ffmpeg not found: %w", lookErr)
+	}
+	// The conflict policy has already been applied to srtFileName, so let ffmpeg replace it
+	cmd := exec.Command(ffmpeg, "-y", "-loglevel", "error", "-i", assFileName, "-f", "srt", srtFileName)
+	output, cmdErr := cmd.CombinedOutput()
+	if cmdErr != nil {
+		logrus.
+			WithField("cmd", cmd).
+			WithField("assFileName", assFileName).
+			WithError(cmdErr).
+			Error("Error executing ASS to SRT conversion")
+		fmt.Println(string(output))
+		return cmdErr
+	}
+	logrus.
+		WithField("srtFileName", srtFileName).
+		Info("ASS/SSA subtitles converted to SRT")
+	return nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	textunicode "golang.org/x/text/encoding/unicode"
+)
+
+// SRTFixEncodingOptions describes a conversion of a subtitle file to UTF-8
+type SRTFixEncodingOptions struct {
+	InputFileName string
+	From          string
+	NoBackup      bool
+}
+
+// candidateCharset is a legacy single-byte charset considered by detectCharset.
+// Western European comes first and wins ties.
+type candidateCharset struct {
+	Name     string
+	Encoding encoding.Encoding
+}
+
+var candidateCharsets = []candidateCharset{
+	{"windows-1252", charmap.Windows1252},
+	{"windows-1250", charmap.Windows1250},
+	{"windows-1251", charmap.Windows1251},
+	{"windows-1253", charmap.Windows1253},
+	{"windows-1254", charmap.Windows1254},
+	{"windows-1257", charmap.Windows1257},
+}
+
+// unlikelySubtitleRunes show up when text is decoded with the wrong charset
+const unlikelySubtitleRunes = "ÐðÞþÝýÃÂ¤¦¨¯´¸¹²³¼½¾×÷ª"
+
+// scoreDecodedText rates how plausible decoded subtitle text is: words mixing
+// scripts, control characters and unusual symbols are typical of the wrong charset
+func scoreDecodedText(text string) int {
+	score := 0
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		latin, other, accented := 0, 0, 0
+		for _, r := range word {
+			switch {
+			case r < utf8.RuneSelf:
+				latin++
+			case unicode.Is(unicode.Latin, r):
+				latin++
+				accented++
+			default:
+				other++
+			}
+		}
+		switch {
+		case latin > 0 && other > 0:
+			score -= 2
+		case other > 0:
+			score++
+		case accented > 0 && accented < latin:
+			score++
+		case accented > 0:
+			score--
+		}
+	}
+	for _, r := range text {
+		if r == utf8.RuneError || (unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t') {
+			score -= 5
+		} else if strings.ContainsRune(unlikelySubtitleRunes, r) {
+			score -= 2
+		}
+	}
+	return score
+}
+
+// detectCharset returns the most plausible charset of content, which must not be valid UTF-8
+func detectCharset(content []byte) candidateCharset {
+	best := candidateCharsets[0]
+	bestScore := 0
+	for i, candidate := range candidateCharsets {
+		decoded, decodeErr := candidate.Encoding.NewDecoder().Bytes(content)
+		if decodeErr != nil {
+			continue
+		}
+		if score := scoreDecodedText(string(decoded)); i == 0 || score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	return best
+}
+
+// decodeToUTF8 converts content to UTF-8 and names the charset it was read as
+func decodeToUTF8(content []byte, from string) ([]byte, string, error) {
+	if from != "" {
+		fromEncoding, lookupErr := htmlindex.Get(from)
+		if lookupErr != nil {
+			return nil, "", fmt.Errorf("unknown charset %q", from)
+		}
+		decoded, decodeErr := fromEncoding.NewDecoder().Bytes(content)
+		return decoded, from, decodeErr
+	}
+	switch {
+	case bytes.HasPrefix(content, []byte{0xEF, 0xBB, 0xBF}):
+		return content[3:], "utf-8 with BOM", nil
+	case bytes.HasPrefix(content, []byte{0xFF, 0xFE}), bytes.HasPrefix(content, []byte{0xFE, 0xFF}):
+		decoded, decodeErr := textunicode.UTF16(textunicode.LittleEndian, textunicode.ExpectBOM).NewDecoder().Bytes(content)
+		return decoded, "utf-16", decodeErr
+	case utf8.Valid(content):
+		return content, "utf-8", nil
+	}
+	charset := detectCharset(content)
+	decoded, decodeErr := charset.Encoding.NewDecoder().Bytes(content)
+	return decoded, charset.Name, decodeErr
+}
+
+func fixSRTEncoding(options SRTFixEncodingOptions) error {
+	content, readErr := os.ReadFile(options.InputFileName)
+	if readErr != nil {
+		logrus.
+			WithError(readErr).
+			WithField("inputFileName", options.InputFileName).
+			Error("Error reading subtitle file")
+		return readErr
+	}
+	decoded, charset, decodeErr := decodeToUTF8(content, options.From)
+	if decodeErr != nil {
+		logrus.
+			WithError(decodeErr).
+			WithField("charset", charset).
+			Error("Error converting subtitle file to UTF-8")
+		return decodeErr
+	}
+	if bytes.Equal(decoded, content) {
+		logrus.
+			WithField("inputFileName", options.InputFileName).
+			Info("Subtitle file is already UTF-8")
+		return nil
+	}
+	if !options.NoBackup {
+		backupFileName, _, backupNameErr := resolveOutputFileName(options.InputFileName+".bak", ConflictPolicyRename)
+		if backupNameErr != nil {
+			return backupNameErr
+		}
+		if copyErr := copyFile(options.InputFileName, backupFileName); copyErr != nil {
+			logrus.
+				WithError(copyErr).
+				WithField("backupFileName", backupFileName).
+				Error("Error writing backup file")
+			return copyErr
+		}
+		logrus.
+			WithField("backupFileName", backupFileName).
+			Debug("Backup written")
+	}
+	if writeErr := os.WriteFile(options.InputFileName, decoded, 0644); writeErr != nil {
+		logrus.
+			WithError(writeErr).
+			WithField("inputFileName", options.InputFileName).
+			Error("Error writing subtitle file")
+		return writeErr
+	}
+	logrus.
+		WithField("inputFileName", options.InputFileName).
+		WithField("charset", charset).
+		Info("Subtitle file converted to UTF-8")
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+func absolutePath(fileName string) string {
+	if path.IsAbs(fileName) {
+		return fileName
+	}
+	workingDir, wdErr := os.Getwd()
+	if wdErr != nil {
+		return fileName
+	}
+	return path.Join(workingDir, fileName)
+}
+
+func copyFile(src string, dst string) error {
+	sourceFile, openErr := os.Open(src)
+	if openErr != nil {
+		return openErr
+	}
+	defer sourceFile.Close()
+	destFile, createErr := os.Create(dst)
+	if createErr != nil {
+		return createErr
+	}
+	defer destFile.Close()
+	_, copyErr := io.Copy(destFile, sourceFile)
+	return copyErr
+}
+
+func replaceExtension(fileName string, extension string) string {
+	return strings.TrimSuffix(fileName, path.Ext(fileName)) + "." + extension
+}
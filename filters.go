@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	commentaryTrackNamePattern = regexp.MustCompile(`(?i)\b(commentary|commentaire|kommentar|comentario|commento)\b`)
+	sdhTrackNamePattern        = regexp.MustCompile(`(?i)\b(sdh|cc|hi|hoh|hearing[ -]impaired|closed[ -]captions?)\b`)
+)
+
+// TrackFilter decides which subtitle tracks are extracted. Every enabled
+// criterion has to match for a track to be kept.
+type TrackFilter struct {
+	ForcedOnly     bool
+	DefaultOnly    bool
+	SkipCommentary bool
+	SkipSDH        bool
+	MinEntries     int
+	NameMatch      *regexp.Regexp
+	NameExclude    *regexp.Regexp
+	// IgnoreLanguages comes from the config file and applies to every run
+	IgnoreLanguages []string
+}
+
+// SkipReason returns why the track is filtered out, or an empty string when it should be extracted
+func (filter TrackFilter) SkipReason(track MKVTrack) string {
+	if filter.ForcedOnly && !track.Properties.Forced {
+		return "track is not forced"
+	}
+	if filter.DefaultOnly && !track.Properties.Default {
+		return "track is not a default track"
+	}
+	if filter.SkipCommentary && isCommentaryTrack(track) {
+		return "track looks like a commentary"
+	}
+	if filter.SkipSDH && isSDHTrack(track) {
+		return "track looks like SDH"
+	}
+	for _, lang := range filter.IgnoreLanguages {
+		if sameLanguage(track.Properties.Language, lang) {
+			return "language " + track.Properties.Language + " is ignored"
+		}
+	}
+	if filter.NameMatch != nil && !filter.NameMatch.MatchString(track.Properties.TrackName) {
+		return "track name does not match " + filter.NameMatch.String()
+	}
+	if filter.NameExclude != nil && filter.NameExclude.MatchString(track.Properties.TrackName) {
+		return "track name matches " + filter.NameExclude.String()
+	}
+	// mkvmerge only reports num_index_entries for tracks listed in the cues,
+	// so a missing (zero) count is treated as unknown rather than empty
+	if filter.MinEntries > 0 && track.Properties.NumberOfIndexEntries > 0 && track.Properties.NumberOfIndexEntries < filter.MinEntries {
+		return fmt.Sprintf("track has only %d entries", track.Properties.NumberOfIndexEntries)
+	}
+	return ""
+}
+
+func isCommentaryTrack(track MKVTrack) bool {
+	return track.Properties.Commentary || commentaryTrackNamePattern.MatchString(track.Properties.TrackName)
+}
+
+func isSDHTrack(track MKVTrack) bool {
+	return track.Properties.HearingImpaired || sdhTrackNamePattern.MatchString(track.Properties.TrackName)
+}
+
+// compileNamePattern compiles a --name-match / --name-exclude pattern; an empty pattern disables the filter
+func compileNamePattern(option string, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	compiled, compileErr := regexp.Compile(pattern)
+	if compileErr != nil {
+		return nil, fmt.Errorf("invalid %s pattern: %w", option, compileErr)
+	}
+	return compiled, nil
+}
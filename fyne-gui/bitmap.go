@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// previewImageCount is the number of bitmaps shown for image-based subtitle tracks
+const previewImageCount = 4
+
+// imageSubtitleExt returns the file extension of an image-based subtitle codec,
+// or an empty string for other codecs
+func imageSubtitleExt(codec string) string {
+	switch codec {
+	case "hdmv_pgs_subtitle", "HDMV PGS":
+		return "sup"
+	case "vobsub", "VobSub":
+		return "idx"
+	}
+	if isDVBSubtitle(codec) {
+		return "ts"
+	}
+	return ""
+}
+
+// PGS segment types
+const (
+	pgsPaletteSegment = 0x14
+	pgsObjectSegment  = 0x15
+)
+
+// decodePGSImages decodes the first count subtitle bitmaps of a PGS (.sup) stream
+func decodePGSImages(data []byte, count int) ([]image.Image, error) {
+	var images []image.Image
+	var palette [256]color.NRGBA
+	var objData []byte
+	var objWidth, objHeight int
+
+	for pos := 0; pos+13 <= len(data) && len(images) < count; {
+		if data[pos] != 'P' || data[pos+1] != 'G' {
+			return images, fmt.Errorf("Invalid PGS segment at offset %d", pos)
+		}
+		segType := data[pos+10]
+		size := int(binary.BigEndian.Uint16(data[pos+11:]))
+		if pos+13+size > len(data) {
+			break
+		}
+		seg := data[pos+13 : pos+13+size]
+		pos += 13 + size
+
+		switch segType {
+		case pgsPaletteSegment:
+			// Palette ID and version, then entries of ID, Y, Cr, Cb and alpha
+			for i := 2; i+5 <= len(seg); i += 5 {
+				r, g, b := color.YCbCrToRGB(seg[i+1], seg[i+3], seg[i+2])
+				palette[seg[i]] = color.NRGBA{R: r, G: g, B: b, A: seg[i+4]}
+			}
+		case pgsObjectSegment:
+			// Object ID, version and sequence flags, then the RLE data which
+			// may be split over several segments
+			if len(seg) < 4 {
+				continue
+			}
+			flags := seg[3]
+			body := seg[4:]
+			if flags&0x80 != 0 {
+				if len(body) < 7 {
+					continue
+				}
+				objWidth = int(binary.BigEndian.Uint16(body[3:]))
+				objHeight = int(binary.BigEndian.Uint16(body[5:]))
+				objData = append([]byte(nil), body[7:]...)
+			} else {
+				objData = append(objData, body...)
+			}
+			if flags&0x40 != 0 && objWidth > 0 && objHeight > 0 {
+				images = append(images, decodePGSRLE(objData, objWidth, objHeight, &palette))
+				objData = nil
+			}
+		}
+	}
+	return images, nil
+}
+
+// decodePGSRLE decodes the run-length encoded pixels of a PGS object
+func decodePGSRLE(data []byte, width, height int, palette *[256]color.NRGBA) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	x, y := 0, 0
+	fill := func(run int, index byte) {
+		for ; run > 0 && x < width; run-- {
+			img.SetNRGBA(x, y, palette[index])
+			x++
+		}
+	}
+
+	for i := 0; i < len(data) && y < height; {
+		b := data[i]
+		i++
+		if b != 0 {
+			fill(1, b)
+			continue
+		}
+		if i >= len(data) {
+			break
+		}
+		flags := data[i]
+		i++
+		if flags == 0 {
+			// End of line
+			x = 0
+			y++
+			continue
+		}
+
+		run := int(flags & 0x3f)
+		if flags&0x40 != 0 {
+			if i >= len(data) {
+				break
+			}
+			run = run<<8 | int(data[i])
+			i++
+		}
+		var index byte
+		if flags&0x80 != 0 {
+			if i >= len(data) {
+				break
+			}
+			index = data[i]
+			i++
+		}
+		fill(run, index)
+	}
+	return img
+}
+
+// readVobSubPalette reads the 16 color palette of a VobSub .idx file
+func readVobSubPalette(idxPath string) ([16]color.NRGBA, error) {
+	var palette [16]color.NRGBA
+	file, err := os.Open(idxPath)
+	if err != nil {
+		return palette, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "palette:") {
+			continue
+		}
+		for i, entry := range strings.Split(strings.TrimPrefix(line, "palette:"), ",") {
+			if i >= len(palette) {
+				break
+			}
+			rgb, err := strconv.ParseUint(strings.TrimSpace(entry), 16, 32)
+			if err != nil {
+				return palette, fmt.Errorf("Invalid palette entry %q in %s", entry, idxPath)
+			}
+			palette[i] = color.NRGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 255}
+		}
+	}
+	return palette, scanner.Err()
+}
+
+// vobSubPackets collects the SPU packets of the first subtitle stream in a
+// VobSub .sub file, which is an MPEG program stream
+func vobSubPackets(data []byte, count int) [][]byte {
+	var packets [][]byte
+	var current []byte
+	stream := -1
+
+	for pos := 0; pos+6 <= len(data) && len(packets) < count; {
+		if !bytes.HasPrefix(data[pos:], []byte{0, 0, 1}) {
+			pos++
+			continue
+		}
+		streamID := data[pos+3]
+		if streamID == 0xba {
+			// MPEG-2 pack header with stuffing
+			if pos+14 > len(data) {
+				break
+			}
+			pos += 14 + int(data[pos+13]&0x07)
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+4:]))
+		end := pos + 6 + length
+		if end > len(data) {
+			break
+		}
+		if streamID == 0xbd && length > 3 && pos+9+int(data[pos+8]) <= end {
+			// Private stream 1: PES header, then the substream ID and SPU data
+			payload := data[pos+9+int(data[pos+8]) : end]
+			if len(payload) > 1 && (stream == -1 || int(payload[0]) == stream) {
+				stream = int(payload[0])
+				current = append(current, payload[1:]...)
+				if len(current) >= 2 && len(current) >= int(binary.BigEndian.Uint16(current)) {
+					packets = append(packets, current)
+					current = nil
+				}
+			}
+		}
+		pos = end
+	}
+	return packets
+}
+
+// decodeVobSubSPU decodes a VobSub subtitle packet into a bitmap
+func decodeVobSubSPU(spu []byte, palette *[16]color.NRGBA) (image.Image, error) {
+	if len(spu) < 4 {
+		return nil, fmt.Errorf("Subtitle packet too short")
+	}
+	var colors, alphas [4]byte
+	var x1, x2, y1, y2, topOffset, bottomOffset int
+
+	// Walk the control sequences
+	for ctrl := int(binary.BigEndian.Uint16(spu[2:])); ctrl+4 <= len(spu); {
+		next := int(binary.BigEndian.Uint16(spu[ctrl+2:]))
+		i := ctrl + 4
+	commands:
+		for i < len(spu) {
+			cmd := spu[i]
+			i++
+			switch cmd {
+			case 0x03, 0x04:
+				if i+2 > len(spu) {
+					break commands
+				}
+				values := [4]byte{spu[i+1] & 0x0f, spu[i+1] >> 4, spu[i] & 0x0f, spu[i] >> 4}
+				if cmd == 0x03 {
+					colors = values
+				} else {
+					alphas = values
+				}
+				i += 2
+			case 0x05:
+				if i+6 > len(spu) {
+					break commands
+				}
+				x1 = int(spu[i])<<4 | int(spu[i+1])>>4
+				x2 = int(spu[i+1]&0x0f)<<8 | int(spu[i+2])
+				y1 = int(spu[i+3])<<4 | int(spu[i+4])>>4
+				y2 = int(spu[i+4]&0x0f)<<8 | int(spu[i+5])
+				i += 6
+			case 0x06:
+				if i+4 > len(spu) {
+					break commands
+				}
+				topOffset = int(binary.BigEndian.Uint16(spu[i:]))
+				bottomOffset = int(binary.BigEndian.Uint16(spu[i+2:]))
+				i += 4
+			case 0x00, 0x01, 0x02:
+				// Forced, start and stop display carry no arguments
+			default:
+				break commands
+			}
+		}
+		if next <= ctrl {
+			break
+		}
+		ctrl = next
+	}
+
+	width, height := x2-x1+1, y2-y1+1
+	if width <= 0 || height <= 0 || topOffset == 0 {
+		return nil, fmt.Errorf("Subtitle packet has no bitmap")
+	}
+
+	var pixelColors [4]color.NRGBA
+	for i := range pixelColors {
+		c := palette[colors[i]]
+		c.A = alphas[i] * 17
+		pixelColors[i] = c
+	}
+
+	// Even lines come from the top field, odd lines from the bottom field
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	offsets := [2]int{topOffset, bottomOffset}
+	for field := 0; field < 2; field++ {
+		nibble := offsets[field] * 2
+		readNibble := func() int {
+			if nibble/2 >= len(spu) {
+				return 0
+			}
+			b := spu[nibble/2]
+			nibble++
+			if nibble%2 == 1 {
+				return int(b >> 4)
+			}
+			return int(b & 0x0f)
+		}
+
+		for y := field; y < height; y += 2 {
+			for x := 0; x < width; {
+				v := readNibble()
+				if v < 0x4 {
+					v = v<<4 | readNibble()
+					if v < 0x10 {
+						v = v<<4 | readNibble()
+						if v < 0x40 {
+							v = v<<4 | readNibble()
+						}
+					}
+				}
+				run := v >> 2
+				if run == 0 {
+					// Fill to the end of the line
+					run = width - x
+				}
+				for ; run > 0 && x < width; run-- {
+					img.SetNRGBA(x, y, pixelColors[v&0x03])
+					x++
+				}
+			}
+			// Lines start on a byte boundary
+			nibble += nibble % 2
+		}
+	}
+	return img, nil
+}
+
+// decodeVobSubImages decodes the first count subtitle bitmaps of a VobSub .idx/.sub pair
+func decodeVobSubImages(idxPath string, count int) ([]image.Image, error) {
+	palette, err := readVobSubPalette(idxPath)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading VobSub palette: %v", err)
+	}
+	data, err := os.ReadFile(strings.TrimSuffix(idxPath, ".idx") + ".sub")
+	if err != nil {
+		return nil, fmt.Errorf("Error reading VobSub data: %v", err)
+	}
+
+	var images []image.Image
+	for _, spu := range vobSubPackets(data, count) {
+		img, err := decodeVobSubSPU(spu, &palette)
+		if err != nil {
+			continue
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+// onDarkBackground draws a subtitle bitmap over a dark background, as white
+// subtitle text is unreadable on a light theme
+func onDarkBackground(img image.Image) image.Image {
+	bg := image.NewNRGBA(img.Bounds())
+	draw.Draw(bg, bg.Bounds(), image.NewUniform(color.NRGBA{R: 48, G: 48, B: 48, A: 255}), image.Point{}, draw.Src)
+	draw.Draw(bg, bg.Bounds(), img, img.Bounds().Min, draw.Over)
+	return bg
+}
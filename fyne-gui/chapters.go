@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Chapter is a chapter to mux into an MKV file
+type Chapter struct {
+	Start time.Duration
+	Name  string
+}
+
+var (
+	chapterTimeRegex = regexp.MustCompile(`^(\d+):(\d{1,2}):(\d{1,2})(?:[.,](\d{1,9}))?$`)
+	ogmChapterRegex  = regexp.MustCompile(`^CHAPTER(\d+)(NAME)?=(.*)$`)
+)
+
+// parseChapterTime reads a chapter timestamp such as 00:12:34.567 or the
+// nanosecond precision 00:12:34.567000000 of Matroska XML chapters
+func parseChapterTime(text string) (time.Duration, error) {
+	m := chapterTimeRegex.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return 0, errors.New(trf("Invalid chapter time %q, use HH:MM:SS.mmm", text))
+	}
+	hours, _ := strconv.Atoi(m[1])
+	minutes, _ := strconv.Atoi(m[2])
+	seconds, _ := strconv.Atoi(m[3])
+	nanos := 0
+	if m[4] != "" {
+		nanos, _ = strconv.Atoi((m[4] + "000000000")[:9])
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second + time.Duration(nanos), nil
+}
+
+// formatChapterTime writes a chapter timestamp as HH:MM:SS.mmm
+func formatChapterTime(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// parseChapters reads a chapters file in Matroska XML or OGM format
+// (CHAPTER01=00:00:00.000 / CHAPTER01NAME=Intro)
+func parseChapters(data []byte) ([]Chapter, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		return parseXMLChapters(data)
+	}
+	return parseOGMChapters(string(data))
+}
+
+// parseOGMChapters reads chapters in the simple OGM format
+func parseOGMChapters(text string) ([]Chapter, error) {
+	byNumber := map[string]*Chapter{}
+	var numbers []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		m := ogmChapterRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		chapter, ok := byNumber[m[1]]
+		if !ok {
+			chapter = &Chapter{}
+			byNumber[m[1]] = chapter
+			numbers = append(numbers, m[1])
+		}
+		if m[2] == "NAME" {
+			chapter.Name = m[3]
+			continue
+		}
+		start, err := parseChapterTime(m[3])
+		if err != nil {
+			return nil, err
+		}
+		chapter.Start = start
+	}
+	if len(numbers) == 0 {
+		return nil, errors.New(tr("No chapters found in the file"))
+	}
+
+	chapters := make([]Chapter, 0, len(numbers))
+	for _, number := range numbers {
+		chapters = append(chapters, *byNumber[number])
+	}
+	return chapters, nil
+}
+
+// parseXMLChapters reads the top-level chapters of the first edition of a
+// Matroska XML chapters file
+func parseXMLChapters(data []byte) ([]Chapter, error) {
+	var doc struct {
+		Editions []struct {
+			Atoms []struct {
+				Start    string `xml:"ChapterTimeStart"`
+				Displays []struct {
+					String string `xml:"ChapterString"`
+				} `xml:"ChapterDisplay"`
+			} `xml:"ChapterAtom"`
+		} `xml:"EditionEntry"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("Error parsing chapters XML: %v", err)
+	}
+	if len(doc.Editions) == 0 || len(doc.Editions[0].Atoms) == 0 {
+		return nil, errors.New(tr("No chapters found in the file"))
+	}
+
+	var chapters []Chapter
+	for _, atom := range doc.Editions[0].Atoms {
+		start, err := parseChapterTime(atom.Start)
+		if err != nil {
+			return nil, err
+		}
+		chapter := Chapter{Start: start}
+		if len(atom.Displays) > 0 {
+			chapter.Name = atom.Displays[0].String
+		}
+		chapters = append(chapters, chapter)
+	}
+	return chapters, nil
+}
+
+// formatOGMChapters writes chapters, in order of their start, in the OGM
+// format mkvmerge reads with --chapters
+func formatOGMChapters(chapters []Chapter) string {
+	sorted := append([]Chapter(nil), chapters...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var b strings.Builder
+	for i, chapter := range sorted {
+		fmt.Fprintf(&b, "CHAPTER%02d=%s\n", i+1, formatChapterTime(chapter.Start))
+		fmt.Fprintf(&b, "CHAPTER%02dNAME=%s\n", i+1, chapter.Name)
+	}
+	return b.String()
+}
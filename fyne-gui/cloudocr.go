@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// cloudLanguages maps Tesseract languages to the BCP-47 codes the cloud OCR
+// providers take as a hint; other languages are detected by the provider
+var cloudLanguages = map[string]string{
+	"eng": "en", "fra": "fr", "deu": "de", "spa": "es", "ita": "it",
+	"por": "pt", "nld": "nl", "rus": "ru", "jpn": "ja", "chi_sim": "zh-Hans",
+	"chi_tra": "zh-Hant", "kor": "ko", "ces": "cs", "pol": "pl", "swe": "sv",
+	"dan": "da", "nor": "nb", "fin": "fi", "hun": "hu", "tur": "tr",
+	"ara": "ar", "ell": "el", "heb": "he", "hin": "hi", "ukr": "uk",
+	"ron": "ro", "bul": "bg", "hrv": "hr", "srp": "sr", "slk": "sk",
+	"slv": "sl", "tha": "th", "vie": "vi", "ind": "id",
+}
+
+// cloudLanguage returns the language hint for the Tesseract languages lang,
+// of several the first, or "" to let the provider detect it
+func cloudLanguage(lang string) string {
+	lang, _, _ = strings.Cut(lang, "+")
+	return cloudLanguages[lang]
+}
+
+// cloudOCRImage encodes a subtitle bitmap, prepared as set by opts, as the
+// PNG sent to a cloud OCR provider
+func cloudOCRImage(cue BitmapCue, opts OCROptions) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, ocrImage(cue.Image, opts.Preprocess)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// cloudOCRError returns the error of a failed request to a cloud OCR
+// provider, with the message of its response
+func cloudOCRError(provider string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var result struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &result) == nil && result.Error.Message != "" {
+		message = result.Error.Message
+	}
+	return fmt.Errorf("%s returned %s: %s", provider, resp.Status, message)
+}
+
+// googleVisionBatch is how many bitmaps one Google Vision request reads, the
+// most the API takes
+const googleVisionBatch = 16
+
+// googleVisionURL is the endpoint of Google Cloud Vision's image annotation
+const googleVisionURL = "https://vision.googleapis.com/v1/images:annotate"
+
+// googleVisionEngine reads bitmaps with the Google Cloud Vision API, using the
+// API key set in the Settings tab. Bitmaps are sent to Google.
+type googleVisionEngine struct{}
+
+func (googleVisionEngine) Name() string {
+	return "Google Vision"
+}
+
+func (googleVisionEngine) Recognize(ctx context.Context, cues []BitmapCue, opts OCROptions, onProgress func(done, total int)) ([]string, []float64, error) {
+	key := fyne.CurrentApp().Preferences().String("google_vision_key")
+	if key == "" {
+		return nil, nil, fmt.Errorf("Google Vision needs an API key; set it in the Cloud OCR settings")
+	}
+
+	type imageRequest struct {
+		Image struct {
+			Content string `json:"content"`
+		} `json:"image"`
+		Features []struct {
+			Type string `json:"type"`
+		} `json:"features"`
+		ImageContext struct {
+			LanguageHints []string `json:"languageHints,omitempty"`
+		} `json:"imageContext"`
+	}
+	var hints []string
+	if lang := cloudLanguage(opts.Lang); lang != "" {
+		hints = []string{lang}
+	}
+
+	texts := make([]string, len(cues))
+	confidences := make([]float64, len(cues))
+	for first := 0; first < len(cues); first += googleVisionBatch {
+		last := min(first+googleVisionBatch, len(cues))
+		var batch struct {
+			Requests []imageRequest `json:"requests"`
+		}
+		for i := first; i < last; i++ {
+			data, err := cloudOCRImage(cues[i], opts)
+			if err != nil {
+				return nil, nil, err
+			}
+			var request imageRequest
+			request.Image.Content = base64.StdEncoding.EncodeToString(data)
+			// Document detection also returns the confidence of the text
+			request.Features = []struct {
+				Type string `json:"type"`
+			}{{Type: "DOCUMENT_TEXT_DETECTION"}}
+			request.ImageContext.LanguageHints = hints
+			batch.Requests = append(batch.Requests, request)
+		}
+		body, err := json.Marshal(batch)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, googleVisionURL+"?key="+url.QueryEscape(key), bytes.NewReader(body))
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return texts[:first], confidences[:first], ctx.Err()
+			}
+			return nil, nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			err := cloudOCRError("Google Vision", resp)
+			resp.Body.Close()
+			return nil, nil, err
+		}
+		var result struct {
+			Responses []struct {
+				FullTextAnnotation struct {
+					Text  string `json:"text"`
+					Pages []struct {
+						Confidence float64 `json:"confidence"`
+					} `json:"pages"`
+				} `json:"fullTextAnnotation"`
+				Error struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			} `json:"responses"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error reading the Google Vision response: %v", err)
+		}
+		for j, response := range result.Responses {
+			i := first + j
+			if i >= last {
+				break
+			}
+			if response.Error.Message != "" {
+				return nil, nil, fmt.Errorf("Google Vision could not read subtitle %d: %s", i+1, response.Error.Message)
+			}
+			annotation := response.FullTextAnnotation
+			texts[i] = opts.Charset.filter(strings.TrimSpace(annotation.Text))
+			if texts[i] == "" || len(annotation.Pages) == 0 {
+				continue
+			}
+			var total float64
+			for _, page := range annotation.Pages {
+				total += page.Confidence
+			}
+			confidences[i] = total / float64(len(annotation.Pages)) * 100
+		}
+		onProgress(last, len(cues))
+	}
+	return texts, confidences, nil
+}
+
+// azureReadWorkers is how many bitmaps are read by Azure at a time, which
+// is kept low for the request rate of the free tier
+const azureReadWorkers = 4
+
+// azureReadRetries is how often a request Azure turned down for the request
+// rate is sent again
+const azureReadRetries = 5
+
+// azureReadEngine reads bitmaps with the Read API of Azure AI Vision, using
+// the endpoint and key set in the Settings tab. Bitmaps are sent to
+// Microsoft.
+type azureReadEngine struct{}
+
+func (azureReadEngine) Name() string {
+	return "Azure Read"
+}
+
+func (azureReadEngine) Recognize(ctx context.Context, cues []BitmapCue, opts OCROptions, onProgress func(done, total int)) ([]string, []float64, error) {
+	prefs := fyne.CurrentApp().Preferences()
+	endpoint := strings.TrimRight(strings.TrimSpace(prefs.String("azure_read_endpoint")), "/")
+	key := prefs.String("azure_read_key")
+	if endpoint == "" || key == "" {
+		return nil, nil, fmt.Errorf("Azure Read needs an endpoint and a key; set them in the Cloud OCR settings")
+	}
+	analyzeURL := endpoint + "/vision/v3.2/read/analyze"
+	if lang := cloudLanguage(opts.Lang); lang != "" {
+		analyzeURL += "?language=" + url.QueryEscape(lang)
+	}
+
+	texts := make([]string, len(cues))
+	confidences := make([]float64, len(cues))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	done := 0
+	read := make([]bool, len(cues))
+	next := make(chan int)
+	for range min(azureReadWorkers, len(cues)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				text, confidence, err := azureRead(ctx, analyzeURL, key, cues[i], opts)
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil && ctx.Err() == nil {
+						firstErr = fmt.Errorf("Azure Read could not read subtitle %d: %v", i+1, err)
+						cancel()
+					}
+				} else {
+					texts[i], confidences[i] = opts.Charset.filter(text), confidence
+					read[i] = true
+					done++
+					onProgress(done, len(cues))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for i := range cues {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		n := 0
+		for n < len(read) && read[n] {
+			n++
+		}
+		return texts[:n], confidences[:n], err
+	}
+	return texts, confidences, nil
+}
+
+// azureRead reads one bitmap with the Azure Read API: the bitmap is submitted
+// for analysis, then its result is polled until the analysis is done
+func azureRead(ctx context.Context, analyzeURL, key string, cue BitmapCue, opts OCROptions) (string, float64, error) {
+	data, err := cloudOCRImage(cue, opts)
+	if err != nil {
+		return "", 0, err
+	}
+	resp, err := azureRequest(ctx, http.MethodPost, analyzeURL, key, data)
+	if err != nil {
+		return "", 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return "", 0, fmt.Errorf("unexpected response %s", resp.Status)
+	}
+	operation := resp.Header.Get("Operation-Location")
+	if operation == "" {
+		return "", 0, fmt.Errorf("no Operation-Location in the response")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", 0, ctx.Err()
+		case <-time.After(time.Second):
+		}
+		resp, err := azureRequest(ctx, http.MethodGet, operation, key, nil)
+		if err != nil {
+			return "", 0, err
+		}
+		var result struct {
+			Status        string `json:"status"`
+			AnalyzeResult struct {
+				ReadResults []struct {
+					Lines []struct {
+						Text  string `json:"text"`
+						Words []struct {
+							Confidence float64 `json:"confidence"`
+						} `json:"words"`
+					} `json:"lines"`
+				} `json:"readResults"`
+			} `json:"analyzeResult"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return "", 0, err
+		}
+		switch result.Status {
+		case "succeeded":
+		case "failed":
+			return "", 0, fmt.Errorf("the analysis failed")
+		default:
+			continue
+		}
+
+		var lines []string
+		var total float64
+		words := 0
+		for _, page := range result.AnalyzeResult.ReadResults {
+			for _, line := range page.Lines {
+				lines = append(lines, line.Text)
+				for _, word := range line.Words {
+					total += word.Confidence
+					words++
+				}
+			}
+		}
+		if words == 0 {
+			return strings.Join(lines, "\n"), 0, nil
+		}
+		return strings.Join(lines, "\n"), total / float64(words) * 100, nil
+	}
+}
+
+// azureRequest sends a request to the Azure Read API, sending it again when
+// Azure turns it down for the request rate, and returns the response of a
+// request that succeeded
+func azureRequest(ctx context.Context, method, requestURL, key string, body []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Ocp-Apim-Subscription-Key", key)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/octet-stream")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < azureReadRetries {
+			resp.Body.Close()
+			wait := time.Second
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+				wait = time.Duration(seconds) * time.Second
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+		if resp.StatusCode >= 300 {
+			defer resp.Body.Close()
+			return nil, cloudOCRError("Azure Read", resp)
+		}
+		return resp, nil
+	}
+}
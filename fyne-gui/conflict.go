@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Answers to an output file that already exists
+const (
+	ConflictOverwrite = "overwrite"
+	ConflictSkip      = "skip"
+	ConflictRename    = "rename"
+)
+
+// codecFileExt returns the file extension mkvextract output gets for a codec
+func codecFileExt(codec string) string {
+	switch {
+	case strings.Contains(codec, "SubRip") || strings.Contains(codec, "subrip") || strings.Contains(codec, "SRT") || strings.Contains(codec, "srt"):
+		return "srt"
+	case codec == "hdmv_pgs_subtitle" || codec == "HDMV PGS":
+		return "sup"
+	case codec == "ass" || codec == "ssa" || codec == "ASS" || codec == "SSA":
+		return "ass"
+	case codec == "vobsub" || codec == "VobSub":
+		return "idx"
+	case isDVBSubtitle(codec):
+		// DVB subtitles are extracted into a transport stream with ffmpeg
+		return "ts"
+	}
+	// Use lowercase codec name as fallback but remove any slashes
+	return strings.ToLower(strings.ReplaceAll(codec, "/", "_"))
+}
+
+// outputFileExt returns the extension of the file a track ends up in,
+// taking conversion to SRT into account
+func outputFileExt(t *TrackItem) string {
+	if t.ConvertOCR != nil && t.ConvertOCR.Checked {
+		return "srt"
+	}
+	return codecFileExt(t.Codec)
+}
+
+// uniqueOutputName appends a number to name until no file with the extension
+// exists in dir
+func uniqueOutputName(dir, name, ext string) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)", name, n)
+		if _, err := os.Stat(filepath.Join(dir, candidate+"."+ext)); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+// conflictResolver asks what to do with output files that already exist,
+// remembering an answer the user applied to all files of the run
+type conflictResolver struct {
+	w        fyne.Window
+	mu       sync.Mutex
+	applyAll string
+}
+
+// Reset forgets the answer applied to all files, at the start of a run
+func (r *conflictResolver) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.applyAll = ""
+}
+
+// Resolve returns ConflictOverwrite when path does not exist yet, and asks the
+// user otherwise. It blocks the worker goroutine until answered; a cancelled
+// run skips the file.
+func (r *conflictResolver) Resolve(ctx context.Context, path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return ConflictOverwrite
+	}
+	r.mu.Lock()
+	answer := r.applyAll
+	r.mu.Unlock()
+	if answer != "" {
+		return answer
+	}
+
+	answered := make(chan string, 1)
+	fyne.Do(func() {
+		applyAllCheck := widget.NewCheck(tr("Apply to all"), nil)
+		message := widget.NewLabel(trf("%s already exists.", filepath.Base(path)))
+		var d *dialog.CustomDialog
+		answerBtn := func(label, answer string) *widget.Button {
+			return widget.NewButton(label, func() {
+				if applyAllCheck.Checked {
+					r.mu.Lock()
+					r.applyAll = answer
+					r.mu.Unlock()
+				}
+				d.Hide()
+				answered <- answer
+			})
+		}
+		d = dialog.NewCustomWithoutButtons(tr("File Exists"), container.NewVBox(message, applyAllCheck), r.w)
+		d.SetButtons([]fyne.CanvasObject{
+			answerBtn(tr("Skip"), ConflictSkip),
+			answerBtn(tr("Rename"), ConflictRename),
+			answerBtn(tr("Overwrite"), ConflictOverwrite),
+		})
+		d.Show()
+	})
+
+	select {
+	case answer := <-answered:
+		return answer
+	case <-ctx.Done():
+		return ConflictSkip
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// errFreeSpaceUnknown is returned where the free disk space cannot be read
+var errFreeSpaceUnknown = errors.New("free disk space is unknown on this platform")
+
+// checkFreeSpace returns an error when the disk holding dir has less than
+// needed bytes available. Platforms that cannot tell pass the check.
+func checkFreeSpace(dir string, needed int64) error {
+	free, err := freeDiskSpace(dir)
+	if errors.Is(err, errFreeSpaceUnknown) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if free < uint64(needed) {
+		return errors.New(trf("Not enough free disk space in %s: %s needed, %s available", dir, formatSize(needed), formatSize(int64(free))))
+	}
+	return nil
+}
+
+// filesSize returns the total size of the files, skipping any that cannot be read
+func filesSize(paths ...string) int64 {
+	var total int64
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// existingDir returns dir, or its nearest parent that exists, so the free
+// space of an output folder can be read before it is created
+func existingDir(dir string) string {
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
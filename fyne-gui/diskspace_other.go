@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package main
+
+// freeDiskSpace cannot read the free disk space on this platform
+func freeDiskSpace(dir string) (uint64, error) {
+	return 0, errFreeSpaceUnknown
+}
@@ -0,0 +1,582 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DVB subtitle segment types
+const (
+	dvbPageSegment    = 0x10
+	dvbRegionSegment  = 0x11
+	dvbCLUTSegment    = 0x12
+	dvbObjectSegment  = 0x13
+	dvbDisplaySegment = 0x14
+)
+
+// isDVBSubtitle reports whether a codec is DVB bitmap subtitles, as found in
+// remuxed TV recordings
+func isDVBSubtitle(codec string) bool {
+	return strings.Contains(strings.ToLower(codec), "dvb")
+}
+
+// extractDVBSubtitles copies a DVB subtitle track of an MKV file into an
+// MPEG transport stream with ffmpeg, as mkvextract cannot extract them. The
+// stream keeps the timestamps of the MKV file.
+func extractDVBSubtitles(ctx context.Context, mkvPath string, trackID int, tsPath string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-y", "-nostdin", "-copyts", "-i", mkvPath,
+		"-map", fmt.Sprintf("0:%d", trackID), "-c", "copy", "-muxdelay", "0", "-muxpreload", "0", "-f", "mpegts", tsPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return output, fmt.Errorf("Error running ffmpeg: %v", err)
+	}
+	return output, nil
+}
+
+// dvbPES is a PES packet of DVB subtitles with its presentation time
+type dvbPES struct {
+	PTS     time.Duration
+	Payload []byte
+}
+
+// readDVBPES collects the PES packets of private stream 1, which carries DVB
+// subtitles, from an MPEG transport stream
+func readDVBPES(data []byte) ([]dvbPES, error) {
+	const packetSize = 188
+	var packets []dvbPES
+	pending := map[int][]byte{}
+	flush := func(pid int) {
+		pes := pending[pid]
+		delete(pending, pid)
+		if len(pes) < 9 || pes[0] != 0 || pes[1] != 0 || pes[2] != 1 || pes[3] != 0xbd {
+			return
+		}
+		if n := int(binary.BigEndian.Uint16(pes[4:])); n > 0 && 6+n < len(pes) {
+			pes = pes[:6+n]
+		}
+		start := 9 + int(pes[8])
+		if start > len(pes) {
+			return
+		}
+		var pts time.Duration
+		if pes[7]&0x80 != 0 && len(pes) >= 14 {
+			p := pes[9:]
+			ticks := int64(p[0]>>1&0x07)<<30 | int64(p[1])<<22 | int64(p[2]>>1)<<15 | int64(p[3])<<7 | int64(p[4]>>1)
+			pts = time.Duration(ticks) * time.Second / 90000
+		}
+		packets = append(packets, dvbPES{PTS: pts, Payload: pes[start:]})
+	}
+
+	for pos := 0; pos+packetSize <= len(data); pos += packetSize {
+		p := data[pos : pos+packetSize]
+		if p[0] != 0x47 {
+			return packets, fmt.Errorf("Invalid transport stream packet at offset %d", pos)
+		}
+		pid := int(p[1]&0x1f)<<8 | int(p[2])
+		payload := p[4:]
+		if p[3]&0x20 != 0 {
+			// Skip the adaptation field
+			if 5+int(p[4]) > len(p) {
+				continue
+			}
+			payload = p[5+int(p[4]):]
+		}
+		if p[3]&0x10 == 0 {
+			continue
+		}
+		if p[1]&0x40 != 0 {
+			flush(pid)
+			pending[pid] = append([]byte(nil), payload...)
+		} else if pes, ok := pending[pid]; ok {
+			pending[pid] = append(pes, payload...)
+		}
+	}
+	for pid := range pending {
+		flush(pid)
+	}
+	return packets, nil
+}
+
+// dvbPlacement places a region on the page or an object in a region
+type dvbPlacement struct {
+	ID   int
+	X, Y int
+}
+
+// dvbRegion is a rectangle of the page that objects are drawn in
+type dvbRegion struct {
+	Width, Height int
+	Depth         int // Bits per pixel: 2, 4 or 8
+	CLUT          byte
+	Fill          byte // Pixel code the region is filled with, 0 when not filled
+	Objects       []dvbPlacement
+}
+
+// dvbObject is the run-length coded pixel data of an object, per field
+type dvbObject struct {
+	Top, Bottom []byte
+}
+
+// dvbMaps are the tables that map pixel codes to deeper regions
+type dvbMaps struct {
+	TwoToFour   [4]byte
+	TwoToEight  [4]byte
+	FourToEight [16]byte
+}
+
+// defaultDVBMaps are the map tables used until an object defines its own
+var defaultDVBMaps = dvbMaps{
+	TwoToFour:   [4]byte{0x0, 0x7, 0x8, 0xf},
+	TwoToEight:  [4]byte{0x00, 0x77, 0x88, 0xff},
+	FourToEight: [16]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+}
+
+// parseDVBCues decodes the subtitles of DVB subtitle PES packets. A
+// subtitle is shown until the next page, for at most its page time-out.
+func parseDVBCues(packets []dvbPES) []BitmapCue {
+	var cues []BitmapCue
+	regions := map[int]*dvbRegion{}
+	objects := map[int]*dvbObject{}
+	cluts := map[byte]*[256]color.NRGBA{}
+	screenHeight := 576
+
+	for _, pes := range packets {
+		data := pes.Payload
+		// Data identifier and subtitle stream ID
+		if len(data) < 2 || data[0] != 0x20 {
+			continue
+		}
+		var page []dvbPlacement
+		var timeout time.Duration
+		pageSeen := false
+		for pos := 2; pos+6 <= len(data) && data[pos] == 0x0f; {
+			segType := data[pos+1]
+			size := int(binary.BigEndian.Uint16(data[pos+4:]))
+			if pos+6+size > len(data) {
+				break
+			}
+			seg := data[pos+6 : pos+6+size]
+			pos += 6 + size
+
+			switch segType {
+			case dvbPageSegment:
+				if len(seg) < 2 {
+					continue
+				}
+				pageSeen = true
+				timeout = time.Duration(seg[0]) * time.Second
+				// A mode change starts over, dropping the regions and objects before it
+				if seg[1]>>2&0x03 == 2 {
+					regions = map[int]*dvbRegion{}
+					objects = map[int]*dvbObject{}
+				}
+				page = nil
+				for i := 2; i+6 <= len(seg); i += 6 {
+					page = append(page, dvbPlacement{
+						ID: int(seg[i]),
+						X:  int(binary.BigEndian.Uint16(seg[i+2:])),
+						Y:  int(binary.BigEndian.Uint16(seg[i+4:])),
+					})
+				}
+			case dvbRegionSegment:
+				// Depth codes 1 to 3 are 2, 4 and 8 bits per pixel, the others are reserved
+				depthCode := seg[6] >> 2 & 0x07
+				if len(seg) < 10 || depthCode < 1 || depthCode > 3 {
+					continue
+				}
+				region := &dvbRegion{
+					Width:  int(binary.BigEndian.Uint16(seg[2:])),
+					Height: int(binary.BigEndian.Uint16(seg[4:])),
+					Depth:  1 << depthCode,
+					CLUT:   seg[7],
+				}
+				if seg[1]&0x08 != 0 {
+					switch region.Depth {
+					case 8:
+						region.Fill = seg[8]
+					case 4:
+						region.Fill = seg[9] >> 4
+					default:
+						region.Fill = seg[9] >> 2 & 0x03
+					}
+				}
+				for i := 10; i+6 <= len(seg); {
+					objectType := seg[i+2] >> 6
+					region.Objects = append(region.Objects, dvbPlacement{
+						ID: int(binary.BigEndian.Uint16(seg[i:])),
+						X:  int(binary.BigEndian.Uint16(seg[i+2:]) & 0x0fff),
+						Y:  int(binary.BigEndian.Uint16(seg[i+4:]) & 0x0fff),
+					})
+					// Character objects carry their colors
+					if objectType == 1 || objectType == 2 {
+						i += 8
+					} else {
+						i += 6
+					}
+				}
+				regions[int(seg[0])] = region
+			case dvbCLUTSegment:
+				if len(seg) < 2 {
+					continue
+				}
+				clut, ok := cluts[seg[0]]
+				if !ok {
+					clut = &[256]color.NRGBA{}
+					cluts[seg[0]] = clut
+				}
+				for i := 2; i+2 <= len(seg); {
+					id := seg[i]
+					var y, cr, cb, t byte
+					if seg[i+1]&0x01 != 0 {
+						if i+6 > len(seg) {
+							break
+						}
+						y, cr, cb, t = seg[i+2], seg[i+3], seg[i+4], seg[i+5]
+						i += 6
+					} else {
+						if i+4 > len(seg) {
+							break
+						}
+						v := binary.BigEndian.Uint16(seg[i+2:])
+						y, cr, cb, t = byte(v>>10)<<2, byte(v>>6&0x0f)<<4, byte(v>>2&0x0f)<<4, byte(v&0x03)<<6
+						i += 4
+					}
+					// A luma of 0 makes the entry fully transparent
+					if y == 0 {
+						clut[id] = color.NRGBA{}
+						continue
+					}
+					r, g, b := color.YCbCrToRGB(y, cb, cr)
+					clut[id] = color.NRGBA{R: r, G: g, B: b, A: 255 - t}
+				}
+			case dvbObjectSegment:
+				// Only objects coded as pixels are drawn, not character strings
+				if len(seg) < 7 || seg[2]>>2&0x03 != 0 {
+					continue
+				}
+				topLen := int(binary.BigEndian.Uint16(seg[3:]))
+				bottomLen := int(binary.BigEndian.Uint16(seg[5:]))
+				if 7+topLen+bottomLen > len(seg) {
+					continue
+				}
+				object := &dvbObject{Top: seg[7 : 7+topLen], Bottom: seg[7+topLen : 7+topLen+bottomLen]}
+				// Without a bottom field, the top field is repeated
+				if bottomLen == 0 {
+					object.Bottom = object.Top
+				}
+				objects[int(binary.BigEndian.Uint16(seg))] = object
+			case dvbDisplaySegment:
+				if len(seg) >= 5 {
+					screenHeight = int(binary.BigEndian.Uint16(seg[3:])) + 1
+				}
+			}
+		}
+		if !pageSeen {
+			continue
+		}
+
+		// Every page replaces the subtitle shown before it
+		if n := len(cues); n > 0 && (cues[n-1].End == 0 || cues[n-1].End > pes.PTS) {
+			cues[n-1].End = pes.PTS
+		}
+		img, area := composeDVBPage(page, regions, objects, cluts)
+		if img == nil {
+			continue
+		}
+		cue := BitmapCue{Start: pes.PTS, Image: img, Top: area.Min.Y+area.Max.Y < screenHeight}
+		if timeout > 0 {
+			cue.End = pes.PTS + timeout
+		}
+		cues = append(cues, cue)
+	}
+
+	if n := len(cues); n > 0 && cues[n-1].End == 0 {
+		cues[n-1].End = cues[n-1].Start + defaultCueDuration
+	}
+	return cues
+}
+
+// composeDVBPage draws the regions of a page into one bitmap that covers
+// them all, with the screen area it covers, or returns nil when nothing of
+// the page is visible
+func composeDVBPage(page []dvbPlacement, regions map[int]*dvbRegion, objects map[int]*dvbObject, cluts map[byte]*[256]color.NRGBA) (image.Image, image.Rectangle) {
+	var bounds image.Rectangle
+	type drawnRegion struct {
+		pixels []byte
+		region *dvbRegion
+		origin image.Point
+	}
+	var drawn []drawnRegion
+	for _, p := range page {
+		region, ok := regions[p.ID]
+		if !ok || region.Width == 0 || region.Height == 0 || cluts[region.CLUT] == nil || len(region.Objects) == 0 {
+			continue
+		}
+		pixels := bytes.Repeat([]byte{region.Fill}, region.Width*region.Height)
+		for _, o := range region.Objects {
+			if object, ok := objects[o.ID]; ok {
+				decodeDVBObject(object, pixels, region.Width, region.Height, region.Depth, o.X, o.Y)
+			}
+		}
+		origin := image.Pt(p.X, p.Y)
+		drawn = append(drawn, drawnRegion{pixels: pixels, region: region, origin: origin})
+		bounds = bounds.Union(image.Rectangle{Min: origin, Max: origin.Add(image.Pt(region.Width, region.Height))})
+	}
+	if len(drawn) == 0 {
+		return nil, bounds
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	visible := false
+	for _, d := range drawn {
+		clut := cluts[d.region.CLUT]
+		offset := d.origin.Sub(bounds.Min)
+		for y := 0; y < d.region.Height; y++ {
+			for x := 0; x < d.region.Width; x++ {
+				if c := clut[d.pixels[y*d.region.Width+x]]; c.A > 0 {
+					img.SetNRGBA(x+offset.X, y+offset.Y, c)
+					visible = true
+				}
+			}
+		}
+	}
+	if !visible {
+		return nil, bounds
+	}
+	return img, bounds
+}
+
+// decodeDVBObject draws the pixel codes of an object into the pixels of a
+// region, at x, y. Even lines come from the top field, odd lines from the
+// bottom field.
+func decodeDVBObject(object *dvbObject, pixels []byte, width, height, depth, x, y int) {
+	for field, data := range [][]byte{object.Top, object.Bottom} {
+		maps := defaultDVBMaps
+		line, col := y+field, x
+		emit := func(code byte, run int) {
+			for ; run > 0; run-- {
+				if line < height && col < width {
+					pixels[line*width+col] = code
+				}
+				col++
+			}
+		}
+		for i := 0; i < len(data); {
+			dataType := data[i]
+			i++
+			switch dataType {
+			case 0x10, 0x11, 0x12:
+				bits := map[byte]int{0x10: 2, 0x11: 4, 0x12: 8}[dataType]
+				i += decodeDVBPixelString(data[i:], bits, func(code byte, run int) {
+					emit(mapDVBPixel(code, bits, depth, &maps), run)
+				})
+			case 0x20:
+				if i+2 > len(data) {
+					return
+				}
+				for j := range maps.TwoToFour {
+					maps.TwoToFour[j] = data[i+j/2] >> (4 - 4*(j%2)) & 0x0f
+				}
+				i += 2
+			case 0x21:
+				if i+4 > len(data) {
+					return
+				}
+				copy(maps.TwoToEight[:], data[i:i+4])
+				i += 4
+			case 0x22:
+				if i+16 > len(data) {
+					return
+				}
+				copy(maps.FourToEight[:], data[i:i+16])
+				i += 16
+			case 0xf0:
+				// End of object line
+				line += 2
+				col = x
+			default:
+				i = len(data)
+			}
+		}
+	}
+}
+
+// mapDVBPixel maps a pixel code of a string of bits per pixel to the depth of
+// its region
+func mapDVBPixel(code byte, bits, depth int, maps *dvbMaps) byte {
+	switch {
+	case bits == depth:
+		return code
+	case bits == 2 && depth == 4:
+		return maps.TwoToFour[code&0x03]
+	case bits == 2 && depth == 8:
+		return maps.TwoToEight[code&0x03]
+	case bits == 4 && depth == 8:
+		return maps.FourToEight[code&0x0f]
+	case bits < depth:
+		return code
+	}
+	// Deeper codes in a shallower region keep their most significant bits
+	return code >> (bits - depth)
+}
+
+// dvbBitReader reads a run-length coded pixel string bit by bit
+type dvbBitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *dvbBitReader) read(n int) int {
+	v := 0
+	for ; n > 0; n-- {
+		bit := 0
+		if r.pos/8 < len(r.data) {
+			bit = int(r.data[r.pos/8]>>(7-r.pos%8)) & 1
+		}
+		v = v<<1 | bit
+		r.pos++
+	}
+	return v
+}
+
+func (r *dvbBitReader) done() bool {
+	return r.pos/8 >= len(r.data)
+}
+
+// decodeDVBPixelString decodes a 2, 4 or 8 bit per pixel code string,
+// calling emit for every run of pixels, and returns the bytes it took
+func decodeDVBPixelString(data []byte, bits int, emit func(code byte, run int)) int {
+	r := &dvbBitReader{data: data}
+	for !r.done() {
+		if code := r.read(bits); code != 0 {
+			emit(byte(code), 1)
+			continue
+		}
+		if end := decodeDVBRun(r, bits, emit); end {
+			break
+		}
+	}
+	return (r.pos + 7) / 8
+}
+
+// decodeDVBRun decodes the run that follows a zero pixel code in a pixel
+// string and reports whether it ends the string
+func decodeDVBRun(r *dvbBitReader, bits int, emit func(code byte, run int)) bool {
+	switch bits {
+	case 2:
+		if r.read(1) == 1 {
+			run := r.read(3) + 3
+			emit(byte(r.read(2)), run)
+			return false
+		}
+		if r.read(1) == 1 {
+			emit(0, 1)
+			return false
+		}
+		switch r.read(2) {
+		case 0:
+			return true
+		case 1:
+			emit(0, 2)
+		case 2:
+			run := r.read(4) + 12
+			emit(byte(r.read(2)), run)
+		case 3:
+			run := r.read(8) + 29
+			emit(byte(r.read(2)), run)
+		}
+	case 4:
+		if r.read(1) == 0 {
+			run := r.read(3)
+			if run == 0 {
+				return true
+			}
+			emit(0, run+2)
+			return false
+		}
+		if r.read(1) == 0 {
+			run := r.read(2) + 4
+			emit(byte(r.read(4)), run)
+			return false
+		}
+		switch r.read(2) {
+		case 0:
+			emit(0, 1)
+		case 1:
+			emit(0, 2)
+		case 2:
+			run := r.read(4) + 9
+			emit(byte(r.read(4)), run)
+		case 3:
+			run := r.read(8) + 25
+			emit(byte(r.read(4)), run)
+		}
+	default:
+		if r.read(1) == 0 {
+			run := r.read(7)
+			if run == 0 {
+				return true
+			}
+			emit(0, run)
+			return false
+		}
+		run := r.read(7)
+		emit(byte(r.read(8)), run)
+	}
+	return false
+}
+
+// decodeDVBImages decodes the first count subtitle bitmaps of a transport
+// stream of DVB subtitles, for the preview
+func decodeDVBImages(tsPath string, count int) ([]image.Image, error) {
+	data, err := os.ReadFile(tsPath)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading extracted track: %v", err)
+	}
+	packets, err := readDVBPES(data)
+	if len(packets) == 0 && err != nil {
+		return nil, err
+	}
+	var images []image.Image
+	for _, cue := range parseDVBCues(packets) {
+		if len(images) == count {
+			break
+		}
+		images = append(images, cue.Image)
+	}
+	return images, nil
+}
+
+// convertDVBSubToSRT recognizes the text of every subtitle of a transport
+// stream of DVB subtitles with the OCR engine of opts and writes it as SRT,
+// or reuses the cached result of the same stream. onProgress is called
+// after every subtitle with the number done and the total.
+func convertDVBSubToSRT(ctx context.Context, tsPath, srtPath string, opts OCROptions, onProgress func(done, total int)) (OCRResult, error) {
+	data, err := os.ReadFile(tsPath)
+	if err != nil {
+		return OCRResult{}, err
+	}
+	packets, err := readDVBPES(data)
+	if err != nil && len(packets) == 0 {
+		return OCRResult{}, err
+	}
+	cues := parseDVBCues(packets)
+	if len(cues) == 0 {
+		return OCRResult{}, fmt.Errorf("No subtitles found in %s", filepath.Base(tsPath))
+	}
+	var cacheKey string
+	if opts.Cache {
+		// Without a key the result is just not cached
+		cacheKey, _ = ocrCacheKey(opts, tsPath)
+	}
+	return ocrCuesToSRT(ctx, cues, srtPath, cacheKey, opts, onProgress)
+}
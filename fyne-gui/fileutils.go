@@ -1,6 +1,15 @@
 package main
 
-import "os"
+import (
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+)
 
 // Helper function to check if a file exists and is executable
 func fileExistsAndExecutable(path string) bool {
@@ -12,3 +21,66 @@ func fileExistsAndExecutable(path string) bool {
 	// Check if file is executable
 	return info.Mode()&0111 != 0
 }
+
+// listableDir returns a remembered folder as a dialog location, or nil when
+// it is not set or no longer exists
+func listableDir(dir string) fyne.ListableURI {
+	if dir == "" {
+		return nil
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil
+	}
+	lister, err := storage.ListerForURI(storage.NewFileURI(dir))
+	if err != nil {
+		return nil
+	}
+	return lister
+}
+
+// maxRecentFiles is how many MKV files the "Recent MKVs" menu remembers
+const maxRecentFiles = 10
+
+// addRecentFile moves path to the front of the recent files list
+func addRecentFile(recent []string, path string) []string {
+	list := []string{path}
+	for _, p := range recent {
+		if p != path && len(list) < maxRecentFiles {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// removeRecentFile drops path from the recent files list
+func removeRecentFile(recent []string, path string) []string {
+	var list []string
+	for _, p := range recent {
+		if p != path {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// openFolder shows dir in the system file manager
+func openFolder(dir string) error {
+	u, err := url.Parse(storage.NewFileURI(dir).String())
+	if err != nil {
+		return err
+	}
+	return fyne.CurrentApp().OpenURL(u)
+}
+
+// revealFile shows path selected in Finder or Explorer. Linux file managers
+// have no common way to select a file, so its folder is opened instead.
+func revealFile(path string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", "-R", path).Start()
+	case "windows":
+		return exec.Command("explorer", "/select,"+path).Start()
+	default:
+		return openFolder(filepath.Dir(path))
+	}
+}
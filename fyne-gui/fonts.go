@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+// fontFileExts are the font formats that can be attached for ASS subtitles
+var fontFileExts = []string{".ttf", ".otf", ".ttc"}
+
+// assFontOverrideRegex matches \fn font overrides in ASS dialogue
+var assFontOverrideRegex = regexp.MustCompile(`\\fn([^\\}]+)`)
+
+// isFontFile reports whether path is a font file that can be attached
+func isFontFile(path string) bool {
+	return slices.Contains(fontFileExts, strings.ToLower(filepath.Ext(path)))
+}
+
+// isASSSubtitle reports whether path is an ASS/SSA script, which can use fonts
+func isASSSubtitle(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".ass" || ext == ".ssa"
+}
+
+// assFontNames returns the fonts an ASS/SSA script uses, from its styles and
+// the \fn overrides in its dialogue, in order of first use
+func assFontNames(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var fonts []string
+	addFont := func(name string) {
+		// A leading @ asks for vertical text in the same font
+		name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+		if name != "" && !slices.ContainsFunc(fonts, func(font string) bool { return strings.EqualFold(font, name) }) {
+			fonts = append(fonts, name)
+		}
+	}
+
+	fontColumn := -1
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "Format:") && fontColumn < 0:
+			for i, column := range strings.Split(strings.TrimPrefix(line, "Format:"), ",") {
+				if strings.EqualFold(strings.TrimSpace(column), "Fontname") {
+					fontColumn = i
+				}
+			}
+		case strings.HasPrefix(line, "Style:") && fontColumn >= 0:
+			columns := strings.Split(strings.TrimPrefix(line, "Style:"), ",")
+			if fontColumn < len(columns) {
+				addFont(columns[fontColumn])
+			}
+		case strings.HasPrefix(line, "Dialogue:"):
+			for _, m := range assFontOverrideRegex.FindAllStringSubmatch(line, -1) {
+				addFont(m[1])
+			}
+		}
+	}
+	return fonts, scanner.Err()
+}
+
+// fontFamilyNames reads the family and full names of the fonts in a
+// TrueType/OpenType file or collection from their 'name' tables
+func fontFamilyNames(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 {
+		return nil, errors.New(trf("Not a font file: %s", filepath.Base(path)))
+	}
+
+	offsets := []uint32{0}
+	if string(data[:4]) == "ttcf" {
+		offsets = nil
+		count := binary.BigEndian.Uint32(data[8:])
+		for i := uint32(0); i < count; i++ {
+			if b := byteRange(data, 12+4*int(i), 4); b != nil {
+				offsets = append(offsets, binary.BigEndian.Uint32(b))
+			}
+		}
+	}
+
+	var names []string
+	for _, offset := range offsets {
+		for _, name := range sfntNames(data, int(offset)) {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// sfntNames returns the family (IDs 1 and 16) and full (ID 4) names of the
+// font whose table directory starts at offset
+func sfntNames(data []byte, offset int) []string {
+	header := byteRange(data, offset, 12)
+	if header == nil {
+		return nil
+	}
+	var names []string
+	numTables := int(binary.BigEndian.Uint16(header[4:]))
+	for i := 0; i < numTables; i++ {
+		record := byteRange(data, offset+12+16*i, 16)
+		if record == nil || string(record[:4]) != "name" {
+			continue
+		}
+		table := byteRange(data, int(binary.BigEndian.Uint32(record[8:])), int(binary.BigEndian.Uint32(record[12:])))
+		if len(table) < 6 {
+			return nil
+		}
+		count := int(binary.BigEndian.Uint16(table[2:]))
+		stringsStart := int(binary.BigEndian.Uint16(table[4:]))
+		for j := 0; j < count; j++ {
+			entry := byteRange(table, 6+12*j, 12)
+			if entry == nil {
+				break
+			}
+			platform := binary.BigEndian.Uint16(entry[0:])
+			nameID := binary.BigEndian.Uint16(entry[6:])
+			if nameID != 1 && nameID != 4 && nameID != 16 {
+				continue
+			}
+			raw := byteRange(table, stringsStart+int(binary.BigEndian.Uint16(entry[10:])), int(binary.BigEndian.Uint16(entry[8:])))
+			name := ""
+			switch platform {
+			case 0, 3: // Unicode and Windows names are UTF-16BE
+				units := make([]uint16, len(raw)/2)
+				for k := range units {
+					units[k] = binary.BigEndian.Uint16(raw[2*k:])
+				}
+				name = string(utf16.Decode(units))
+			case 1: // Macintosh names are (mostly) ASCII
+				name = string(raw)
+			}
+			if name = strings.TrimSpace(name); name != "" && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// byteRange returns data[start:start+length], or nil when it is out of range
+func byteRange(data []byte, start, length int) []byte {
+	if start < 0 || length < 0 || start+length > len(data) {
+		return nil
+	}
+	return data[start : start+length]
+}
+
+// normalizeFontName makes font names comparable: "Open Sans-Bold" and
+// "opensans_bold" are the same
+func normalizeFontName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '_' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// missingFonts returns the fonts in used that none of the font files
+// provide. Fonts already attached to the MKV are only known by their file
+// names, so those are matched on the name instead.
+func missingFonts(used []string, fontFiles []string, attachmentNames []string) []string {
+	var provided []string
+	for _, path := range fontFiles {
+		names, _ := fontFamilyNames(path)
+		for _, name := range names {
+			provided = append(provided, normalizeFontName(name))
+		}
+		provided = append(provided, normalizeFontName(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))))
+	}
+	var attached []string
+	for _, name := range attachmentNames {
+		attached = append(attached, normalizeFontName(strings.TrimSuffix(name, filepath.Ext(name))))
+	}
+
+	var missing []string
+	for _, font := range used {
+		normalized := normalizeFontName(font)
+		if slices.Contains(provided, normalized) || slices.ContainsFunc(attached, func(name string) bool { return strings.HasPrefix(name, normalized) }) {
+			continue
+		}
+		missing = append(missing, font)
+	}
+	return missing
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// maxHistoryEntries is how many finished jobs the History tab keeps
+const maxHistoryEntries = 50
+
+// HistoryEntry records a finished extraction of one MKV file, with the
+// options of every extracted track so the job can be run again
+type HistoryEntry struct {
+	Time   time.Time      `json:"time"`
+	Path   string         `json:"path"`
+	OutDir string         `json:"out_dir"`
+	Tracks []HistoryTrack `json:"tracks"`
+}
+
+// HistoryTrack is a track of a HistoryEntry with its options and result
+type HistoryTrack struct {
+	Num         int    `json:"num"`
+	Lang        string `json:"lang"`
+	Codec       string `json:"codec"`
+	OutputName  string `json:"output_name,omitempty"`
+	Convert     bool   `json:"convert,omitempty"`
+	OCRLanguage string `json:"ocr_language,omitempty"`
+	OCREngine   string `json:"ocr_engine,omitempty"`
+	State       string `json:"state"`
+	OutputPath  string `json:"output_path,omitempty"`
+}
+
+// newHistoryEntry records the extraction of the checked tracks of a file
+func newHistoryEntry(mkvPath, outDir string, items []*TrackItem) HistoryEntry {
+	entry := HistoryEntry{Time: time.Now(), Path: mkvPath, OutDir: outDir}
+	for _, t := range items {
+		if !t.Check.Checked {
+			continue
+		}
+		track := HistoryTrack{
+			Num:        t.Num,
+			Lang:       t.Lang,
+			Codec:      t.Codec,
+			OutputName: t.OutputName,
+			State:      t.State,
+			OutputPath: t.OutputPath,
+		}
+		if t.ConvertOCR != nil {
+			track.Convert = t.ConvertOCR.Checked
+		}
+		if t.LangSelect != nil {
+			track.OCRLanguage = t.LangSelect.Selected
+		}
+		track.OCREngine = ocrEngine(t)
+		entry.Tracks = append(entry.Tracks, track)
+	}
+	return entry
+}
+
+// doneCount returns how many tracks of the job were extracted
+func (entry *HistoryEntry) doneCount() int {
+	done := 0
+	for _, t := range entry.Tracks {
+		if t.State == "Done" {
+			done++
+		}
+	}
+	return done
+}
+
+// applyTo checks the tracks of the job in freshly loaded tracks and restores
+// their options. It returns how many of the job's tracks were found.
+func (entry *HistoryEntry) applyTo(items []*TrackItem) int {
+	found := 0
+	for _, t := range items {
+		t.Check.SetChecked(false)
+		for _, track := range entry.Tracks {
+			if track.Num != t.Num || track.Codec != t.Codec {
+				continue
+			}
+			found++
+			t.Check.SetChecked(true)
+			t.OutputName = track.OutputName
+			if t.ConvertOCR != nil {
+				t.ConvertOCR.SetChecked(track.Convert)
+			}
+			if t.LangSelect != nil && track.OCRLanguage != "" {
+				selectOCRLanguage(t.LangSelect, track.OCRLanguage)
+			}
+			if t.Engine != nil && track.OCREngine != "" {
+				t.Engine.SetSelected(track.OCREngine)
+			}
+		}
+	}
+	return found
+}
+
+// loadHistory reads the finished jobs, newest first
+func loadHistory(prefs fyne.Preferences) []HistoryEntry {
+	var history []HistoryEntry
+	if data := prefs.String("extraction_history"); data != "" {
+		if err := json.Unmarshal([]byte(data), &history); err != nil {
+			fyne.LogError("Error loading extraction history", err)
+		}
+	}
+	return history
+}
+
+// saveHistory stores the finished jobs in the preferences
+func saveHistory(prefs fyne.Preferences, history []HistoryEntry) {
+	data, err := json.Marshal(history)
+	if err != nil {
+		fyne.LogError("Error saving extraction history", err)
+		return
+	}
+	prefs.SetString("extraction_history", string(data))
+}
+
+// addHistoryEntry records a finished job, dropping the oldest beyond maxHistoryEntries
+func addHistoryEntry(prefs fyne.Preferences, entry HistoryEntry) {
+	history := append([]HistoryEntry{entry}, loadHistory(prefs)...)
+	if len(history) > maxHistoryEntries {
+		history = history[:maxHistoryEntries]
+	}
+	saveHistory(prefs, history)
+}
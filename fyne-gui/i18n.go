@@ -0,0 +1,62 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/lang"
+)
+
+//go:embed translations/*.json
+var translationFiles embed.FS
+
+// uiLanguages are the interface languages selectable in the Settings tab;
+// an empty code follows the system language
+var uiLanguages = []struct {
+	Code string
+	Name string
+}{
+	{"", "System"},
+	{"en", "English"},
+	{"nl", "Nederlands"},
+	{"fr", "Français"},
+	{"de", "Deutsch"},
+	{"es", "Español"},
+}
+
+// translations maps the English interface strings to the active language
+var translations map[string]string
+
+// loadTranslations selects the interface language from the preferences, or
+// from the system when none was chosen. English needs no translation file.
+func loadTranslations(a fyne.App) {
+	code := a.Preferences().String("ui_language")
+	if code == "" {
+		code, _, _ = strings.Cut(lang.SystemLocale().LanguageString(), "-")
+	}
+
+	translations = nil
+	data, err := translationFiles.ReadFile("translations/" + code + ".json")
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &translations); err != nil {
+		fyne.LogError("Error loading translations for "+code, err)
+	}
+}
+
+// tr returns the translation of an interface string, or the string itself
+func tr(s string) string {
+	if translated, ok := translations[s]; ok && translated != "" {
+		return translated
+	}
+	return s
+}
+
+// trf formats a translated interface string
+func trf(format string, args ...any) string {
+	return fmt.Sprintf(tr(format), args...)
+}
@@ -0,0 +1,662 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// insertSubtitleExts are the subtitle formats the Insert tab can mux: text
+// (SRT, ASS/SSA, WebVTT), PGS and VobSub (IDX/SUB)
+var insertSubtitleExts = []string{".srt", ".ass", ".ssa", ".vtt", ".sup", ".idx", ".sub"}
+
+// isInsertSubtitle reports whether path is a subtitle file the Insert tab accepts
+func isInsertSubtitle(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range insertSubtitleExts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// insertSubtitlePath returns the file mkvmerge has to be given for a subtitle:
+// VobSub is muxed from its .idx file, which refers to the .sub next to it
+func insertSubtitlePath(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".idx" && ext != ".sub" {
+		return path, nil
+	}
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, pair := range [][2]string{{".idx", ".sub"}, {".IDX", ".SUB"}} {
+		if _, err := os.Stat(base + pair[0]); err != nil {
+			continue
+		}
+		if _, err := os.Stat(base + pair[1]); err != nil {
+			continue
+		}
+		return base + pair[0], nil
+	}
+	return "", errors.New(trf("VobSub subtitles need both an .idx and a .sub file: %s", filepath.Base(base)))
+}
+
+// isImageSubtitle reports whether a subtitle file holds bitmaps (PGS or VobSub)
+func isImageSubtitle(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".sup" || ext == ".idx"
+}
+
+// InsertSubtitle is a subtitle file to mux into an MKV with its track options
+type InsertSubtitle struct {
+	Path    string
+	Lang    string // ISO 639-2 language code
+	Name    string
+	Default bool
+	Forced  bool
+	Offset  int // Timing offset in milliseconds, applied with --sync
+}
+
+// insertCharsets are the character sets offered for legacy text subtitles;
+// the names are the ones mkvmerge's --sub-charset accepts
+var insertCharsets = []string{
+	"UTF-8",
+	"CP1250", "CP1251", "CP1252", "CP1253", "CP1254", "CP1255", "CP1256", "CP1257",
+	"ISO-8859-1", "ISO-8859-2", "ISO-8859-5", "ISO-8859-7", "ISO-8859-9", "ISO-8859-15",
+	"KOI8-R", "Shift_JIS", "GB18030", "Big5", "EUC-KR",
+}
+
+// parseOffset reads a timing offset in milliseconds such as "+500", "-1200"
+// or "250 ms"; empty text is no offset
+func parseOffset(text string) (int, error) {
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "ms"))
+	if text == "" {
+		return 0, nil
+	}
+	offset, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, errors.New(tr("Enter the offset in milliseconds, e.g. +500 or -1200"))
+	}
+	return offset, nil
+}
+
+// validateOffset is the entry validator for timing offsets
+func validateOffset(text string) error {
+	_, err := parseOffset(text)
+	return err
+}
+
+// insertSubtitlesArgs builds the mkvmerge arguments that mux all subtitles
+// into mkvPath in a single pass, optionally dropping all its own subtitle
+// tracks or only the tracks with the IDs in replaced
+func insertSubtitlesArgs(mkvPath, outputPath string, subs []*InsertSubtitle, opts InsertOptions, replaced []int, trackOrder string) []string {
+	args := []string{"-o", outputPath}
+	if trackOrder != "" {
+		args = append(args, "--track-order", trackOrder)
+	}
+	// --no-chapters applies to the MKV that follows, so its own chapters
+	// are replaced rather than merged
+	if opts.ChaptersFile != "" {
+		args = append(args, "--chapters", opts.ChaptersFile, "--no-chapters")
+	}
+	if opts.RemoveOther {
+		args = append(args, "--no-subtitles")
+	} else if len(replaced) > 0 {
+		ids := make([]string, len(replaced))
+		for i, id := range replaced {
+			ids[i] = strconv.Itoa(id)
+		}
+		args = append(args, "--subtitle-tracks", "!"+strings.Join(ids, ","))
+	}
+	args = append(args, mkvPath)
+
+	// Track options apply to track 0 of the file that follows them
+	for _, sub := range subs {
+		args = append(args, "--language", "0:"+sub.Lang, "--track-name", "0:"+sub.Name)
+		if sub.Default {
+			args = append(args, "--default-track", "0:yes")
+		} else {
+			args = append(args, "--default-track", "0:no")
+		}
+		if sub.Forced {
+			args = append(args, "--forced-track", "0:yes")
+		}
+		if sub.Offset != 0 {
+			args = append(args, "--sync", fmt.Sprintf("0:%d", sub.Offset))
+		}
+		// mkvmerge compresses bitmap subtitles with zlib by default, which
+		// many hardware players cannot decode
+		if isImageSubtitle(sub.Path) {
+			args = append(args, "--compression", "0:none")
+		} else if opts.Charset != "" {
+			args = append(args, "--sub-charset", "0:"+opts.Charset)
+		}
+		args = append(args, sub.Path)
+	}
+
+	for _, font := range opts.Fonts {
+		args = append(args, "--attach-file", font)
+	}
+	return args
+}
+
+// insertSubtitleCodecs names the codec of each insertable subtitle format the
+// way mkvmerge reports it
+var insertSubtitleCodecs = map[string]string{
+	".srt": "SubRip/SRT",
+	".ass": "SubStationAlpha",
+	".ssa": "SubStationAlpha",
+	".vtt": "WebVTT",
+	".sup": "HDMV PGS",
+	".idx": "VobSub",
+}
+
+// Positions of the inserted subtitles among the tracks of the output file
+const (
+	InsertPositionEnd           = "end"            // After all existing tracks
+	InsertPositionFirstSubtitle = "first_subtitle" // Before the existing subtitle tracks
+	InsertPositionAfter         = "after"          // After a given existing track
+)
+
+// MuxTrack is a track of the MKV file an insertion produces
+type MuxTrack struct {
+	File    int // Input file of mkvmerge: 0 for the MKV, then the subtitles in order
+	ID      int // Track ID within the input file
+	Type    string
+	Codec   string
+	Lang    string
+	Name    string
+	Default bool
+	Forced  bool
+	Offset  int    // Timing offset in milliseconds of an inserted track
+	Source  string // Base name of the file the track comes from
+}
+
+// sameLanguage reports whether two language codes or names name the same
+// language, e.g. "nld" and "dut"
+func sameLanguage(a, b string) bool {
+	normalize := func(lang string) string {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if code, ok := subtitleLanguageCodes[lang]; ok {
+			return code
+		}
+		return lang
+	}
+	return normalize(a) == normalize(b)
+}
+
+// muxTrackLayout returns the tracks of the file an insertion produces: the
+// tracks of mkvPath that are kept, in their order, followed by the inserted
+// subtitles. With replaceSameLang, existing subtitle tracks in the language
+// of an inserted subtitle are dropped; their IDs are returned in replaced.
+func muxTrackLayout(mkvPath string, subs []*InsertSubtitle, removeOther, replaceSameLang bool) (tracks []MuxTrack, replaced []int, err error) {
+	existing, err := mkvTracks(mkvPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, t := range existing {
+		if removeOther && t.Type == "subtitles" {
+			continue
+		}
+		if replaceSameLang && t.Type == "subtitles" && slices.ContainsFunc(subs, func(sub *InsertSubtitle) bool {
+			return sameLanguage(sub.Lang, t.Lang)
+		}) {
+			replaced = append(replaced, t.ID)
+			continue
+		}
+		tracks = append(tracks, t)
+	}
+	for i, sub := range subs {
+		tracks = append(tracks, MuxTrack{
+			File:    i + 1,
+			Type:    "subtitles",
+			Codec:   insertSubtitleCodecs[strings.ToLower(filepath.Ext(sub.Path))],
+			Lang:    sub.Lang,
+			Name:    sub.Name,
+			Default: sub.Default,
+			Forced:  sub.Forced,
+			Offset:  sub.Offset,
+			Source:  filepath.Base(sub.Path),
+		})
+	}
+	return tracks, replaced, nil
+}
+
+// mkvTracks reads the tracks of an MKV file with mkvmerge, in their order
+func mkvTracks(mkvPath string) ([]MuxTrack, error) {
+	output, err := exec.Command("mkvmerge", "-J", mkvPath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("Error running mkvmerge: %v", err)
+	}
+	var info struct {
+		Tracks []struct {
+			ID         int    `json:"id"`
+			Type       string `json:"type"`
+			Codec      string `json:"codec"`
+			Properties struct {
+				Language string `json:"language"`
+				Name     string `json:"track_name"`
+				Default  bool   `json:"default_track"`
+				Forced   bool   `json:"forced_track"`
+			} `json:"properties"`
+		} `json:"tracks"`
+	}
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, fmt.Errorf("Error parsing mkvmerge output: %v", err)
+	}
+
+	var tracks []MuxTrack
+	for _, t := range info.Tracks {
+		tracks = append(tracks, MuxTrack{
+			ID:      t.ID,
+			Type:    t.Type,
+			Codec:   t.Codec,
+			Lang:    t.Properties.Language,
+			Name:    t.Properties.Name,
+			Default: t.Properties.Default,
+			Forced:  t.Properties.Forced,
+			Source:  filepath.Base(mkvPath),
+		})
+	}
+	return tracks, nil
+}
+
+// formatMuxLayout lists the tracks of an insertion's output, one per line
+func formatMuxLayout(tracks []MuxTrack) string {
+	var b strings.Builder
+	for i, t := range tracks {
+		var flags []string
+		if t.Default {
+			flags = append(flags, tr("default"))
+		}
+		if t.Forced {
+			flags = append(flags, tr("forced"))
+		}
+		if t.Offset != 0 {
+			flags = append(flags, fmt.Sprintf("%+d ms", t.Offset))
+		}
+		source := t.Source
+		if t.File == 0 {
+			source = fmt.Sprintf("%s #%d", t.Source, t.ID)
+		}
+		fmt.Fprintf(&b, "%2d  %-9s  %-16s  %-3s  %-20s  %-16s  %s\n", i, t.Type, t.Codec, t.Lang, t.Name, strings.Join(flags, ", "), source)
+	}
+	return b.String()
+}
+
+// orderMuxTracks moves the inserted subtitles of a layout to position; for
+// InsertPositionAfter they follow the existing track with ID after
+func orderMuxTracks(tracks []MuxTrack, position string, after int) ([]MuxTrack, error) {
+	var existing, inserted []MuxTrack
+	for _, t := range tracks {
+		if t.File == 0 {
+			existing = append(existing, t)
+		} else {
+			inserted = append(inserted, t)
+		}
+	}
+
+	at := len(existing)
+	switch position {
+	case InsertPositionFirstSubtitle:
+		if i := slices.IndexFunc(existing, func(t MuxTrack) bool { return t.Type == "subtitles" }); i >= 0 {
+			at = i
+		}
+	case InsertPositionAfter:
+		i := slices.IndexFunc(existing, func(t MuxTrack) bool { return t.ID == after })
+		if i < 0 {
+			return nil, errors.New(trf("Track %d is not in the output file", after))
+		}
+		at = i + 1
+	}
+	return slices.Concat(existing[:at], inserted, existing[at:]), nil
+}
+
+// trackOrderArg builds the mkvmerge --track-order value for a layout
+func trackOrderArg(tracks []MuxTrack) string {
+	order := make([]string, len(tracks))
+	for i, t := range tracks {
+		order[i] = fmt.Sprintf("%d:%d", t.File, t.ID)
+	}
+	return strings.Join(order, ",")
+}
+
+// InsertOptions are the Insert tab options that apply to every insertion
+type InsertOptions struct {
+	RemoveOther     bool
+	ReplaceSameLang bool
+	Position        string
+	AfterTrack      int    // Existing track the subtitles follow with InsertPositionAfter
+	Charset         string // Character set of text subtitles, detected by mkvmerge when empty
+	Fonts           []string
+	ChaptersFile    string // Chapters replacing those of the MKV, in a format mkvmerge reads
+	KeepAttributes  bool   // Give the output the modification time and permissions of the MKV
+	ReplaceOriginal bool   // Move the verified output over the MKV
+}
+
+// InsertPlan is a prepared insertion: the tracks of the output file and the
+// mkvmerge arguments that produce it
+type InsertPlan struct {
+	Tracks   []MuxTrack
+	Replaced []int // IDs of the existing tracks dropped for ReplaceSameLang
+	Args     []string
+
+	// Fonts the ASS/SSA subtitles use that neither the attached fonts nor
+	// the attachments of the MKV provide
+	MissingFonts []string
+}
+
+// planInsert probes mkvPath and prepares muxing subs into outputPath
+func planInsert(mkvPath, outputPath string, subs []*InsertSubtitle, opts InsertOptions) (*InsertPlan, error) {
+	tracks, replaced, err := muxTrackLayout(mkvPath, subs, opts.RemoveOther, opts.ReplaceSameLang)
+	if err != nil {
+		return nil, err
+	}
+	tracks, err = orderMuxTracks(tracks, opts.Position, opts.AfterTrack)
+	if err != nil {
+		return nil, err
+	}
+	// mkvmerge appends new tracks by default
+	trackOrder := ""
+	if opts.Position != InsertPositionEnd {
+		trackOrder = trackOrderArg(tracks)
+	}
+
+	var usedFonts []string
+	for _, sub := range subs {
+		if !isASSSubtitle(sub.Path) {
+			continue
+		}
+		fonts, err := assFontNames(sub.Path)
+		if err != nil {
+			return nil, err
+		}
+		usedFonts = append(usedFonts, fonts...)
+	}
+	var missing []string
+	if len(usedFonts) > 0 || len(opts.Fonts) > 0 {
+		attachments, err := mkvAttachmentNames(mkvPath)
+		if err != nil {
+			return nil, err
+		}
+		missing = missingFonts(usedFonts, opts.Fonts, attachments)
+		// Fonts the MKV already carries are not attached twice
+		opts.Fonts = slices.DeleteFunc(slices.Clone(opts.Fonts), func(font string) bool {
+			return slices.ContainsFunc(attachments, func(name string) bool { return strings.EqualFold(name, filepath.Base(font)) })
+		})
+	}
+
+	return &InsertPlan{
+		Tracks:       tracks,
+		Replaced:     replaced,
+		Args:         insertSubtitlesArgs(mkvPath, outputPath, subs, opts, replaced, trackOrder),
+		MissingFonts: missing,
+	}, nil
+}
+
+// verifyInsert reads the tracks of the muxed file and describes every way
+// the inserted subtitles differ from the plan. It returns no mismatches when
+// they are all there with the planned language, name and flags.
+func verifyInsert(outputPath string, plan *InsertPlan) ([]string, error) {
+	tracks, err := mkvTracks(outputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var mismatches []string
+	if len(tracks) != len(plan.Tracks) {
+		mismatches = append(mismatches, fmt.Sprintf("The output has %d tracks, %d were expected", len(tracks), len(plan.Tracks)))
+	}
+	for i, want := range plan.Tracks {
+		if want.File == 0 {
+			continue
+		}
+		if i >= len(tracks) || tracks[i].Type != "subtitles" {
+			mismatches = append(mismatches, fmt.Sprintf("%s: no subtitle track at position %d", want.Source, i+1))
+			continue
+		}
+		got := tracks[i]
+		if !sameLanguage(got.Lang, want.Lang) {
+			mismatches = append(mismatches, fmt.Sprintf("%s: language is %q, expected %q", want.Source, got.Lang, want.Lang))
+		}
+		if got.Name != want.Name {
+			mismatches = append(mismatches, fmt.Sprintf("%s: track name is %q, expected %q", want.Source, got.Name, want.Name))
+		}
+		if got.Default != want.Default {
+			mismatches = append(mismatches, fmt.Sprintf("%s: default flag is %t, expected %t", want.Source, got.Default, want.Default))
+		}
+		if got.Forced != want.Forced {
+			mismatches = append(mismatches, fmt.Sprintf("%s: forced flag is %t, expected %t", want.Source, got.Forced, want.Forced))
+		}
+	}
+	return mismatches, nil
+}
+
+// mkvAttachmentNames returns the file names of the attachments of an MKV file
+func mkvAttachmentNames(mkvPath string) ([]string, error) {
+	output, err := exec.Command("mkvmerge", "-J", mkvPath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("Error running mkvmerge: %v", err)
+	}
+	var info struct {
+		Attachments []struct {
+			FileName string `json:"file_name"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, fmt.Errorf("Error parsing mkvmerge output: %v", err)
+	}
+	var names []string
+	for _, attachment := range info.Attachments {
+		names = append(names, attachment.FileName)
+	}
+	return names, nil
+}
+
+// insertOutputPath returns the default output file of an insertion
+func insertOutputPath(mkvPath string) string {
+	return strings.TrimSuffix(mkvPath, filepath.Ext(mkvPath)) + "_with_subtitles.mkv"
+}
+
+// insertOutputSize estimates the size of the remuxed file as the total size
+// of its inputs, the data of a VobSub pair being in the .sub file
+func insertOutputSize(mkvPath string, subs []*InsertSubtitle, opts InsertOptions) int64 {
+	paths := append([]string{mkvPath}, opts.Fonts...)
+	for _, sub := range subs {
+		paths = append(paths, sub.Path)
+		if strings.EqualFold(filepath.Ext(sub.Path), ".idx") {
+			paths = append(paths, strings.TrimSuffix(sub.Path, filepath.Ext(sub.Path))+".sub")
+		}
+	}
+	return filesSize(paths...)
+}
+
+// InsertJob pairs an MKV file with the subtitle files found for it
+type InsertJob struct {
+	MkvPath string
+	Subs    []string
+}
+
+// matchInsertJobs pairs the MKV files in dir with the subtitle files named
+// after them, e.g. Movie.mkv with Movie.srt, Movie.nl.srt and
+// Movie.en.forced.srt. A subtitle goes to the MKV with the longest matching
+// name, so Movie.Part2.nl.srt is not paired with Movie.mkv.
+func matchInsertJobs(dir string) ([]InsertJob, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []InsertJob
+	var subFiles []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if strings.EqualFold(filepath.Ext(name), ".mkv") {
+			jobs = append(jobs, InsertJob{MkvPath: filepath.Join(dir, name)})
+		} else if isInsertSubtitle(name) {
+			subFiles = append(subFiles, name)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, name := range subFiles {
+		subBase := strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name)))
+		best, bestLen := -1, 0
+		for i, job := range jobs {
+			mkvBase := strings.ToLower(strings.TrimSuffix(filepath.Base(job.MkvPath), filepath.Ext(job.MkvPath)))
+			if subBase != mkvBase && !strings.HasPrefix(subBase, mkvBase+".") {
+				continue
+			}
+			if len(mkvBase) > bestLen {
+				best, bestLen = i, len(mkvBase)
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		// Both files of a VobSub pair resolve to the .idx; pairs missing a
+		// file are left out
+		path, err := insertSubtitlePath(filepath.Join(dir, name))
+		if err != nil || seen[path] {
+			continue
+		}
+		seen[path] = true
+		jobs[best].Subs = append(jobs[best].Subs, path)
+	}
+
+	return slices.DeleteFunc(jobs, func(job InsertJob) bool { return len(job.Subs) == 0 }), nil
+}
+
+// isForcedSubtitleFile reports whether a subtitle filename marks the
+// subtitle as forced, e.g. Movie.en.forced.srt
+func isForcedSubtitleFile(path string) bool {
+	parts := strings.Split(strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))), ".")
+	return slices.Contains(parts[1:], "forced")
+}
+
+// mkvmergeWarningRegex matches a warning mkvmerge prints, which usually names
+// the file and track it concerns: Warning: 'movie.srt' track 0: ...
+var mkvmergeWarningRegex = regexp.MustCompile(`^Warning: (?:'([^']+)'(?: track (\d+))?: )?(.*)$`)
+
+// MuxWarning is a warning mkvmerge printed while muxing, with how often it was
+// repeated
+type MuxWarning struct {
+	File    string
+	Track   string
+	Message string
+	Count   int
+}
+
+// String formats the warning as "movie.srt (track 0): message (3x)"
+func (warning MuxWarning) String() string {
+	text := warning.Message
+	switch {
+	case warning.File != "" && warning.Track != "":
+		text = fmt.Sprintf("%s (track %s): %s", filepath.Base(warning.File), warning.Track, text)
+	case warning.File != "":
+		text = fmt.Sprintf("%s: %s", filepath.Base(warning.File), text)
+	}
+	if warning.Count > 1 {
+		text += fmt.Sprintf(" (%dx)", warning.Count)
+	}
+	return text
+}
+
+// mkvmergeWarnings returns the warnings in mkvmerge output, in order of
+// first appearance, counting repeats of the same warning once
+func mkvmergeWarnings(output []byte) []*MuxWarning {
+	var warnings []*MuxWarning
+	for _, line := range strings.Split(string(output), "\n") {
+		m := mkvmergeWarningRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		warning := &MuxWarning{File: m[1], Track: m[2], Message: m[3], Count: 1}
+		if i := slices.IndexFunc(warnings, func(other *MuxWarning) bool {
+			return other.File == warning.File && other.Track == warning.Track && other.Message == warning.Message
+		}); i >= 0 {
+			warnings[i].Count++
+			continue
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
+// mkvmergeError drops the error of an mkvmerge run that only printed
+// warnings: mkvmerge then exits with status 1 and the output file is usable
+func mkvmergeError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil
+	}
+	return err
+}
+
+// copyFileAttributes gives dst the modification time and permissions of src,
+// so library scanners don't take a remuxed file for a new one
+func copyFileAttributes(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, time.Time{}, info.ModTime())
+}
+
+// finishInsert applies the output options to a muxed file and returns where
+// it ended up. The original MKV is only replaced by an output that passed
+// verification, with a rename so it is never left half written.
+func finishInsert(mkvPath, outputPath string, opts InsertOptions, verified bool) (string, error) {
+	if opts.KeepAttributes {
+		if err := copyFileAttributes(mkvPath, outputPath); err != nil {
+			return outputPath, fmt.Errorf("Error copying the file date and permissions: %v", err)
+		}
+	}
+	if !opts.ReplaceOriginal {
+		return outputPath, nil
+	}
+	if !verified {
+		return outputPath, errors.New("The original was kept because the output did not pass verification")
+	}
+	if err := os.Rename(outputPath, mkvPath); err != nil {
+		return outputPath, fmt.Errorf("Error replacing the original: %v", err)
+	}
+	return mkvPath, nil
+}
+
+// MuxJob is a subtitle insertion waiting in the mux queue, with the
+// subtitles and options it was queued with
+type MuxJob struct {
+	MkvPath    string
+	OutputPath string
+	Subs       []*InsertSubtitle
+	Opts       InsertOptions
+	State      string // Pending, Running, Done or Error
+	Message    string // Why the job failed, or its warnings
+}
+
+// newMuxJob queues a copy of the subtitles and options, so editing the
+// Insert tab afterwards leaves the job as it was queued
+func newMuxJob(mkvPath, outputPath string, subs []*InsertSubtitle, opts InsertOptions) *MuxJob {
+	job := &MuxJob{MkvPath: mkvPath, OutputPath: outputPath, Opts: opts, State: "Pending"}
+	for _, sub := range subs {
+		copied := *sub
+		job.Subs = append(job.Subs, &copied)
+	}
+	job.Opts.Fonts = slices.Clone(opts.Fonts)
+	return job
+}
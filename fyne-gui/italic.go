@@ -0,0 +1,108 @@
+package main
+
+import (
+	"image"
+	"strings"
+)
+
+// italicShears are the slants of italic subtitle glyphs tried, as pixels the
+// top of a glyph leans to the right per row of height
+var italicShears = []float64{0.1, 0.15, 0.2, 0.25, 0.3}
+
+// italicGain is how much sharper the columns of a line of text must become
+// when its slant is undone for the line to count as italic
+const italicGain = 1.05
+
+// textLine is a band of rows of a bitmap holding a line of text
+type textLine struct {
+	Top, Bottom int
+}
+
+// textLines returns the lines of text of a binarized bitmap, top to bottom,
+// leaving out bands too thin to be text
+func textLines(g *image.Gray) []textLine {
+	const minHeight = 4
+	var lines []textLine
+	b := g.Bounds()
+	top := -1
+	for y := b.Min.Y; y <= b.Max.Y; y++ {
+		inked := false
+		for x := b.Min.X; y < b.Max.Y && x < b.Max.X; x++ {
+			if g.GrayAt(x, y).Y < 0x80 {
+				inked = true
+				break
+			}
+		}
+		switch {
+		case inked && top == -1:
+			top = y
+		case !inked && top != -1:
+			if y-top >= minHeight {
+				lines = append(lines, textLine{Top: top, Bottom: y})
+			}
+			top = -1
+		}
+	}
+	return lines
+}
+
+// columnSharpness shears the text of a line left by shear pixels per row
+// above its bottom and returns the sum of squares of the inked pixels per
+// column. Upright strokes fill few columns, which makes the sum large.
+func columnSharpness(g *image.Gray, line textLine, shear float64) float64 {
+	b := g.Bounds()
+	offset := int(shear*float64(line.Bottom-line.Top)) + 1
+	columns := make([]int, b.Dx()+offset)
+	for y := line.Top; y < line.Bottom; y++ {
+		shift := int(shear * float64(line.Bottom-1-y))
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if g.GrayAt(x, y).Y < 0x80 {
+				columns[x-b.Min.X-shift+offset]++
+			}
+		}
+	}
+	var sum float64
+	for _, n := range columns {
+		sum += float64(n * n)
+	}
+	return sum
+}
+
+// italicLines reports for every line of text of a binarized bitmap whether
+// its glyphs lean to the right
+func italicLines(g *image.Gray) []bool {
+	var italic []bool
+	for _, line := range textLines(g) {
+		upright := columnSharpness(g, line, 0)
+		slanted := 0.0
+		for _, shear := range italicShears {
+			slanted = max(slanted, columnSharpness(g, line, shear))
+		}
+		italic = append(italic, upright > 0 && slanted > upright*italicGain)
+	}
+	return italic
+}
+
+// italicize wraps the lines of text recognized in a bitmap in <i> tags where
+// the bitmap's lines are italic. When the lines of the text and the bitmap
+// do not match up, the text is only tagged when all of it is italic.
+func italicize(text string, italic []bool) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) != len(italic) {
+		for _, it := range italic {
+			if !it {
+				return text
+			}
+		}
+		if len(italic) == 0 {
+			return text
+		}
+		return "<i>" + text + "</i>"
+	}
+	for i, line := range lines {
+		if italic[i] && line != "" {
+			lines[i] = "<i>" + line + "</i>"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
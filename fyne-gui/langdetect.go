@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// subtitleLanguageCodes maps the language codes and names found in subtitle
+// filenames to the ISO 639-2/B codes Matroska uses
+var subtitleLanguageCodes = map[string]string{
+	"en": "eng", "eng": "eng", "english": "eng",
+	"nl": "dut", "dut": "dut", "nld": "dut", "dutch": "dut",
+	"fr": "fre", "fre": "fre", "fra": "fre", "french": "fre",
+	"de": "ger", "ger": "ger", "deu": "ger", "german": "ger",
+	"es": "spa", "spa": "spa", "spanish": "spa",
+	"it": "ita", "ita": "ita", "italian": "ita",
+	"pt": "por", "por": "por", "portuguese": "por",
+	"sv": "swe", "swe": "swe", "swedish": "swe",
+	"da": "dan", "dan": "dan", "danish": "dan",
+	"no": "nor", "nor": "nor", "nb": "nor", "nob": "nor", "norwegian": "nor",
+	"fi": "fin", "fin": "fin", "finnish": "fin",
+	"pl": "pol", "pol": "pol", "polish": "pol",
+	"cs": "cze", "cze": "cze", "ces": "cze", "czech": "cze",
+	"sk": "slo", "slo": "slo", "slk": "slo", "slovak": "slo",
+	"hu": "hun", "hun": "hun", "hungarian": "hun",
+	"ro": "rum", "rum": "rum", "ron": "rum", "romanian": "rum",
+	"el": "gre", "gre": "gre", "ell": "gre", "greek": "gre",
+	"tr": "tur", "tur": "tur", "turkish": "tur",
+	"ru": "rus", "rus": "rus", "russian": "rus",
+	"uk": "ukr", "ukr": "ukr", "ukrainian": "ukr",
+	"bg": "bul", "bul": "bul", "bulgarian": "bul",
+	"hr": "hrv", "hrv": "hrv", "croatian": "hrv",
+	"sl": "slv", "slv": "slv", "slovenian": "slv",
+	"ja": "jpn", "jpn": "jpn", "japanese": "jpn",
+	"ko": "kor", "kor": "kor", "korean": "kor",
+	"zh": "chi", "chi": "chi", "zho": "chi", "chinese": "chi",
+	"ar": "ara", "ara": "ara", "arabic": "ara",
+	"he": "heb", "heb": "heb", "hebrew": "heb",
+	"hi": "hin", "hin": "hin", "hindi": "hin",
+	"th": "tha", "tha": "tha", "thai": "tha",
+	"vi": "vie", "vie": "vie", "vietnamese": "vie",
+}
+
+// subtitleFilenameFlags are filename parts that describe a subtitle rather
+// than its language, e.g. movie.en.forced.srt
+var subtitleFilenameFlags = map[string]bool{
+	"forced": true, "sdh": true, "cc": true, "default": true, "full": true, "signs": true,
+}
+
+// languageStopwords are frequent short words of each language, used to tell
+// the language of subtitle text by how many of them it contains
+var languageStopwords = map[string][]string{
+	"eng": {"the", "and", "you", "that", "is", "what", "this", "have", "it's", "don't", "with", "are"},
+	"dut": {"de", "het", "een", "en", "ik", "je", "niet", "wat", "dat", "is", "van", "hij", "zijn", "maar"},
+	"fre": {"le", "la", "les", "et", "je", "vous", "pas", "est", "que", "une", "c'est", "qui", "pour"},
+	"ger": {"der", "die", "das", "und", "ich", "nicht", "ist", "du", "sie", "ein", "was", "mit", "es"},
+	"spa": {"el", "la", "que", "de", "y", "no", "es", "en", "lo", "los", "por", "qué", "una", "pero"},
+	"ita": {"il", "di", "che", "non", "è", "e", "la", "un", "per", "sono", "mi", "cosa", "questo"},
+	"por": {"o", "a", "que", "não", "de", "é", "um", "uma", "você", "eu", "com", "para", "isso"},
+	"swe": {"och", "det", "att", "jag", "är", "inte", "du", "en", "som", "på", "har", "vad", "med"},
+	"dan": {"og", "det", "at", "jeg", "er", "ikke", "du", "en", "på", "har", "hvad", "med", "til"},
+	"nor": {"og", "det", "at", "jeg", "er", "ikke", "du", "en", "på", "har", "hva", "med", "til", "meg"},
+	"fin": {"ja", "on", "ei", "se", "että", "mitä", "minä", "sinä", "hän", "mutta", "tämä", "olen"},
+	"pol": {"nie", "to", "się", "i", "w", "na", "jest", "że", "co", "jak", "ale", "tak", "ty"},
+	"tur": {"bir", "ve", "bu", "ne", "ben", "sen", "değil", "için", "çok", "mi", "da", "ama"},
+}
+
+var (
+	subtitleTimingRegex = regexp.MustCompile(`-->|^\d+$|^WEBVTT`)
+	subtitleTagRegex    = regexp.MustCompile(`<[^>]*>|\{[^}]*\}`)
+	idxLanguageRegex    = regexp.MustCompile(`^id:\s*([a-z]{2,3})\b`)
+)
+
+// detectSubtitleLanguage guesses the Matroska language code of a subtitle
+// file, first from its filename (movie.nl.srt) and then from its content.
+// It returns "" when the language cannot be told.
+func detectSubtitleLanguage(path string) string {
+	if lang := languageFromFilename(path); lang != "" {
+		return lang
+	}
+	return languageFromContent(path)
+}
+
+// languageFromFilename returns the language named by the dot-separated parts
+// between the title and the extension of a subtitle filename
+func languageFromFilename(path string) string {
+	parts := strings.Split(strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))), ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		if subtitleFilenameFlags[parts[i]] {
+			continue
+		}
+		return subtitleLanguageCodes[parts[i]]
+	}
+	return ""
+}
+
+// languageFromContent reads the start of a text subtitle and returns the
+// language whose stopwords occur most often in its dialogue. VobSub .idx
+// files name their language, which is used instead.
+func languageFromContent(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".sup" {
+		return ""
+	}
+
+	counts := map[string]int{}
+	words := 0
+	scanner := bufio.NewScanner(io.LimitReader(file, 64*1024))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if ext == ".idx" {
+			if m := idxLanguageRegex.FindStringSubmatch(line); m != nil {
+				return subtitleLanguageCodes[m[1]]
+			}
+			continue
+		}
+		if ext == ".ass" || ext == ".ssa" {
+			// Only the text field of dialogue lines holds words
+			if !strings.HasPrefix(line, "Dialogue:") {
+				continue
+			}
+			fields := strings.SplitN(line, ",", 10)
+			if len(fields) < 10 {
+				continue
+			}
+			line = strings.ReplaceAll(fields[9], `\N`, " ")
+		}
+		if line == "" || subtitleTimingRegex.MatchString(line) {
+			continue
+		}
+		line = subtitleTagRegex.ReplaceAllString(line, " ")
+		for _, word := range strings.FieldsFunc(strings.ToLower(line), func(r rune) bool {
+			return !unicode.IsLetter(r) && r != '\''
+		}) {
+			words++
+			for lang, stopwords := range languageStopwords {
+				for _, stopword := range stopwords {
+					if word == stopword {
+						counts[lang]++
+					}
+				}
+			}
+		}
+	}
+
+	// Require a clear winner among enough words, so short or unusual files
+	// keep the language chosen by the user
+	best, second := "", 0
+	for lang, count := range counts {
+		if best == "" || count > counts[best] {
+			if best != "" {
+				second = counts[best]
+			}
+			best = lang
+		} else if count > second {
+			second = count
+		}
+	}
+	if words < 50 || best == "" || counts[best] < words/10 || counts[best] < second*5/4 {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Log levels shown in the log pane
+const (
+	LogInfo  = "INFO"
+	LogDebug = "DEBUG"
+	LogError = "ERROR"
+)
+
+type logEntry struct {
+	Time  time.Time
+	Level string
+	Text  string
+}
+
+func (e logEntry) String() string {
+	return fmt.Sprintf("%s [%s] %s", e.Time.Format("15:04:05"), e.Level, e.Text)
+}
+
+// LogPane is a log of what the extraction is doing, with level filtering,
+// search, copy to clipboard and saving to a file. Messages may be added from
+// any goroutine.
+type LogPane struct {
+	mu             sync.Mutex
+	entries        []logEntry
+	visible        []logEntry
+	levels         map[string]bool
+	search         string
+	refreshPending bool
+
+	window  fyne.Window
+	list    *widget.List
+	content fyne.CanvasObject
+}
+
+// NewLogPane creates an empty log pane; debug messages are hidden until
+// their filter is checked
+func NewLogPane(w fyne.Window) *LogPane {
+	p := &LogPane{
+		window: w,
+		levels: map[string]bool{LogInfo: true, LogDebug: false, LogError: true},
+	}
+
+	p.list = widget.NewList(
+		func() int {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			return len(p.visible)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if id < len(p.visible) {
+				o.(*widget.Label).SetText(p.visible[id].String())
+			}
+		},
+	)
+
+	var filters []fyne.CanvasObject
+	for _, level := range []string{LogInfo, LogDebug, LogError} {
+		level := level
+		check := widget.NewCheck(tr(level[:1]+strings.ToLower(level[1:])), func(checked bool) {
+			p.mu.Lock()
+			p.levels[level] = checked
+			p.mu.Unlock()
+			p.refresh()
+		})
+		check.SetChecked(p.levels[level])
+		filters = append(filters, check)
+	}
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder(tr("Search log..."))
+	searchEntry.OnChanged = func(s string) {
+		p.mu.Lock()
+		p.search = strings.ToLower(s)
+		p.mu.Unlock()
+		p.refresh()
+	}
+
+	copyBtn := widget.NewButton(tr("Copy"), func() {
+		p.window.Clipboard().SetContent(p.Text(true))
+	})
+	saveBtn := widget.NewButton(tr("Save log…"), p.save)
+	clearBtn := widget.NewButton(tr("Clear"), p.Clear)
+
+	toolbar := container.NewBorder(nil, nil,
+		container.NewHBox(filters...),
+		container.NewHBox(copyBtn, saveBtn, clearBtn),
+		searchEntry,
+	)
+
+	// Keep the log readable even when the window is small
+	minSize := canvas.NewRectangle(color.Transparent)
+	minSize.SetMinSize(fyne.NewSize(780, 200))
+	p.content = container.NewBorder(toolbar, nil, nil, nil, container.NewStack(minSize, p.list))
+
+	return p
+}
+
+// Content returns the canvas object to place in the layout
+func (p *LogPane) Content() fyne.CanvasObject {
+	return p.content
+}
+
+// Add logs a message, one entry per non-empty line, at the level that fits its text
+func (p *LogPane) Add(text string) {
+	level := logLevel(text)
+	now := time.Now()
+
+	p.mu.Lock()
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		entry := logEntry{Time: now, Level: level, Text: line}
+		p.entries = append(p.entries, entry)
+		if p.matches(entry) {
+			p.visible = append(p.visible, entry)
+		}
+	}
+	p.mu.Unlock()
+
+	p.scheduleRefresh()
+}
+
+// Clear removes all messages
+func (p *LogPane) Clear() {
+	p.mu.Lock()
+	p.entries = nil
+	p.mu.Unlock()
+	p.refresh()
+}
+
+// Text returns the log as text, either the entries that pass the filters or all of them
+func (p *LogPane) Text(visibleOnly bool) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entries := p.entries
+	if visibleOnly {
+		entries = p.visible
+	}
+	var b strings.Builder
+	for _, entry := range entries {
+		b.WriteString(entry.String() + "\n")
+	}
+	return b.String()
+}
+
+// matches reports whether an entry passes the level and search filters; p.mu must be held
+func (p *LogPane) matches(entry logEntry) bool {
+	return p.levels[entry.Level] && (p.search == "" || strings.Contains(strings.ToLower(entry.Text), p.search))
+}
+
+// refresh filters the entries again and redraws the list
+func (p *LogPane) refresh() {
+	p.mu.Lock()
+	p.visible = nil
+	for _, entry := range p.entries {
+		if p.matches(entry) {
+			p.visible = append(p.visible, entry)
+		}
+	}
+	p.mu.Unlock()
+	p.scheduleRefresh()
+}
+
+// scheduleRefresh redraws the list on the UI thread, coalescing bursts of messages
+func (p *LogPane) scheduleRefresh() {
+	p.mu.Lock()
+	if p.refreshPending {
+		p.mu.Unlock()
+		return
+	}
+	p.refreshPending = true
+	p.mu.Unlock()
+
+	fyne.Do(func() {
+		p.mu.Lock()
+		p.refreshPending = false
+		p.mu.Unlock()
+		p.list.Refresh()
+		p.list.ScrollToBottom()
+	})
+}
+
+// save writes the whole log, regardless of the filters, to a file
+func (p *LogPane) save() {
+	fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+		if _, err := writer.Write([]byte(p.Text(false))); err != nil {
+			dialog.ShowError(fmt.Errorf("Error saving log: %v", err), p.window)
+		}
+	}, p.window)
+	fd.SetFileName(fmt.Sprintf("subtitle-forge-%s.log", time.Now().Format("20060102-150405")))
+	if home, err := os.UserHomeDir(); err == nil {
+		if dir := listableDir(home); dir != nil {
+			fd.SetLocation(dir)
+		}
+	}
+	fd.Show()
+}
+
+// logLevel picks the level of a message from its text
+func logLevel(text string) string {
+	lower := strings.ToLower(text)
+	for _, marker := range []string{"error", "failed", "❌", "⚠️", "warning", "[!]"} {
+		if strings.Contains(lower, marker) {
+			return LogError
+		}
+	}
+	trimmed := strings.TrimSpace(text)
+	for _, marker := range []string{"[debug]", "running:", "command output", "absolute path", "working directory", "path:", "exit code"} {
+		if strings.Contains(lower, marker) {
+			return LogDebug
+		}
+	}
+	if strings.HasPrefix(trimmed, "===") {
+		return LogDebug
+	}
+	return LogInfo
+}
@@ -1,19 +1,18 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"image/color"
 	"io"
-	"log"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -38,11 +37,20 @@ type TrackItem struct {
 	Lang       string
 	Codec      string
 	Name       string
+	Forced     bool
+	Default    bool
+	Entries    int               // Number of subtitle entries, 0 when mkvmerge does not report it
+	Content    string            // trackContentDialogue or trackContentSigns, empty when unknown
+	OutputName string            // Output file name without extension, empty for the default name
+	OutputPath string            // Extracted file, set once the track is done
+	Error      string            // Reason the last extraction of the track failed
+	Properties map[string]string // mkvmerge track properties, formatted for display
 	State      string
 	Check      *widget.Check
 	Status     *widget.Label
 	ConvertOCR *widget.Check  // Option to convert PGS to SRT using OCR
 	LangSelect *widget.Select // Language selection dropdown for OCR
+	Engine     *widget.Select // OCR engine selection dropdown
 }
 
 // checkDependencies verifies if all required external tools are installed
@@ -57,11 +65,7 @@ func checkDependencies() map[string]bool {
 	mkvextractCmd := exec.Command("mkvextract", "--version")
 	results["mkvextract"] = mkvextractCmd.Run() == nil
 
-	// Check for Deno
-	denoCmd := exec.Command("deno", "--version")
-	results["deno"] = denoCmd.Run() == nil
-
-	// Check for Tesseract (optional, as it might be bundled with the script)
+	// Check for Tesseract, which reads the text of PGS subtitles
 	tesseractCmd := exec.Command("tesseract", "--version")
 	results["tesseract"] = tesseractCmd.Run() == nil
 
@@ -123,57 +127,6 @@ func checkDependencies() map[string]bool {
 	fmt.Println("[DEBUG] Final ffmpeg found status:", ffmpegFound)
 	results["ffmpeg"] = ffmpegFound
 
-	// Check for vobsub2srt binary
-	fmt.Println("[DEBUG] Checking for vobsub2srt...")
-	vobsub2srtPath := "/usr/local/bin/vobsub2srt"
-	vobsub2srtFound := false
-
-	// Check if vobsub2srt exists at the expected path
-	if fileInfo, err := os.Stat(vobsub2srtPath); err == nil {
-		fmt.Println("[DEBUG] vobsub2srt exists at", vobsub2srtPath)
-
-		// Check if the file is executable (Unix-style permission check)
-		perm := fileInfo.Mode().Perm()
-		isExecutable := (perm & 0111) != 0 // Check if any execute bit is set
-
-		fmt.Println("[DEBUG] vobsub2srt executable permission check:", isExecutable)
-
-		if isExecutable {
-			// Just verify the binary exists and is executable
-			vobsub2srtFound = true
-			fmt.Println("[DEBUG] vobsub2srt found and is executable")
-		} else {
-			fmt.Println("[DEBUG] vobsub2srt exists but is not executable")
-		}
-	} else {
-		fmt.Println("[DEBUG] vobsub2srt not found at", vobsub2srtPath, "error:", err)
-
-		// Try standard path using which command
-		fmt.Println("[DEBUG] Trying to find vobsub2srt in PATH")
-		whichCmd := exec.Command("which", "vobsub2srt")
-		output, err := whichCmd.CombinedOutput()
-		if err == nil && len(output) > 0 {
-			altPath := strings.TrimSpace(string(output))
-			fmt.Println("[DEBUG] Found vobsub2srt at", altPath)
-
-			// Check if the file exists and is executable
-			info, err := os.Stat(altPath)
-			if err == nil {
-				// Check if the file is executable (Unix-style permission check)
-				perm := info.Mode().Perm()
-				isExecutable := (perm & 0111) != 0 // Check if any execute bit is set
-				
-				vobsub2srtFound = isExecutable
-				fmt.Println("[DEBUG] vobsub2srt executable permission check:", isExecutable)
-			}
-
-			// End of if block
-		}
-	}
-
-	fmt.Println("[DEBUG] Final vobsub2srt found status:", vobsub2srtFound)
-	results["vobsub2srt"] = vobsub2srtFound
-
 	// Check for Go installation
 	fmt.Println("[DEBUG] Checking for Go...")
 	goCmd := exec.Command("go", "version")
@@ -195,11 +148,11 @@ func checkDependencies() map[string]bool {
 // installDependency handles the installation of a specific dependency
 func installDependency(w fyne.Window, tool string) {
 	// Show a confirmation dialog before proceeding
-	confirmMessage := fmt.Sprintf("This will install %s using Homebrew.\n\nDo you want to continue?", tool)
-	dialog.ShowConfirm(fmt.Sprintf("Install %s", tool), confirmMessage, func(confirmed bool) {
+	confirmMessage := trf("This will install %s using Homebrew.", tool) + "\n\n" + tr("Do you want to continue?")
+	dialog.ShowConfirm(trf("Install %s", tool), confirmMessage, func(confirmed bool) {
 		if confirmed {
 			// Create a progress dialog
-			progress := dialog.NewProgress(fmt.Sprintf("Installing %s", tool), "Preparing installation...", w)
+			progress := dialog.NewProgress(trf("Installing %s", tool), tr("Preparing installation..."), w)
 			progress.Show()
 
 			// Run the installation in a goroutine
@@ -212,18 +165,15 @@ func installDependency(w fyne.Window, tool string) {
 				var installDesc string
 
 				// Check if brew is installed first
-				if tool != "vobsub2srt" { // Skip brew check for vobsub2srt as it uses custom script
-					_, err := exec.LookPath("brew")
-					if err != nil {
-						// Hide progress dialog
-						progress.Hide()
+				if _, err := exec.LookPath("brew"); err != nil {
+					// Hide progress dialog
+					progress.Hide()
 
-						// Show error about Homebrew not being installed
-						dialog.ShowError(
-							fmt.Errorf("Homebrew is required but not installed. Please install Homebrew first:\n\nhttps://brew.sh"),
-							w)
-						return
-					}
+					// Show error about Homebrew not being installed
+					dialog.ShowError(
+						errors.New(tr("Homebrew is required but not installed. Please install Homebrew first:")+"\n\nhttps://brew.sh"),
+						w)
+					return
 				}
 
 				// Set up command and description based on tool
@@ -231,53 +181,29 @@ func installDependency(w fyne.Window, tool string) {
 				case "mkvmerge", "mkvextract":
 					// Install MKVToolNix via Homebrew
 					cmd = exec.Command("brew", "install", "mkvtoolnix")
-					installDesc = "Installing MKVToolNix (provides mkvmerge and mkvextract)"
-				case "deno":
-					// Install Deno via Homebrew
-					cmd = exec.Command("brew", "install", "deno")
-					installDesc = "Installing Deno runtime"
+					installDesc = tr("Installing MKVToolNix (provides mkvmerge and mkvextract)")
 				case "tesseract":
 					// Install Tesseract via Homebrew
 					cmd = exec.Command("brew", "install", "tesseract")
-					installDesc = "Installing Tesseract OCR engine"
+					installDesc = tr("Installing Tesseract OCR engine")
 				case "ffmpeg":
 					// Install ffmpeg via Homebrew
 					cmd = exec.Command("brew", "install", "ffmpeg")
-					installDesc = "Installing FFmpeg multimedia framework"
+					installDesc = tr("Installing FFmpeg multimedia framework")
 				case "go":
 					// Install Go via Homebrew
 					cmd = exec.Command("brew", "install", "go")
-					installDesc = "Installing Go programming language"
-				case "vobsub2srt":
-					// Use the custom installation script for VobSub2SRT
-					execPath, err := os.Executable()
-					if err != nil {
-						fmt.Println("[ERROR] Failed to get executable path:", err)
-					}
-
-					scriptPath := filepath.Join(filepath.Dir(execPath), "install_vobsub2srt.sh")
-
-					// Check if script exists
-					if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
-						progress.Hide()
-						dialog.ShowError(
-							fmt.Errorf("Installation script not found: %s", scriptPath),
-							w)
-						return
-					}
-
-					cmd = exec.Command("bash", scriptPath)
-					installDesc = "Installing VobSub2SRT (may require additional dependencies)"
+					installDesc = tr("Installing Go programming language")
 				default:
 					// Hide the progress dialog
 					progress.Hide()
-					dialog.ShowError(fmt.Errorf("Unknown tool: %s", tool), w)
+					dialog.ShowError(errors.New(trf("Unknown tool: %s", tool)), w)
 					return
 				}
 
 				// Update progress dialog with specific tool info
 				progress.Hide()
-				progress = dialog.NewProgress("Installing Dependencies", installDesc, w)
+				progress = dialog.NewProgress(tr("Installing Dependencies"), installDesc, w)
 				progress.Show()
 				progress.SetValue(0.3)
 
@@ -290,7 +216,7 @@ func installDependency(w fyne.Window, tool string) {
 				err := cmd.Start()
 				if err != nil {
 					progress.Hide()
-					dialog.ShowError(fmt.Errorf("Failed to start installation: %v", err), w)
+					dialog.ShowError(errors.New(trf("Failed to start installation: %v", err)), w)
 					return
 				}
 
@@ -306,30 +232,19 @@ func installDependency(w fyne.Window, tool string) {
 
 				if err != nil {
 					// Show detailed error dialog with output and suggestions
-					errorMsg := fmt.Sprintf("Installation of %s failed.\n\nError: %v\n\n", tool, err)
+					errorMsg := trf("Installation of %s failed.", tool) + "\n\n" + trf("Error: %v", err) + "\n\n"
 
 					// Add output but limit it to avoid huge dialog
 					outputStr := string(output)
 					if len(outputStr) > 500 {
-						outputStr = outputStr[:500] + "...\n(output truncated)"
-					}
-					errorMsg += "Output:\n" + outputStr + "\n\n"
-
-					// Add suggestions based on the tool
-					switch tool {
-					case "vobsub2srt":
-						// Get executable path again for suggestion
-						suggestionExecPath, _ := os.Executable()
-						errorMsg += "Suggestions:\n" +
-							"- Make sure cmake is installed (brew install cmake)\n" +
-							"- Make sure tesseract is installed (brew install tesseract)\n" +
-							"- Try running the script manually: bash " + filepath.Join(filepath.Dir(suggestionExecPath), "install_vobsub2srt.sh")
-					default:
-						errorMsg += "Suggestions:\n" +
-							"- Make sure Homebrew is properly installed\n" +
-							"- Try running 'brew doctor' to diagnose Homebrew issues\n" +
-							"- Try installing manually: brew install " + tool
+						outputStr = outputStr[:500] + "...\n" + tr("(output truncated)")
 					}
+					errorMsg += tr("Output:") + "\n" + outputStr + "\n\n"
+
+					errorMsg += tr("Suggestions:") + "\n" +
+						"- " + tr("Make sure Homebrew is properly installed") + "\n" +
+						"- " + tr("Try running 'brew doctor' to diagnose Homebrew issues") + "\n" +
+						"- " + trf("Try installing manually: %s", "brew install "+tool)
 
 					dialog.ShowError(errors.New(errorMsg), w)
 				} else {
@@ -348,8 +263,8 @@ func installDependency(w fyne.Window, tool string) {
 					if successful {
 						// Show success dialog
 						dialog.ShowInformation(
-							"Installation Complete",
-							fmt.Sprintf("%s has been successfully installed.\n\nThe application will now recognize this tool.", tool),
+							tr("Installation Complete"),
+							trf("%s has been successfully installed.", tool)+"\n\n"+tr("The application will now recognize this tool."),
 							w)
 
 						// Update dependency status
@@ -357,8 +272,8 @@ func installDependency(w fyne.Window, tool string) {
 					} else {
 						// Installation seemed to succeed but tool still not found
 						dialog.ShowInformation(
-							"Installation Completed",
-							fmt.Sprintf("The installation process completed, but %s may not be properly installed.\n\nYou may need to restart the application or your computer.", tool),
+							tr("Installation Completed"),
+							trf("The installation process completed, but %s may not be properly installed.", tool)+"\n\n"+tr("You may need to restart the application or your computer."),
 							w)
 					}
 				}
@@ -375,16 +290,16 @@ func updateDependencyStatus(w fyne.Window) {
 	dependencyResults := checkDependencies()
 
 	// Update the status text
-	dependencyStatus := "System Dependency Check:\n"
+	dependencyStatus := tr("System Dependency Check:") + "\n"
 	allDependenciesInstalled := true
 
 	// Track missing tools
 	missingTools := []string{}
 
 	for tool, installed := range dependencyResults {
-		status := "✅ Installed"
+		status := "✅ " + tr("Installed")
 		if !installed {
-			status = "❌ Not found"
+			status = "❌ " + tr("Not found")
 			allDependenciesInstalled = false
 			missingTools = append(missingTools, tool)
 		}
@@ -392,18 +307,18 @@ func updateDependencyStatus(w fyne.Window) {
 	}
 
 	if !allDependenciesInstalled {
-		dependencyStatus += "\n⚠️ Some required tools are missing. Please install them before using all features.\n"
+		dependencyStatus += "\n⚠️ " + tr("Some required tools are missing. Please install them before using all features.") + "\n"
 	} else {
-		dependencyStatus += "\n✅ All required tools are installed.\n"
+		dependencyStatus += "\n✅ " + tr("All required tools are installed.") + "\n"
 	}
 
 	// Find and update the dependency result label in the Settings tab
 	if tabs, ok := w.Content().(*container.AppTabs); ok {
 		for _, tab := range tabs.Items {
-			if tab.Text == "Settings" {
+			if tab.Text == tr("Settings") {
 				if settingsContainer, ok := tab.Content.(*fyne.Container); ok {
 					for _, child := range settingsContainer.Objects {
-						if label, ok := child.(*widget.Label); ok && strings.Contains(label.Text, "System Dependency Check") {
+						if label, ok := child.(*widget.Label); ok && strings.Contains(label.Text, tr("System Dependency Check:")) {
 							label.SetText(dependencyStatus)
 							break
 						}
@@ -417,7 +332,7 @@ func updateDependencyStatus(w fyne.Window) {
 	// Clear existing buttons
 	if tabs, ok := w.Content().(*container.AppTabs); ok {
 		for _, tab := range tabs.Items {
-			if tab.Text == "Settings" {
+			if tab.Text == tr("Settings") {
 				if settingsContainer, ok := tab.Content.(*fyne.Container); ok {
 					for _, child := range settingsContainer.Objects {
 						if buttonContainer, ok := child.(*fyne.Container); ok && len(buttonContainer.Objects) > 0 {
@@ -436,7 +351,7 @@ func updateDependencyStatus(w fyne.Window) {
 	// Add buttons for missing tools
 	if len(missingTools) > 0 {
 		// Create install all button
-		installAllBtn := widget.NewButton("Install All Missing Dependencies", func() {
+		installAllBtn := widget.NewButton(tr("Install All Missing Dependencies"), func() {
 			installDependencies(missingTools, w)
 		})
 		installAllBtn.Importance = widget.HighImportance
@@ -444,7 +359,7 @@ func updateDependencyStatus(w fyne.Window) {
 		// Add to dependency buttons container
 		if tabs, ok := w.Content().(*container.AppTabs); ok {
 			for _, tab := range tabs.Items {
-				if tab.Text == "Settings" {
+				if tab.Text == tr("Settings") {
 					if settingsContainer, ok := tab.Content.(*fyne.Container); ok {
 						for _, child := range settingsContainer.Objects {
 							if buttonContainer, ok := child.(*fyne.Container); ok && len(buttonContainer.Objects) == 0 {
@@ -453,7 +368,7 @@ func updateDependencyStatus(w fyne.Window) {
 
 								// Add individual install buttons
 								for _, tool := range missingTools {
-									installBtn := widget.NewButton(fmt.Sprintf("Install %s", tool), func(t string) func() {
+									installBtn := widget.NewButton(trf("Install %s", tool), func(t string) func() {
 										return func() {
 											installDependencies([]string{t}, w)
 										}
@@ -473,7 +388,7 @@ func updateDependencyStatus(w fyne.Window) {
 // installDependencies installs the specified missing tools
 func installDependencies(tools []string, w fyne.Window) {
 	// Show progress dialog
-	progress := dialog.NewProgressInfinite("Installing Dependencies", "Installing required tools...", w)
+	progress := dialog.NewProgressInfinite(tr("Installing Dependencies"), tr("Installing required tools..."), w)
 	progress.Show()
 
 	// Install dependencies in a goroutine
@@ -490,22 +405,10 @@ func installDependencies(tools []string, w fyne.Window) {
 			switch tool {
 			case "mkvmerge":
 				cmd = exec.Command("brew", "install", "mkvtoolnix")
-			case "deno":
-				cmd = exec.Command("brew", "install", "deno")
 			case "tesseract":
 				cmd = exec.Command("brew", "install", "tesseract")
 			case "ffmpeg":
 				cmd = exec.Command("brew", "install", "ffmpeg")
-			case "vobsub2srt":
-				// Get the script path relative to the executable
-				execPath, err := os.Executable()
-				if err != nil {
-					fmt.Println("[ERROR] Failed to get executable path:", err)
-					execPath = "."
-				}
-				execDir := filepath.Dir(execPath)
-				scriptPath := filepath.Join(execDir, "install_vobsub2srt.sh")
-				cmd = exec.Command("bash", scriptPath)
 			default:
 				fmt.Printf("[ERROR] Unknown tool: %s\n", tool)
 				failureCount++
@@ -527,13 +430,12 @@ func installDependencies(tools []string, w fyne.Window) {
 
 		// Show results
 		if failureCount == 0 {
-			dialog.ShowInformation("Installation Complete",
-				fmt.Sprintf("All %d dependencies have been successfully installed.\n\nPlease restart the application to use all features.", successCount),
+			dialog.ShowInformation(tr("Installation Complete"),
+				trf("All %d dependencies have been successfully installed.", successCount)+"\n\n"+tr("Please restart the application to use all features."),
 				w)
 		} else {
-			dialog.ShowInformation("Installation Results",
-				fmt.Sprintf("%d dependencies installed successfully.\n%d dependencies failed to install.\n\nPlease check the logs for details and try installing the failed dependencies individually.",
-					successCount, failureCount),
+			dialog.ShowInformation(tr("Installation Results"),
+				trf("%d dependencies installed successfully.", successCount)+"\n"+trf("%d dependencies failed to install.", failureCount)+"\n\n"+tr("Please check the logs for details and try installing the failed dependencies individually."),
 				w)
 		}
 
@@ -544,14 +446,14 @@ func installDependencies(tools []string, w fyne.Window) {
 
 func createUtilitiesTab(result *widget.Label) *fyne.Container {
 	// Create a new Label for utilities tab results
-	utilitiesResult := widget.NewLabel("Results will appear here...")
+	utilitiesResult := widget.NewLabel(tr("Results will appear here..."))
 	utilitiesResult.Wrapping = fyne.TextWrapWord
 	utilitiesResultScroll := container.NewScroll(utilitiesResult)
 	utilitiesResultScroll.SetMinSize(fyne.NewSize(850, 200))
 
 	// Create file selection widgets for MKV operations
-	mkvFileLabel := widget.NewLabel("No MKV file selected")
-	selectMkvBtn := widget.NewButton("Select MKV File", func() {
+	mkvFileLabel := widget.NewLabel(tr("No MKV file selected"))
+	selectMkvBtn := widget.NewButton(tr("Select MKV File"), func() {
 		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
 			if err != nil {
 				dialog.ShowError(err, fyne.CurrentApp().Driver().AllWindows()[0])
@@ -563,20 +465,20 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 
 			filePath := reader.URI().Path()
 			if !strings.HasSuffix(strings.ToLower(filePath), ".mkv") {
-				dialog.ShowInformation("Invalid File", "Please select an MKV file", fyne.CurrentApp().Driver().AllWindows()[0])
+				dialog.ShowInformation(tr("Invalid File"), tr("Please select an MKV file"), fyne.CurrentApp().Driver().AllWindows()[0])
 				return
 			}
 
 			mkvFileLabel.SetText(filePath)
-			utilitiesResult.SetText("MKV file selected: " + filePath)
+			utilitiesResult.SetText(tr("MKV file selected: ") + filePath)
 		}, fyne.CurrentApp().Driver().AllWindows()[0])
 		fd.SetFilter(storage.NewExtensionFileFilter([]string{".mkv"}))
 		fd.Show()
 	})
 
 	// Create file selection widgets for SRT operations
-	srtFileLabel := widget.NewLabel("No SRT file selected")
-	selectSrtBtn := widget.NewButton("Select SRT File", func() {
+	srtFileLabel := widget.NewLabel(tr("No SRT file selected"))
+	selectSrtBtn := widget.NewButton(tr("Select SRT File"), func() {
 		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
 			if err != nil {
 				dialog.ShowError(err, fyne.CurrentApp().Driver().AllWindows()[0])
@@ -588,26 +490,26 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 
 			filePath := reader.URI().Path()
 			if !strings.HasSuffix(strings.ToLower(filePath), ".srt") {
-				dialog.ShowInformation("Invalid File", "Please select an SRT file", fyne.CurrentApp().Driver().AllWindows()[0])
+				dialog.ShowInformation(tr("Invalid File"), tr("Please select an SRT file"), fyne.CurrentApp().Driver().AllWindows()[0])
 				return
 			}
 
 			srtFileLabel.SetText(filePath)
-			utilitiesResult.SetText("SRT file selected: " + filePath)
+			utilitiesResult.SetText(tr("SRT file selected: ") + filePath)
 		}, fyne.CurrentApp().Driver().AllWindows()[0])
 		fd.SetFilter(storage.NewExtensionFileFilter([]string{".srt"}))
 		fd.Show()
 	})
 
 	// Create MKV utility operations
-	mkvInfoBtn := widget.NewButton("MKV Info", func() {
+	mkvInfoBtn := widget.NewButton(tr("MKV Info"), func() {
 		mkvPath := mkvFileLabel.Text
-		if mkvPath == "No MKV file selected" {
-			dialog.ShowInformation("No File Selected", "Please select an MKV file first", fyne.CurrentApp().Driver().AllWindows()[0])
+		if mkvPath == tr("No MKV file selected") {
+			dialog.ShowInformation(tr("No File Selected"), tr("Please select an MKV file first"), fyne.CurrentApp().Driver().AllWindows()[0])
 			return
 		}
 
-		utilitiesResult.SetText("Getting MKV information...\n")
+		utilitiesResult.SetText(tr("Getting MKV information...") + "\n")
 
 		// Run mkvinfo command
 		go func() {
@@ -616,19 +518,19 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 
 			fyne.Do(func() {
 				if err != nil {
-					utilitiesResult.SetText(utilitiesResult.Text + "\nError: " + err.Error())
+					utilitiesResult.SetText(utilitiesResult.Text + "\n" + trf("Error: %v", err))
 					return
 				}
 
-				utilitiesResult.SetText("MKV Information for: " + mkvPath + "\n\n" + string(output))
+				utilitiesResult.SetText(trf("MKV Information for: %s", mkvPath) + "\n\n" + string(output))
 			})
 		}()
 	})
 
-	mkvExtractChaptersBtn := widget.NewButton("Extract Chapters", func() {
+	mkvExtractChaptersBtn := widget.NewButton(tr("Extract Chapters"), func() {
 		mkvPath := mkvFileLabel.Text
-		if mkvPath == "No MKV file selected" {
-			dialog.ShowInformation("No File Selected", "Please select an MKV file first", fyne.CurrentApp().Driver().AllWindows()[0])
+		if mkvPath == tr("No MKV file selected") {
+			dialog.ShowInformation(tr("No File Selected"), tr("Please select an MKV file first"), fyne.CurrentApp().Driver().AllWindows()[0])
 			return
 		}
 
@@ -638,7 +540,7 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 		baseName = strings.TrimSuffix(baseName, filepath.Ext(baseName))
 		outputPath := filepath.Join(dir, baseName+"_chapters.txt")
 
-		utilitiesResult.SetText("Extracting chapters to: " + outputPath + "\n")
+		utilitiesResult.SetText(trf("Extracting chapters to: %s", outputPath) + "\n")
 
 		// Run mkvextract command for chapters
 		go func() {
@@ -647,24 +549,24 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 
 			fyne.Do(func() {
 				if err != nil {
-					utilitiesResult.SetText(utilitiesResult.Text + "\nError: " + err.Error())
+					utilitiesResult.SetText(utilitiesResult.Text + "\n" + trf("Error: %v", err))
 					return
 				}
 
-				utilitiesResult.SetText(utilitiesResult.Text + "\nChapters extracted successfully to: " + outputPath + "\n" + string(output))
+				utilitiesResult.SetText(utilitiesResult.Text + "\n" + trf("Chapters extracted successfully to: %s", outputPath) + "\n" + string(output))
 			})
 		}()
 	})
 
 	// Create SRT utility operations
-	srtFixEncodingBtn := widget.NewButton("Fix SRT Encoding", func() {
+	srtFixEncodingBtn := widget.NewButton(tr("Fix SRT Encoding"), func() {
 		srtPath := srtFileLabel.Text
-		if srtPath == "No SRT file selected" {
-			dialog.ShowInformation("No File Selected", "Please select an SRT file first", fyne.CurrentApp().Driver().AllWindows()[0])
+		if srtPath == tr("No SRT file selected") {
+			dialog.ShowInformation(tr("No File Selected"), tr("Please select an SRT file first"), fyne.CurrentApp().Driver().AllWindows()[0])
 			return
 		}
 
-		utilitiesResult.SetText("Fixing SRT encoding...\n")
+		utilitiesResult.SetText(tr("Fixing SRT encoding...") + "\n")
 
 		// Run iconv command to fix encoding
 		go func() {
@@ -672,7 +574,7 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 			backupPath := srtPath + ".bak"
 			if err := copyFile(srtPath, backupPath); err != nil {
 				fyne.Do(func() {
-					utilitiesResult.SetText(utilitiesResult.Text + "\nError creating backup: " + err.Error())
+					utilitiesResult.SetText(utilitiesResult.Text + "\n" + trf("Error creating backup: %v", err))
 				})
 				return
 			}
@@ -683,35 +585,35 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 
 			fyne.Do(func() {
 				if err != nil {
-					utilitiesResult.SetText(utilitiesResult.Text + "\nError: " + err.Error())
+					utilitiesResult.SetText(utilitiesResult.Text + "\n" + trf("Error: %v", err))
 					return
 				}
 
 				// Replace original with converted file
 				if err := os.Rename(srtPath+".tmp", srtPath); err != nil {
-					utilitiesResult.SetText(utilitiesResult.Text + "\nError replacing file: " + err.Error())
+					utilitiesResult.SetText(utilitiesResult.Text + "\n" + trf("Error replacing file: %v", err))
 					return
 				}
 
-				utilitiesResult.SetText(utilitiesResult.Text + "\nSRT encoding fixed successfully.\nOriginal backup saved to: " + backupPath + "\n" + string(output))
+				utilitiesResult.SetText(utilitiesResult.Text + "\n" + tr("SRT encoding fixed successfully.") + "\n" + trf("Original backup saved to: %s", backupPath) + "\n" + string(output))
 			})
 		}()
 	})
 
-	srtFixTimingBtn := widget.NewButton("Fix SRT Timing", func() {
+	srtFixTimingBtn := widget.NewButton(tr("Fix SRT Timing"), func() {
 		srtPath := srtFileLabel.Text
-		if srtPath == "No SRT file selected" {
-			dialog.ShowInformation("No File Selected", "Please select an SRT file first", fyne.CurrentApp().Driver().AllWindows()[0])
+		if srtPath == tr("No SRT file selected") {
+			dialog.ShowInformation(tr("No File Selected"), tr("Please select an SRT file first"), fyne.CurrentApp().Driver().AllWindows()[0])
 			return
 		}
 
 		// Show dialog to get timing offset
 		offsetEntry := widget.NewEntry()
-		offsetEntry.SetPlaceHolder("e.g., +1.5 or -2.3 (seconds)")
+		offsetEntry.SetPlaceHolder(tr("e.g., +1.5 or -2.3 (seconds)"))
 
-		dialog.ShowCustomConfirm("Adjust SRT Timing", "Apply", "Cancel",
+		dialog.ShowCustomConfirm(tr("Adjust SRT Timing"), tr("Apply"), tr("Cancel"),
 			container.NewVBox(
-				widget.NewLabel("Enter timing offset in seconds:"),
+				widget.NewLabel(tr("Enter timing offset in seconds:")),
 				offsetEntry,
 			),
 			func(confirmed bool) {
@@ -720,14 +622,14 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 				}
 
 				offset := offsetEntry.Text
-				utilitiesResult.SetText("Adjusting SRT timing with offset: " + offset + " seconds...\n")
+				utilitiesResult.SetText(trf("Adjusting SRT timing with offset: %s seconds...", offset) + "\n")
 
 				go func() {
 					// Create a backup of the original file
 					backupPath := srtPath + ".bak"
 					if err := copyFile(srtPath, backupPath); err != nil {
 						fyne.Do(func() {
-							utilitiesResult.SetText(utilitiesResult.Text + "\nError creating backup: " + err.Error())
+							utilitiesResult.SetText(utilitiesResult.Text + "\n" + trf("Error creating backup: %v", err))
 						})
 						return
 					}
@@ -736,7 +638,7 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 					content, err := os.ReadFile(srtPath)
 					if err != nil {
 						fyne.Do(func() {
-							utilitiesResult.SetText(utilitiesResult.Text + "\nError reading SRT file: " + err.Error())
+							utilitiesResult.SetText(utilitiesResult.Text + "\n" + trf("Error reading SRT file: %v", err))
 						})
 						return
 					}
@@ -745,7 +647,7 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 					offsetFloat, err := strconv.ParseFloat(offset, 64)
 					if err != nil {
 						fyne.Do(func() {
-							utilitiesResult.SetText(utilitiesResult.Text + "\nInvalid offset format: " + err.Error())
+							utilitiesResult.SetText(utilitiesResult.Text + "\n" + trf("Invalid offset format: %v", err))
 						})
 						return
 					}
@@ -756,13 +658,13 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 					// Write back to file
 					if err := os.WriteFile(srtPath, []byte(adjustedContent), 0644); err != nil {
 						fyne.Do(func() {
-							utilitiesResult.SetText(utilitiesResult.Text + "\nError writing adjusted SRT file: " + err.Error())
+							utilitiesResult.SetText(utilitiesResult.Text + "\n" + trf("Error writing adjusted SRT file: %v", err))
 						})
 						return
 					}
 
 					fyne.Do(func() {
-						utilitiesResult.SetText(utilitiesResult.Text + "\nSRT timing adjusted successfully.\nOriginal backup saved to: " + backupPath)
+						utilitiesResult.SetText(utilitiesResult.Text + "\n" + tr("SRT timing adjusted successfully.") + "\n" + trf("Original backup saved to: %s", backupPath))
 					})
 				}()
 			},
@@ -772,13 +674,13 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 
 	// Create layout for the Utilities tab
 	mkvSection := container.NewVBox(
-		widget.NewLabelWithStyle("MKV Utilities", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(tr("MKV Utilities"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		container.NewHBox(selectMkvBtn, mkvFileLabel),
 		container.NewHBox(mkvInfoBtn, mkvExtractChaptersBtn),
 	)
 
 	srtSection := container.NewVBox(
-		widget.NewLabelWithStyle("SRT Utilities", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(tr("SRT Utilities"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		container.NewHBox(selectSrtBtn, srtFileLabel),
 		container.NewHBox(srtFixEncodingBtn, srtFixTimingBtn),
 	)
@@ -788,7 +690,7 @@ func createUtilitiesTab(result *widget.Label) *fyne.Container {
 		widget.NewSeparator(),
 		srtSection,
 		widget.NewSeparator(),
-		widget.NewLabel("Results:"),
+		widget.NewLabel(tr("Results:")),
 		utilitiesResultScroll,
 	)
 
@@ -893,15 +795,14 @@ func adjustSRTTiming(content string, offsetSeconds float64) string {
 }
 
 func main() {
+	// Conversion progress is shown below the track table
 	trackList := container.NewVBox()
-	// Create a scrollable container for the track list
-	trackListScroll := container.NewScroll(trackList)
-	// Set a minimum size for the track list scroll area to show more tracks
-	trackListScroll.SetMinSize(fyne.NewSize(850, 250))
 
 	// Create app with explicit ID and set metadata directly
 	a := app.NewWithID("com.gmm.subtitleforge")
 	a.SetIcon(theme.FileTextIcon())
+	applyTheme(a)
+	loadTranslations(a)
 
 	// Create main window with explicit name
 	w := a.NewWindow("Subtitle Forge")
@@ -910,7 +811,7 @@ func main() {
 	w.CenterOnScreen()
 
 	// Setup keyboard shortcuts
-	setupKeyboardShortcuts := func(fileOpenFunc, dirChangeFunc, loadTracksFunc, startExtractFunc func()) {
+	setupKeyboardShortcuts := func(fileOpenFunc, dirChangeFunc, loadTracksFunc, startExtractFunc, focusTracksFunc func()) {
 		// Ctrl+O for opening files
 		ctrlO := &desktop.CustomShortcut{KeyName: fyne.KeyO, Modifier: fyne.KeyModifierControl}
 		w.Canvas().AddShortcut(ctrlO, func(shortcut fyne.Shortcut) {
@@ -934,6 +835,12 @@ func main() {
 		w.Canvas().AddShortcut(ctrlE, func(shortcut fyne.Shortcut) {
 			startExtractFunc()
 		})
+
+		// Ctrl+T for moving the keyboard focus to the track table
+		ctrlT := &desktop.CustomShortcut{KeyName: fyne.KeyT, Modifier: fyne.KeyModifierControl}
+		w.Canvas().AddShortcut(ctrlT, func(shortcut fyne.Shortcut) {
+			focusTracksFunc()
+		})
 	}
 
 	// Load window size from preferences or use default size
@@ -989,74 +896,237 @@ func main() {
 	var outDir string
 	var trackItems []*TrackItem
 
-	selectedFile := widget.NewLabel("No MKV file selected.")
-	selectedDir := widget.NewLabel("No output directory selected.")
-	result := widget.NewLabel("Results will appear here...")
-	result.Wrapping = fyne.TextWrapWord
-	// Make the result area larger to show more debug information
-	resultScroll := container.NewScroll(result)
-	resultScroll.SetMinSize(fyne.NewSize(780, 200))
+	// filenameTemplate returns the output filename template from the Settings tab
+	filenameTemplate := func() string {
+		return a.Preferences().StringWithFallback("filename_template", defaultFilenameTemplate)
+	}
+
+	// Sortable table of the subtitle tracks of the current file
+	trackTable := NewTrackTable()
+	trackTable.OnMultipleLanguages = func(t *TrackItem) {
+		showOCRLanguagesDialog(w, t, trackTable.Refresh)
+	}
+
+	// Preview pane showing the first cues or bitmaps of the selected subtitle track
+	previewHint := tr("Select a track to preview its first subtitle cues or images.")
+	previewLabel := widget.NewLabel(previewHint)
+	previewLabel.Wrapping = fyne.TextWrapWord
+	previewImages := container.NewVBox()
+	previewScroll := container.NewScroll(container.NewVBox(previewLabel, previewImages))
+	previewScroll.SetMinSize(fyne.NewSize(250, 250))
+
+	// Details pane listing every mkvmerge property of the selected track
+	detailsHint := tr("Select a track to show its properties.")
+	detailsForm := container.New(layout.NewFormLayout(), widget.NewLabel(detailsHint))
+	detailsScroll := container.NewScroll(detailsForm)
+	showDetails := func(t *TrackItem) {
+		detailsForm.RemoveAll()
+		for _, d := range trackDetails(t) {
+			key := widget.NewLabel(d[0])
+			key.TextStyle = fyne.TextStyle{Bold: true}
+			value := widget.NewLabel(d[1])
+			value.Wrapping = fyne.TextWrapBreak
+			detailsForm.Add(key)
+			detailsForm.Add(value)
+		}
+		detailsScroll.ScrollToTop()
+	}
+
+	selectedFile := widget.NewLabel(tr("No MKV file selected."))
+	selectedDir := widget.NewLabel(tr("No output directory selected."))
+	// Log pane for results and debug information
+	logPane := NewLogPane(w)
+
+	// Extraction queue: every queued MKV file keeps its own track selection
+	var queue []*QueueItem
+	customOutDir := false
+
+	// Restore the output directory chosen in an earlier session
+	if dir := a.Preferences().String("last_output_dir"); listableDir(dir) != nil {
+		outDir = dir
+		customOutDir = true
+		selectedDir.SetText(outDir)
+	}
+	queueList := container.NewVBox()
+	queueListScroll := container.NewScroll(queueList)
+	queueListScroll.SetMinSize(fyne.NewSize(850, 100))
+
+	var refreshQueue func()
+	var refreshHistory func()
+
+	// cancelRun stops the running extraction or queue. Only one runs at a time:
+	// running is set until the worker calls the returned done function.
+	var cancelRun context.CancelFunc
+	running, queueRunning := false, false
+	conflicts := &conflictResolver{w: w}
+	startRun := func() (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelRun = cancel
+		running = true
+		conflicts.Reset()
+		return ctx, func() {
+			cancel()
+			fyne.Do(func() {
+				running = false
+				queueRunning = false
+			})
+		}
+	}
+	runPause := &pauseGate{}
+
+	// startQueue extracts every pending queued file; set with the Start Queue button
+	var startQueue func()
+
+	// showQueueItem makes a queued file the current file and shows its tracks
+	showQueueItem := func(item *QueueItem) {
+		mkvPath = item.Path
+		selectedFile.SetText(mkvPath)
+
+		// Follow the MKV file unless the user picked an output directory
+		if !customOutDir {
+			outDir = filepath.Dir(mkvPath)
+			selectedDir.SetText(outDir)
+		}
+
+		trackItems = item.Tracks
+		trackTable.SetItems(trackItems)
+		previewLabel.SetText(previewHint)
+		previewImages.RemoveAll()
+		detailsForm.Objects = []fyne.CanvasObject{widget.NewLabel(detailsHint)}
+		detailsForm.Refresh()
+		refreshQueue()
+	}
+
+	refreshQueue = func() {
+		queueList.Objects = nil
+		for _, item := range queue {
+			item := item
+			name := filepath.Base(item.Path)
+			if item.Path == mkvPath {
+				name = "▶ " + name
+			}
+			tracksInfo := tr("tracks not loaded")
+			if item.Tracks != nil {
+				tracksInfo = trf("%d of %d tracks selected", item.selectedCount(), len(item.Tracks))
+			}
+			queueList.Add(container.NewHBox(
+				widget.NewLabel("["+item.State+"]"),
+				widget.NewLabel(name),
+				widget.NewLabel(tracksInfo),
+				layout.NewSpacer(),
+				widget.NewButton(tr("Tracks"), func() {
+					showQueueItem(item)
+				}),
+				widget.NewButton(tr("Remove"), func() {
+					queue = removeQueueItem(queue, item)
+					refreshQueue()
+				}),
+			))
+		}
+		queueList.Refresh()
+	}
+	trackTable.OnChanged = refreshQueue
+	trackTable.DefaultName = func(t *TrackItem) string {
+		return defaultOutputName(filenameTemplate(), mkvPath, t)
+	}
+
+	// addToQueue queues MKV files that are not queued yet and shows the first new one
+	addToQueue := func(paths []string) int {
+		var first *QueueItem
+		added := 0
+		for _, p := range paths {
+			if findQueueItem(queue, p) != nil {
+				continue
+			}
+			item := &QueueItem{Path: p, State: "Pending"}
+			queue = append(queue, item)
+			a.Preferences().SetString("last_mkv_dir", filepath.Dir(p))
+			a.Preferences().SetStringList("recent_mkv_files", addRecentFile(a.Preferences().StringList("recent_mkv_files"), p))
+			added++
+			if first == nil {
+				first = item
+			}
+		}
+		if first != nil {
+			showQueueItem(first)
+		}
+		return added
+	}
 
 	// Set up file drop handling
 	w.Canvas().SetOnTypedKey(func(ke *fyne.KeyEvent) {
 		// Handle key events if needed
 	})
 
-	w.SetOnDropped(func(pos fyne.Position, uris []fyne.URI) {
-		if len(uris) > 0 {
-			filePath := uris[0].Path()
-			fileExt := strings.ToLower(filepath.Ext(filePath))
-
-			if fileExt == ".mkv" {
-				// Handle MKV file drop
-				mkvPath = filePath
-				a.SendNotification(&fyne.Notification{
-					Title:   "File Dropped",
-					Content: "MKV file loaded: " + filepath.Base(filePath),
-				})
-
-				// Update UI
-				selectedFile.SetText(mkvPath)
+	// handleExtractDrop queues every MKV file dropped on the Extract tab.
+	// Dropped folders are scanned recursively for MKV files.
+	handleExtractDrop := func(pos fyne.Position, uris []fyne.URI) {
+		var paths []string
+		for _, uri := range uris {
+			if info, err := os.Stat(uri.Path()); err == nil && info.IsDir() {
+				found, err := findMKVFiles(uri.Path())
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("Error scanning folder %s: %v", uri.Path(), err), w)
+					continue
+				}
+				paths = append(paths, found...)
+			} else if strings.ToLower(filepath.Ext(uri.Path())) == ".mkv" {
+				paths = append(paths, uri.Path())
+			}
+		}
 
-				// Set output directory to the same directory as the MKV file
-				outDir = filepath.Dir(mkvPath)
-				selectedDir.SetText(outDir)
+		if len(paths) == 0 {
+			a.SendNotification(&fyne.Notification{
+				Title:   tr("Invalid File"),
+				Content: tr("Please drop MKV files or folders containing MKV files."),
+			})
+			return
+		}
 
-				// Clear previous tracks
-				trackItems = []*TrackItem{}
-				trackList.Objects = nil
-				trackList.Refresh()
+		added := addToQueue(paths)
+		a.SendNotification(&fyne.Notification{
+			Title:   tr("Files Dropped"),
+			Content: trf("%d MKV file(s) added to the queue", added),
+		})
 
-				result.SetText("MKV file dropped and loaded. Output directory automatically set to MKV location. Click 'Load Tracks' to analyze the MKV file.")
-			} else {
-				a.SendNotification(&fyne.Notification{
-					Title:   "Invalid File",
-					Content: "Please drop an MKV file only.",
-				})
+		// Auto-extract mode loads the tracks, applies the active preset and
+		// extracts without further clicks
+		if a.Preferences().Bool("auto_extract_on_drop") {
+			switch {
+			case queueRunning:
+				logPane.Add(trf("%d MKV file(s) added to the running queue.", added))
+			case running:
+				logPane.Add(trf("%d MKV file(s) added to the queue. Click 'Start Queue' once the current extraction is finished.", added))
+			default:
+				startQueue()
 			}
+			return
 		}
-	})
+		logPane.Add(trf("Found %d MKV file(s), %d added to the queue. Click 'Load Tracks' to choose the tracks of the current file, or 'Start Queue' to extract all subtitle tracks of every queued file.", len(paths), added))
+	}
+
+	w.SetOnDropped(handleExtractDrop)
 
 	// Display dependency check results
-	dependencyStatus := "System Dependency Check:\n"
+	dependencyStatus := tr("System Dependency Check:") + "\n"
 	allDependenciesInstalled := true
 
 	for tool, installed := range dependencyResults {
-		status := "✅ Installed"
+		status := "✅ " + tr("Installed")
 		if !installed {
-			status = "❌ Not found"
+			status = "❌ " + tr("Not found")
 			allDependenciesInstalled = false
 		}
 		dependencyStatus += fmt.Sprintf("- %s: %s\n", tool, status)
 	}
 
 	if !allDependenciesInstalled {
-		dependencyStatus += "\n⚠️ Some required tools are missing. Please install them before using all features.\n"
+		dependencyStatus += "\n⚠️ " + tr("Some required tools are missing. Please install them before using all features.") + "\n"
 	} else {
-		dependencyStatus += "\n✅ All required tools are installed.\n"
+		dependencyStatus += "\n✅ " + tr("All required tools are installed.") + "\n"
 	}
 
-	result.SetText(dependencyStatus)
+	logPane.Add(dependencyStatus)
 
 	// Create a container for dependency-related buttons
 	dependencyButtons := container.NewVBox()
@@ -1075,7 +1145,7 @@ func main() {
 	// Add individual install buttons for each missing dependency
 	if len(missingDependencies) > 0 {
 		// Add header for install buttons
-		dependencyButtons.Add(widget.NewLabel("Install Missing Dependencies:"))
+		dependencyButtons.Add(widget.NewLabel(tr("Install Missing Dependencies:")))
 
 		// Add buttons for each missing dependency
 		for _, tool := range missingDependencies {
@@ -1083,7 +1153,7 @@ func main() {
 			toolName := tool
 
 			// Create button with appropriate label
-			buttonLabel := fmt.Sprintf("Install %s", toolName)
+			buttonLabel := trf("Install %s", toolName)
 			installButton := widget.NewButton(buttonLabel, func() {
 				installDependency(w, toolName)
 			})
@@ -1094,14 +1164,14 @@ func main() {
 
 		// Add an "Install All" button if there are multiple missing dependencies
 		if len(missingDependencies) > 1 {
-			installAllButton := widget.NewButton("Install All Missing Dependencies", func() {
+			installAllButton := widget.NewButton(tr("Install All Missing Dependencies"), func() {
 				// Show confirmation dialog
-				dialog.ShowConfirm("Install All Dependencies",
-					"This will attempt to install all missing dependencies.\n\nSome installations may require sudo privileges.\n\nDo you want to continue?",
+				dialog.ShowConfirm(tr("Install All Dependencies"),
+					tr("This will attempt to install all missing dependencies.")+"\n\n"+tr("Some installations may require sudo privileges.")+"\n\n"+tr("Do you want to continue?"),
 					func(confirmed bool) {
 						if confirmed {
 							// Create a simple progress dialog
-							progress := dialog.NewProgress("Installing Dependencies", "Installing missing dependencies...", w)
+							progress := dialog.NewProgress(tr("Installing Dependencies"), tr("Installing missing dependencies..."), w)
 							progress.Show()
 
 							// Run installations in a goroutine
@@ -1122,22 +1192,10 @@ func main() {
 									case "mkvmerge", "mkvextract":
 										// MKVToolNix includes both mkvmerge and mkvextract
 										cmd = exec.Command("brew", "install", "mkvtoolnix")
-									case "deno":
-										cmd = exec.Command("brew", "install", "deno")
 									case "tesseract":
 										cmd = exec.Command("brew", "install", "tesseract")
 									case "ffmpeg":
 										cmd = exec.Command("brew", "install", "ffmpeg")
-									case "vobsub2srt":
-										// Get the script path relative to the executable
-										execPath, err := os.Executable()
-										if err != nil {
-											fmt.Println("[ERROR] Failed to get executable path:", err)
-											execPath = "."
-										}
-										execDir := filepath.Dir(execPath)
-										scriptPath := filepath.Join(execDir, "install_vobsub2srt.sh")
-										cmd = exec.Command("bash", scriptPath)
 									default:
 										fmt.Printf("[ERROR] Unknown tool: %s\n", tool)
 										failureCount++
@@ -1159,13 +1217,12 @@ func main() {
 
 								// Show results
 								if failureCount == 0 {
-									dialog.ShowInformation("Installation Complete",
-										fmt.Sprintf("All %d dependencies have been successfully installed.\n\nPlease restart the application to use all features.", successCount),
+									dialog.ShowInformation(tr("Installation Complete"),
+										trf("All %d dependencies have been successfully installed.", successCount)+"\n\n"+tr("Please restart the application to use all features."),
 										w)
 								} else {
-									dialog.ShowInformation("Installation Results",
-										fmt.Sprintf("%d dependencies installed successfully.\n%d dependencies failed to install.\n\nPlease check the logs for details and try installing the failed dependencies individually.",
-											successCount, failureCount),
+									dialog.ShowInformation(tr("Installation Results"),
+										trf("%d dependencies installed successfully.", successCount)+"\n"+trf("%d dependencies failed to install.", failureCount)+"\n\n"+tr("Please check the logs for details and try installing the failed dependencies individually."),
 										w)
 								}
 
@@ -1187,10 +1244,15 @@ func main() {
 	progress.Max = 1
 	progress.SetValue(0)
 
+	// Progress of the current track and time estimate for the whole run
+	trackProgress := widget.NewProgressBar()
+	etaLabel := widget.NewLabel("")
+	estimate := &runEstimate{}
+
 	currentTrackLabel := widget.NewLabel("")
 
 	// Button to select MKV file
-	fileBtn := widget.NewButton("Select MKV File (or Drag & Drop)", func() {
+	fileBtn := widget.NewButton(tr("Add MKV File (or Drag & Drop)"), func() {
 		// Create a file filter for MKV files
 		filter := storage.NewExtensionFileFilter([]string{".mkv"})
 
@@ -1205,253 +1267,261 @@ func main() {
 
 			// Double-check that it's an MKV file
 			if fileExt != ".mkv" {
-				dialog.ShowError(fmt.Errorf("Please select an MKV file only."), w)
+				dialog.ShowError(errors.New(tr("Please select an MKV file only.")), w)
 				return
 			}
 
-			mkvPath = filePath
-			selectedFile.SetText(mkvPath)
-
-			// Set output directory to the same directory as the MKV file
-			outDir = filepath.Dir(mkvPath)
-			selectedDir.SetText(outDir)
-
-			// Clear previous tracks
-			trackItems = []*TrackItem{}
-			trackList.Objects = nil
-			trackList.Refresh()
+			if addToQueue([]string{filePath}) == 0 {
+				logPane.Add(tr("MKV file is already in the queue."))
+				return
+			}
 
-			result.SetText("MKV file loaded. Output directory automatically set to MKV location. Click 'Load Tracks' to analyze the MKV file.")
+			logPane.Add(tr("MKV file added to the queue. Click 'Load Tracks' to analyze the MKV file."))
 		}, w)
 
 		fd.SetFilter(filter)
+		if dir := listableDir(a.Preferences().String("last_mkv_dir")); dir != nil {
+			fd.SetLocation(dir)
+		}
 		fd.Show()
 	})
 
+	// Button to reload an MKV file processed earlier
+	var recentBtn *widget.Button
+	recentBtn = widget.NewButton(tr("Recent MKVs"), func() {
+		recent := a.Preferences().StringList("recent_mkv_files")
+		if len(recent) == 0 {
+			dialog.ShowInformation(tr("Recent MKVs"), tr("No recent MKV files yet."), w)
+			return
+		}
+
+		var items []*fyne.MenuItem
+		for _, p := range recent {
+			items = append(items, fyne.NewMenuItem(p, func() {
+				if _, err := os.Stat(p); err != nil {
+					a.Preferences().SetStringList("recent_mkv_files", removeRecentFile(a.Preferences().StringList("recent_mkv_files"), p))
+					dialog.ShowError(errors.New(trf("File no longer exists: %s", p)), w)
+					return
+				}
+				if addToQueue([]string{p}) == 0 {
+					showQueueItem(findQueueItem(queue, p))
+					return
+				}
+				logPane.Add(tr("MKV file added to the queue. Click 'Load Tracks' to analyze the MKV file."))
+			}))
+		}
+		items = append(items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem(tr("Clear Recent Files"), func() {
+			a.Preferences().SetStringList("recent_mkv_files", nil)
+		}))
+		widget.ShowPopUpMenuAtRelativePosition(fyne.NewMenu("", items...), w.Canvas(), fyne.NewPos(0, recentBtn.Size().Height), recentBtn)
+	})
+
 	// Button to select output directory (optional, as it's auto-set)
-	dirBtn := widget.NewButton("Change Output Directory", func() {
-		dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+	dirBtn := widget.NewButton(tr("Change Output Directory"), func() {
+		fd := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
 			if err != nil || uri == nil {
 				return
 			}
 
 			outDir = uri.Path()
+			customOutDir = true
 			selectedDir.SetText(outDir)
+			a.Preferences().SetString("last_output_dir", outDir)
 		}, w)
+		if dir := listableDir(outDir); dir != nil {
+			fd.SetLocation(dir)
+		}
+		fd.Show()
 	})
 
 	// Button to load tracks from MKV file
-	loadTracksBtn := widget.NewButton("Load Tracks", func() {
+	loadTracksBtn := widget.NewButton(tr("Load Tracks"), func() {
 		if mkvPath == "" {
-			dialog.ShowError(fmt.Errorf("Please select or drag & drop an MKV file first."), w)
+			dialog.ShowError(errors.New(tr("Please select or drag & drop an MKV file first.")), w)
 			return
 		}
 
-		// Run mkvmerge to get track info
-		cmd := exec.Command("mkvmerge", "-J", mkvPath)
-		output, err := cmd.Output()
+		items, err := loadSubtitleTracks(mkvPath)
 		if err != nil {
-			dialog.ShowError(fmt.Errorf("Error running mkvmerge: %v", err), w)
+			dialog.ShowError(err, w)
 			return
 		}
 
-		// Parse JSON output
-		var mkvInfo map[string]interface{}
-		err = json.Unmarshal(output, &mkvInfo)
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("Error parsing mkvmerge output: %v", err), w)
-			return
-		}
-
-		// Extract tracks
-		tracks, ok := mkvInfo["tracks"].([]interface{})
-		if !ok {
-			dialog.ShowError(fmt.Errorf("No tracks found in MKV file."), w)
-			return
+		// Replace previous tracks, keeping the selection with the queued file
+		item := findQueueItem(queue, mkvPath)
+		if item == nil {
+			item = &QueueItem{Path: mkvPath, State: "Pending"}
+			queue = append(queue, item)
 		}
+		item.Tracks = items
+		showQueueItem(item)
 
-		// Clear previous tracks
-		trackItems = []*TrackItem{}
-		trackList.Objects = nil
-
-		// Process subtitle tracks
-		for _, track := range tracks {
-			trackMap, ok := track.(map[string]interface{})
-			if !ok {
-				continue
-			}
-
-			// Check if this is a subtitle track
-			trackType, ok := trackMap["type"].(string)
-			if !ok || trackType != "subtitles" {
-				continue
-			}
-
-			// Get track properties
-			properties, ok := trackMap["properties"].(map[string]interface{})
-			if !ok {
-				continue
-			}
-
-			trackID := int(trackMap["id"].(float64))
-
-			// Get language with nil check
-			var trackLang string
-			if properties != nil {
-				if lang, ok := properties["language"].(string); ok {
-					trackLang = lang
-				} else {
-					trackLang = "und" // undefined language code
-				}
-			} else {
-				trackLang = "und" // undefined language code
-			}
-
-			trackCodec := trackMap["codec"].(string)
+		logPane.Add(tr("Tracks loaded. Select the tracks you want to extract, then click 'Start Extraction'"))
 
-			// Get track name if available
-			var trackName string
-			if name, ok := properties["track_name"].(string); ok {
-				trackName = name
-			} else {
-				trackName = ""
-			}
-
-			// Create UI elements for this track
-			check := widget.NewCheck("", nil)
-			check.SetChecked(true)
-			status := widget.NewLabel("[ ]")
-
-			// Create track item
-			t := &TrackItem{
-				Num:    trackID,
-				Lang:   trackLang,
-				Codec:  trackCodec,
-				Name:   trackName,
-				State:  "Pending",
-				Check:  check,
-				Status: status,
-			}
-
-			// Add OCR option for PGS subtitles, ASS/SSA subtitles, and VobSub subtitles
-			if t.Codec == "hdmv_pgs_subtitle" || t.Codec == "HDMV PGS" ||
-				strings.Contains(strings.ToLower(t.Codec), "ass") || strings.Contains(strings.ToLower(t.Codec), "ssa") ||
-				strings.Contains(strings.ToLower(t.Codec), "substation") || strings.Contains(strings.ToLower(t.Codec), "sub station") ||
-				t.Codec == "vobsub" || t.Codec == "VobSub" {
-				t.ConvertOCR = widget.NewCheck("", nil)
-				t.ConvertOCR.SetChecked(true)
-
-				// Add language selection for OCR conversion
-				if t.Codec == "hdmv_pgs_subtitle" || t.Codec == "HDMV PGS" || t.Codec == "vobsub" || t.Codec == "VobSub" {
-					// Create language options
-					langOptions := []string{
-						"Auto (" + t.Lang + ")", // Auto option with detected language
-						"English (en)",
-						"French (fr)",
-						"German (de)",
-						"Spanish (es)",
-						"Italian (it)",
-						"Portuguese (pt)",
-						"Dutch (nl)",
-						"Russian (ru)",
-						"Japanese (ja)",
-						"Chinese (zh)",
-						"Korean (ko)",
-						"Czech (cs)",
-						"Polish (pl)",
-						"Swedish (sv)",
-						"Danish (da)",
-						"Finnish (fi)",
-						"Norwegian (no)",
-						"Hungarian (hu)",
-						"Greek (el)",
-						"Turkish (tr)",
-						"Arabic (ar)",
-						"Hebrew (he)",
-						"Thai (th)",
+		// Tell dialogue from signs & songs where a language has several PGS or
+		// ASS tracks; this reads the whole file, so it runs in the background
+		if analyzed := contentAnalysisTracks(items); len(analyzed) > 0 && a.Preferences().BoolWithFallback("detect_track_content", true) {
+			logPane.Add(tr("Analyzing the subtitle tracks for signs & songs..."))
+			path := mkvPath
+			go func() {
+				contents, err := analyzeTrackContent(context.Background(), path, analyzed)
+				fyne.Do(func() {
+					if err != nil {
+						logPane.Add(trf("Could not analyze the subtitle tracks: %v", err))
+						return
 					}
-
-					// Create language dropdown
-					t.LangSelect = widget.NewSelect(langOptions, nil)
-					t.LangSelect.SetSelected("Auto (" + t.Lang + ")")
-				} else {
-					t.LangSelect = nil
-				}
-			} else {
-				t.ConvertOCR = nil
-				t.LangSelect = nil
-			}
-
-			trackItems = append(trackItems, t)
-
-			// Create row for this track
-			trackInfo := widget.NewLabel(fmt.Sprintf("Track %d: %s (%s) %s", trackID, trackLang, trackCodec, trackName))
-
-			var row *fyne.Container
-			if t.ConvertOCR != nil {
-				// For PGS/VobSub subtitles, show OCR option and language selection
-				ocrLabel := widget.NewLabel("Convert to SRT")
-
-				if t.LangSelect != nil {
-					// Add language selection dropdown for OCR-based conversion
-					langLabel := widget.NewLabel("OCR Language:")
-					row = container.NewHBox(check, status, trackInfo, t.ConvertOCR, ocrLabel, langLabel, t.LangSelect)
-				} else {
-					// For ASS/SSA conversion (no OCR language needed)
-					row = container.NewHBox(check, status, trackInfo, t.ConvertOCR, ocrLabel)
-				}
-			} else {
-				// For other subtitle formats
-				row = container.NewHBox(check, status, trackInfo)
-			}
-
-			trackList.Add(row)
+					for _, t := range analyzed {
+						// A track's name tells its content more reliably
+						if t.Content == "" {
+							t.Content = contents[t.Num]
+						}
+						if t.Content != "" {
+							logPane.Add(trf("Track %d (%s): %s", t.Num, t.Lang, tr(t.Content)))
+						}
+					}
+					trackTable.Table.Refresh()
+				})
+			}()
 		}
-		trackList.Refresh()
-
-		result.SetText("Tracks loaded. Select the tracks you want to extract, then click 'Start Extraction'")
 	})
 
-	// Button to start extraction of selected tracks
-	startExtractBtn := widget.NewButton("Start Extraction", func() {
-		if mkvPath == "" || outDir == "" {
-			dialog.ShowError(fmt.Errorf("Please select both MKV file and output directory."), w)
-			return
-		}
-
+	// extractTracks extracts the checked tracks of one MKV file, converting them as requested.
+	// It runs on a goroutine of its own, reports progress through the UI and
+	// closes the returned channel when done.
+	extractTracks := func(ctx context.Context, mkvPath string, outDir string, trackItems []*TrackItem) <-chan struct{} {
+		done := make(chan struct{})
 		go func() {
+			defer close(done)
+
+			// Widgets belong to the UI thread, so the checked tracks and how
+			// each is converted are read there before the workers start
+			type trackSettings struct {
+				Convert bool   // Convert to SRT
+				OCRLang string // Tesseract language(s) to read the track in
+				Engine  string // OCR engine selected for the track
+			}
 			selected := []*TrackItem{}
-			for _, t := range trackItems {
-				if t.Check.Checked {
-					selected = append(selected, t)
+			settings := map[*TrackItem]trackSettings{}
+			fyne.DoAndWait(func() {
+				for _, t := range trackItems {
+					if t.Check.Checked {
+						selected = append(selected, t)
+						settings[t] = trackSettings{
+							Convert: t.ConvertOCR != nil && t.ConvertOCR.Checked,
+							OCRLang: ocrLanguage(t),
+							Engine:  ocrEngine(t),
+						}
+					}
 				}
-			}
+			})
 			if len(selected) == 0 {
 				// Thread-safe UI update
 				fyne.CurrentApp().SendNotification(&fyne.Notification{
-					Title:   "No Tracks",
-					Content: "No tracks selected.",
+					Title:   tr("No Tracks"),
+					Content: tr("No tracks selected."),
 				})
 				return
 			}
 
 			// Set up progress bar
 			fyne.Do(func() {
-				result.SetText("Extracting selected tracks...")
+				logPane.Add(tr("Extracting selected tracks..."))
 				progress.Max = float64(len(selected))
 				progress.SetValue(0)
 			})
 
+			// Tracks are extracted in parallel up to the "parallel_tracks" setting,
+			// with at most "parallel_ocr" of them converted to SRT at a time
+			prefs := fyne.CurrentApp().Preferences()
+			trackSlots := make(chan struct{}, max(prefs.IntWithFallback("parallel_tracks", 1), 1))
+			ocrSlots := make(chan struct{}, max(prefs.IntWithFallback("parallel_ocr", 1), 1))
+
+			// OCR results are shown for correction before the SRT is written, unless turned off in the settings
+			reviewOCR := prefs.BoolWithFallback("ocr_review", true)
+			correctOCR := prefs.BoolWithFallback("ocr_correct", true)
+			ocrItalics := prefs.BoolWithFallback("ocr_italics", true)
+			ocrTop := prefs.BoolWithFallback("ocr_positions", true)
+			ocrSavePartial := prefs.BoolWithFallback("ocr_save_partial", true)
+			ocrCache := prefs.BoolWithFallback("ocr_cache", true)
+			learnOCR := prefs.BoolWithFallback("ocr_learn_rules", true)
+			fallbackEngines := prefs.StringList("ocr_fallback_engines")
+			fallbackThreshold := float64(prefs.IntWithFallback("ocr_fallback_threshold", lowConfidenceThreshold))
+			ocrPreprocess := OCRPreprocess{
+				Scale:     prefs.IntWithFallback("ocr_scale", defaultOCRPreprocess.Scale),
+				Threshold: prefs.IntWithFallback("ocr_threshold", defaultOCRPreprocess.Threshold),
+				Padding:   prefs.IntWithFallback("ocr_padding", defaultOCRPreprocess.Padding),
+				Invert:    prefs.BoolWithFallback("ocr_invert", defaultOCRPreprocess.Invert),
+			}
+			ocrQuality := prefs.StringWithFallback("ocr_quality", OCRQualityFast)
+			ocrReview := func(t *TrackItem) func([]OCRCue) []OCRCue {
+				if !reviewOCR {
+					return nil
+				}
+				return func(cues []OCRCue) []OCRCue {
+					reviewed := make(chan []OCRCue, 1)
+					fyne.Do(func() {
+						showOCRReview(w, trf("Review OCR of Track %d (%s)", t.Num, t.Lang), cues, func(corrected []OCRCue) {
+							reviewed <- corrected
+						})
+					})
+					select {
+					case corrected := <-reviewed:
+						return corrected
+					case <-ctx.Done():
+						return cues
+					}
+				}
+			}
+			var mu sync.Mutex
+			var wg sync.WaitGroup
 			tracksDone := 0
-			var output []byte
-			var err error
+			activeTracks := 0
+
+			// The overall bar counts the finished tracks plus how far each running track has come
+			trackFractions := map[int]float64{}
+			overallProgress := func() float64 {
+				overall := float64(tracksDone)
+				for _, fraction := range trackFractions {
+					overall += fraction
+				}
+				return overall
+			}
+
+			extractTrack := func(i int, t *TrackItem) {
+				var output []byte
+				var err error
+				mu.Lock()
+				activeTracks++
+				mu.Unlock()
+				defer func() {
+					mu.Lock()
+					delete(trackFractions, i)
+					tracksDone++
+					activeTracks--
+					mu.Unlock()
+				}()
 
-			for i, t := range selected {
 				// Update UI on main thread
 				fyne.Do(func() {
-					currentTrackLabel.SetText(fmt.Sprintf("Extracting track %d of %d: %s (%s) %s", i+1, len(selected), t.Lang, t.Codec, t.Name))
+					currentTrackLabel.SetText(trf("Extracting track %d of %d: %s (%s) %s", i+1, len(selected), t.Lang, t.Codec, t.Name))
 				})
 
+				// Report how far this track has come, for the track bar and the overall estimate
+				trackStart := time.Now()
+				reportProgress := func(fraction float64) {
+					mu.Lock()
+					trackFractions[i] = fraction
+					mu.Unlock()
+					fyne.Do(func() {
+						trackProgress.SetValue(fraction)
+						mu.Lock()
+						progress.SetValue(overallProgress())
+						mu.Unlock()
+						etaLabel.SetText(estimate.Describe(fraction))
+					})
+				}
+				reportProgress(0)
+
 				// Extract the subtitle track
 				var outFile string
 
@@ -1459,14 +1529,34 @@ func main() {
 				mkvBaseName := filepath.Base(mkvPath)
 				mkvBaseName = strings.TrimSuffix(mkvBaseName, filepath.Ext(mkvBaseName))
 
+				// Use the output name edited by the user, if any
+				outName := t.OutputName
+				if outName == "" {
+					outName = defaultOutputName(filenameTemplate(), mkvPath, t)
+				}
+
+				// Ask what to do when the output file already exists
+				switch conflicts.Resolve(ctx, filepath.Join(outDir, outName+"."+outputFileExt(t))) {
+				case ConflictSkip:
+					fyne.Do(func() {
+						t.State = "Skipped"
+						t.Status.SetText(fmt.Sprintf("[-] Track %d: %s (%s) %s - Skipped", t.Num, t.Lang, t.Codec, t.Name))
+						trackTable.Refresh()
+					})
+					estimate.TrackDone(time.Since(trackStart))
+					return
+				case ConflictRename:
+					outName = uniqueOutputName(outDir, outName, outputFileExt(t))
+				}
+
 				// Check if this is a PGS track with OCR conversion requested
-				if t.ConvertOCR != nil && t.ConvertOCR.Checked && (t.Codec == "hdmv_pgs_subtitle" || t.Codec == "HDMV PGS") {
+				if settings[t].Convert && (t.Codec == "hdmv_pgs_subtitle" || t.Codec == "HDMV PGS") {
 					// First extract as PGS
 					fyne.Do(func() {
-						result.SetText(result.Text + "\n\n[DEBUG] Starting PGS extraction process")
+						logPane.Add("\n\n[DEBUG] Starting PGS extraction process")
 					})
-					tempPgsFile := fmt.Sprintf("%s.track%d_%s.sup", mkvBaseName, t.Num, t.Lang)
-					outFile = fmt.Sprintf("%s.track%d_%s.srt", mkvBaseName, t.Num, t.Lang) // Final output will be SRT
+					tempPgsFile := outName + ".sup"
+					outFile = outName + ".srt" // Final output will be SRT
 
 					// Get absolute paths for extraction
 					absPgsPath := filepath.Join(outDir, tempPgsFile)
@@ -1474,31 +1564,33 @@ func main() {
 					// Debug output
 					fyne.Do(func() {
 						currentTrackLabel.SetText(fmt.Sprintf("Extracting PGS track %d...", t.Num))
-						result.SetText(result.Text + "\n\n=== PGS Extraction ===\n")
-						result.SetText(result.Text + fmt.Sprintf("Track: %d (%s)\n", t.Num, t.Lang))
-						result.SetText(result.Text + fmt.Sprintf("Output directory: %s\n", outDir))
-						result.SetText(result.Text + fmt.Sprintf("PGS file: %s\n", tempPgsFile))
-						result.SetText(result.Text + fmt.Sprintf("Absolute path: %s\n", absPgsPath))
+						logPane.Add("\n\n=== PGS Extraction ===\n")
+						logPane.Add(fmt.Sprintf("Track: %d (%s)\n", t.Num, t.Lang))
+						logPane.Add(fmt.Sprintf("Output directory: %s\n", outDir))
+						logPane.Add(fmt.Sprintf("PGS file: %s\n", tempPgsFile))
+						logPane.Add(fmt.Sprintf("Absolute path: %s\n", absPgsPath))
 					})
 
 					// Extract PGS first - use full command for debugging
 					cmdStr := fmt.Sprintf("mkvextract tracks \"%s\" %d:\"%s\"", mkvPath, t.Num, tempPgsFile)
 					fyne.Do(func() {
-						result.SetText(result.Text + "\nRunning: " + cmdStr)
+						logPane.Add("\nRunning: " + cmdStr)
 					})
 
 					// Create the command with proper arguments
-					cmd := exec.Command("mkvextract", "tracks", mkvPath, fmt.Sprintf("%d:%s", t.Num, tempPgsFile))
+					cmd := exec.CommandContext(ctx, "mkvextract", "tracks", mkvPath, fmt.Sprintf("%d:%s", t.Num, tempPgsFile))
 					cmd.Dir = outDir
 
 					// Run the command and capture output
-					output, err = cmd.CombinedOutput()
+					output, err = runWithProgress(cmd, func(percent int) {
+						reportProgress(float64(percent) / 100)
+					})
 
 					// Debug output - show command result
 					fyne.Do(func() {
-						result.SetText(result.Text + "\nCommand output: " + string(output))
+						logPane.Add("\nCommand output: " + string(output))
 						if err != nil {
-							result.SetText(result.Text + "\nError: " + err.Error())
+							logPane.Add("\nError: " + err.Error())
 						}
 					})
 
@@ -1507,17 +1599,17 @@ func main() {
 					fileInfo, statErr := os.Stat(pgsFilePath)
 					if statErr != nil {
 						fyne.Do(func() {
-							result.SetText(result.Text + "\nCannot find extracted file: " + statErr.Error())
+							logPane.Add("\nCannot find extracted file: " + statErr.Error())
 						})
 						err = statErr
 					} else if fileInfo.Size() == 0 {
 						fyne.Do(func() {
-							result.SetText(result.Text + "\nExtracted file is empty (0 bytes)")
+							logPane.Add("\nExtracted file is empty (0 bytes)")
 						})
 						err = fmt.Errorf("extracted file is empty (0 bytes)")
 					} else {
 						fyne.Do(func() {
-							result.SetText(result.Text + fmt.Sprintf("\nSuccessfully extracted PGS file (%d bytes)", fileInfo.Size()))
+							logPane.Add(fmt.Sprintf("\nSuccessfully extracted PGS file (%d bytes)", fileInfo.Size()))
 						})
 					}
 
@@ -1529,10 +1621,22 @@ func main() {
 						conversionProgress.Max = 100 // Percentage-based progress
 						conversionProgress.SetValue(0)
 
-						conversionLabel := widget.NewLabel("Converting PGS to SRT...")
-						statusLabel := widget.NewLabel("Initializing OCR process...")
-						elapsedLabel := widget.NewLabel("Elapsed: 0s")
-						remainingLabel := widget.NewLabel("Estimated time remaining: calculating...")
+						conversionLabel := widget.NewLabel(tr("Converting PGS to SRT..."))
+						statusLabel := widget.NewLabel(tr("Initializing OCR process..."))
+						elapsedLabel := widget.NewLabel(tr("Elapsed: 0s"))
+						remainingLabel := widget.NewLabel(tr("Estimated time remaining: calculating..."))
+
+						// Every track converted in parallel shows a box of its own
+						conversionBox := container.NewVBox(
+							conversionLabel,
+							statusLabel,
+							conversionProgress,
+							container.NewHBox(
+								elapsedLabel,
+								widget.NewLabel("|"),
+								remainingLabel,
+							),
+						)
 
 						// Track conversion start time and progress data
 						conversionStartTime := time.Now()
@@ -1549,13 +1653,20 @@ func main() {
 							lastUpdate:   time.Now(),
 						}
 
-						// Create a ticker to update elapsed time and estimated remaining time
+						// Create a ticker to update elapsed time and estimated remaining time,
+						// until tickerDone is closed at the end of the conversion
 						ticker := time.NewTicker(500 * time.Millisecond)
+						tickerDone := make(chan struct{})
 						go func() {
 							defer ticker.Stop()
 							var lastElapsedText, lastRemainingText string
 
-							for range ticker.C {
+							for {
+								select {
+								case <-tickerDone:
+									return
+								case <-ticker.C:
+								}
 								elapsed := time.Since(conversionStartTime).Round(time.Second)
 								newElapsedText := fmt.Sprintf("Elapsed: %s", elapsed)
 
@@ -1600,488 +1711,109 @@ func main() {
 						}()
 
 						fyne.Do(func() {
-							result.SetText(result.Text + "\n\n[DEBUG] PGS extraction completed successfully, starting conversion process")
+							logPane.Add("\n\n[DEBUG] PGS extraction completed successfully, starting conversion process")
 
 							// Show the conversion progress bar and labels
-							currentTrackLabel.SetText("Converting PGS to SRT...")
+							currentTrackLabel.SetText(tr("Converting PGS to SRT..."))
 							progress.Hide()
-							trackList.Add(container.NewVBox(
-								conversionLabel,
-								statusLabel,
-								conversionProgress,
-								container.NewHBox(
-									elapsedLabel,
-									widget.NewLabel("|"),
-									remainingLabel,
-								),
-							))
+							trackList.Add(conversionBox)
 							trackList.Refresh()
 						})
 
-						// Use the user's custom pgs-to-srt-2 tool with Deno
-						pgsToSrtScript := "/Users/venimk/Downloads/pgs-to-srt-2/pgs-to-srt.js"
-						// Get language from user selection or use track language as default
-						langCode := "eng" // Default to English
-						if t.Lang != "" {
-							langCode = t.Lang
-						}
-
-						// Check if user has selected a specific language
-						if t.LangSelect != nil && t.LangSelect.Selected != "" && !strings.HasPrefix(t.LangSelect.Selected, "Auto") {
-							// Extract the language code from the selection (format: "Language (code)")
-							selection := t.LangSelect.Selected
-							// Extract the code part between parentheses
-							if start := strings.LastIndex(selection, "("); start != -1 {
-								if end := strings.LastIndex(selection, ")"); end != -1 && end > start {
-									// Extract the 2-letter code
-									twoLetterCode := selection[start+1 : end]
-									fyne.Do(func() {
-										result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] User selected OCR language: %s (code: %s)", selection, twoLetterCode))
-									})
+						// Read the track in the language(s) selected for it, or its own language
+						langCode := settings[t].OCRLang
+						absInputPath := filepath.Join(outDir, tempPgsFile)
+						absOutputPath := filepath.Join(outDir, outFile)
 
-									// Map 2-letter code to 3-letter code for Tesseract
-									langCodeMap := map[string]string{
-										"en": "eng", // English
-										"fr": "fra", // French
-										"de": "deu", // German
-										"it": "ita", // Italian
-										"es": "spa", // Spanish
-										"pt": "por", // Portuguese
-										"nl": "nld", // Dutch
-										"sv": "swe", // Swedish
-										"no": "nor", // Norwegian
-										"da": "dan", // Danish
-										"fi": "fin", // Finnish
-										"ja": "jpn", // Japanese
-										"ko": "kor", // Korean
-										"zh": "chi", // Chinese
-										"ru": "rus", // Russian
-										"pl": "pol", // Polish
-										"cs": "ces", // Czech
-										"hu": "hun", // Hungarian
-										"el": "ell", // Greek
-										"tr": "tur", // Turkish
-										"ar": "ara", // Arabic
-										"he": "heb", // Hebrew
-										"th": "tha", // Thai
-									}
+						fyne.Do(func() {
+							logPane.Add("\n\n=== PGS OCR ===\n")
+							logPane.Add(fmt.Sprintf("Input SUP file: %s\nOutput SRT file: %s\nOCR language: %s\n", absInputPath, absOutputPath, langCode))
+						})
 
-									// Convert 2-letter code to 3-letter code if a mapping exists
-									if threeLetterCode, exists := langCodeMap[twoLetterCode]; exists {
-										langCode = threeLetterCode
-										fyne.Do(func() {
-											result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] Mapped language code for OCR: %s -> %s", twoLetterCode, langCode))
-										})
+						// Parse the SUP file here and read every subtitle bitmap with the track's OCR engine
+						ocrSlots <- struct{}{}
+						ocrResult, ocrErr := convertPGSToSRT(ctx, absInputPath, absOutputPath, OCROptions{Engine: settings[t].Engine, Lang: langCode, Quality: ocrQuality, Correct: correctOCR, Italics: ocrItalics, Top: ocrTop, SavePartial: ocrSavePartial, Cache: ocrCache, Preprocess: ocrPreprocess, Fallback: fallbackEngines, FallbackThreshold: fallbackThreshold, Charset: ocrCharset(langCode), Rules: loadOCRRules(prefs), LearnRules: learnOCR, Review: ocrReview(t)}, func(done, total int) {
+							progressMutex.Lock()
+							if progressData.currentFrame > 0 {
+								timeDiff := time.Since(progressData.lastUpdate).Seconds()
+								if timeDiff > 0 {
+									// Smooth the frame rate with a weighted average for steadier estimates
+									newFrameRate := float64(done-progressData.currentFrame) / timeDiff
+									if progressData.frameRate > 0 {
+										progressData.frameRate = progressData.frameRate*0.7 + newFrameRate*0.3
 									} else {
-										// If no mapping exists, use the 2-letter code directly
-										langCode = twoLetterCode
-										fyne.Do(func() {
-											result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] Using language code as-is for OCR: %s", langCode))
-										})
+										progressData.frameRate = newFrameRate
 									}
 								}
 							}
-						}
+							progressData.currentFrame = done
+							progressData.totalFrames = total
+							progressData.lastUpdate = time.Now()
+							progressMutex.Unlock()
 
-						// Define the path to the trained data file with the selected language
-						trainedDataPath := filepath.Join(filepath.Dir(pgsToSrtScript), "tessdata_fast", langCode+".traineddata")
+							percentComplete := float64(done) / float64(total) * 100
+							fyne.Do(func() {
+								statusLabel.SetText(fmt.Sprintf("Processing frame %d of %d (%.1f%%)", done, total, percentComplete))
+							})
+							reportProgress(percentComplete / 100)
+						})
+						err = ocrErr
+						<-ocrSlots
+						output = []byte(ocrResult.String())
+						close(tickerDone)
 
-						// Get absolute paths for input and output
-						absInputPath := filepath.Join(outDir, tempPgsFile)
-						absOutputPath := filepath.Join(outDir, outFile)
+						// Prepare output text in memory before updating UI
+						var outputText strings.Builder
+						outputText.WriteString("\nFull command output:\n")
 
-						// Check if the script exists
-						fyne.Do(func() {
-							result.SetText(result.Text + fmt.Sprintf("\n\n[DEBUG] Checking if script exists at: %s", pgsToSrtScript))
-						})
+						// Limit output size to prevent UI sluggishness with very large outputs
+						outputStr := string(output)
+						const maxOutputLen = 10000 // Limit output to 10K chars
+						if len(outputStr) > maxOutputLen {
+							outputText.WriteString(outputStr[:maxOutputLen])
+							outputText.WriteString("\n... [Output truncated, full output in log file] ...")
+						} else {
+							outputText.WriteString(outputStr)
+						}
 
-						if _, statErr := os.Stat(pgsToSrtScript); statErr != nil {
-							fyne.Do(func() {
-								result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] Script NOT found: %v", statErr))
-							})
-							return
+						// Add error message if needed
+						if err != nil {
+							outputText.WriteString("\n\n❌ Command error: " + err.Error())
 						}
 
+						// Update UI in a single operation
 						fyne.Do(func() {
-							result.SetText(result.Text + "\n[DEBUG] Script found!")
+							logPane.Add(outputText.String())
 						})
 
-						// Test if Deno is working correctly
-						fyne.Do(func() {
-							result.SetText(result.Text + "\n[DEBUG] Running Deno version test...")
-						})
-						testCmd := exec.Command("deno", "--version")
-						testOutput, testErr := testCmd.CombinedOutput()
+						// Show output
 						fyne.Do(func() {
-							result.SetText(result.Text + "\n\n=== Deno Version Test ===\n")
-							if testErr != nil {
-								result.SetText(result.Text + fmt.Sprintf("Deno test error: %v\n", testErr))
+							// Calculate total conversion time
+							conversionTime := time.Since(conversionStartTime).Round(time.Second)
+
+							// Update status based on success or failure
+							if err != nil {
+								currentTrackLabel.SetText(fmt.Sprintf("Conversion failed after %s", conversionTime))
 							} else {
-								result.SetText(result.Text + fmt.Sprintf("Deno version: %s\n", string(testOutput)))
-							}
-						})
-
-						// Show detailed file information
-						// Build text updates in memory before applying to UI
-						textUpdate := fmt.Sprintf("\nInput SUP file: %s\nOutput SRT file: %s\nTessdata file: %s\n",
-							absInputPath, absOutputPath, trainedDataPath)
-
-						fyne.Do(func() {
-							result.SetText(result.Text + textUpdate)
-
-							// Check if input file exists and show size
-							if fileInfo, err := os.Stat(absInputPath); err == nil {
-								result.SetText(result.Text + fmt.Sprintf("Input file size: %d bytes\n", fileInfo.Size()))
-							} else {
-								result.SetText(result.Text + fmt.Sprintf("Input file check error: %v\n", err))
-							}
-						})
-
-						// Variables to track file copy status
-						var copyErr error
-						var copySuccess bool
-
-						// Create a temporary file for the output to avoid permission issues
-						tmpOutputFile, tmpErr := os.CreateTemp("", "pgs_to_srt_*.srt")
-						if tmpErr != nil {
-							fyne.Do(func() {
-								result.SetText(result.Text + fmt.Sprintf("\n\n⚠️ Could not create temporary file: %v", tmpErr))
-							})
-							return
-						}
-						tmpOutputPath := tmpOutputFile.Name()
-						tmpOutputFile.Close() // Close it so the script can write to it
-
-						// Build and show the command - the script expects trained data path and input file, with output redirected
-						cmdStr := fmt.Sprintf("deno run --allow-read --allow-write \"%s\" \"%s\" \"%s\" > \"%s\"", pgsToSrtScript, trainedDataPath, absInputPath, tmpOutputPath)
-						// Combine text updates to reduce UI operations
-						updateText := fmt.Sprintf("\n\n=== Executing Command ===\n%s\n\nConversion started at: %s\n",
-							cmdStr, time.Now().Format("15:04:05"))
-
-						fyne.Do(func() {
-							result.SetText(result.Text + updateText)
-						})
-
-						// Create a log file for real-time monitoring of the PGS to SRT conversion process
-						logFileName := filepath.Join(outDir, fmt.Sprintf("%s.track%d_%s.conversion.log", mkvBaseName, t.Num, t.Lang))
-						logFile, logErr := os.Create(logFileName)
-
-						// Create a logger that will be used throughout this function
-						var logger *log.Logger
-
-						if logErr != nil {
-							fyne.Do(func() {
-								result.SetText(result.Text + fmt.Sprintf("\n\n⚠️ Could not create log file: %v", logErr))
-							})
-						} else {
-							defer logFile.Close()
-							logger = log.New(logFile, "", log.LstdFlags)
-							logger.Printf("=== PGS to SRT Conversion Log ===\n")
-							logger.Printf("Started at: %s\n", time.Now().Format("15:04:05"))
-							logger.Printf("Input file: %s\n", absInputPath)
-							logger.Printf("Final output file: %s\n", absOutputPath)
-							logger.Printf("Temporary output file: %s\n", tmpOutputPath)
-							logger.Printf("Script: %s\n", pgsToSrtScript)
-							logger.Printf("Trained data: %s\n", trainedDataPath)
-							logger.Printf("Working directory: %s\n", filepath.Dir(pgsToSrtScript))
-							logger.Printf("PATH: %s\n\n", os.Getenv("PATH"))
-
-							fyne.Do(func() {
-								result.SetText(result.Text + fmt.Sprintf("\n📝 Created log file: %s", logFileName))
-								result.SetText(result.Text + fmt.Sprintf("\n📂 Using temporary file: %s", tmpOutputPath))
-							})
-						}
-
-						// Run the conversion tool with Deno - using shell to enable output redirection
-						cmd = exec.Command("sh", "-c", fmt.Sprintf("deno run --allow-read --allow-write \"%s\" \"%s\" \"%s\" > \"%s\"",
-							pgsToSrtScript, trainedDataPath, absInputPath, tmpOutputPath))
-
-						// Set the working directory to ensure relative paths work correctly
-						cmd.Dir = filepath.Dir(pgsToSrtScript)
-
-						// Print the environment and command for debugging
-						fyne.Do(func() {
-							result.SetText(result.Text + "\n\n=== Environment ===\n")
-							result.SetText(result.Text + fmt.Sprintf("Working directory: %s\n", cmd.Dir))
-							result.SetText(result.Text + fmt.Sprintf("PATH: %s\n", os.Getenv("PATH")))
-							result.SetText(result.Text + "\n=== Command ===\n")
-							result.SetText(result.Text + fmt.Sprintf("deno run --allow-read --allow-write %s %s %s > %s\n",
-								pgsToSrtScript, trainedDataPath, absInputPath, tmpOutputPath))
-						})
-
-						// Set up pipes to capture output in real-time
-						stdoutPipe, _ := cmd.StdoutPipe()
-						stderrPipe, _ := cmd.StderrPipe()
-
-						// Start the command
-						startErr := cmd.Start()
-						if startErr != nil {
-							fyne.Do(func() {
-								result.SetText(result.Text + fmt.Sprintf("\n\n❌ Failed to start command: %v", startErr))
-							})
-							if logFile != nil && logger != nil {
-								logger.Printf("Failed to start command: %v\n", startErr)
-							}
-							err = startErr
-						} else {
-							fyne.Do(func() {
-								result.SetText(result.Text + "\n\n=== Starting Conversion Process ===\n")
-								result.SetText(result.Text + "Check the log file for real-time output\n")
-							})
-
-							// Create a multi-writer to write to both the log file and capture the output
-							var outputBuffer strings.Builder
-							var stdoutWriter, stderrWriter io.Writer
-							if logFile != nil && logger != nil {
-								stdoutWriter = io.MultiWriter(logFile, &outputBuffer)
-								stderrWriter = io.MultiWriter(logFile, &outputBuffer)
-								logger.Printf("Command started successfully\n")
-							} else {
-								stdoutWriter = &outputBuffer
-								stderrWriter = &outputBuffer
-							}
-
-							// Regular expressions to extract progress information from the output
-							frameProgressRegex := regexp.MustCompile(`Processing frame (\d+)/(\d+)`)
-							statusUpdateRegex := regexp.MustCompile(`Status: (.+)`)
-
-							// Copy stdout and stderr to the writers in a buffered way to reduce UI updates
-							go func() {
-								bufReader := bufio.NewReaderSize(stdoutPipe, 4096) // Use larger buffer
-								scanner := bufio.NewScanner(bufReader)
-								for scanner.Scan() {
-									line := scanner.Text() + "\n"
-
-									// Check for progress information in the output
-									if matches := frameProgressRegex.FindStringSubmatch(line); len(matches) == 3 {
-										// Extract current frame and total frames
-										currentFrame := 0
-										totalFrames := 0
-										fmt.Sscanf(matches[1], "%d", &currentFrame)
-										fmt.Sscanf(matches[2], "%d", &totalFrames)
-
-										progressMutex.Lock()
-										// Update progress data
-										if progressData.totalFrames == 0 {
-											progressData.totalFrames = totalFrames
-										}
-
-										// Calculate frame rate
-										if progressData.currentFrame > 0 {
-											timeDiff := time.Since(progressData.lastUpdate).Seconds()
-											frameDiff := currentFrame - progressData.currentFrame
-											if timeDiff > 0 && frameDiff > 0 {
-												// Smooth the frame rate calculation with a weighted average
-												newFrameRate := float64(frameDiff) / timeDiff
-												if progressData.frameRate > 0 {
-													// 70% old rate, 30% new rate for smoother estimates
-													progressData.frameRate = progressData.frameRate*0.7 + newFrameRate*0.3
-												} else {
-													progressData.frameRate = newFrameRate
-												}
-											}
-										}
-
-										progressData.currentFrame = currentFrame
-										progressData.lastUpdate = time.Now()
-										progressMutex.Unlock()
-
-										// Update status label
-										percentComplete := float64(currentFrame) / float64(totalFrames) * 100
-										fyne.Do(func() {
-											statusLabel.SetText(fmt.Sprintf("Processing frame %d of %d (%.1f%%)",
-												currentFrame, totalFrames, percentComplete))
-										})
-									} else if matches := statusUpdateRegex.FindStringSubmatch(line); len(matches) == 2 {
-										// Update status message
-										statusMsg := matches[1]
-										fyne.Do(func() {
-											statusLabel.SetText(statusMsg)
-										})
-									}
-
-									if _, writeErr := stdoutWriter.Write([]byte(line)); writeErr != nil {
-										break
-									}
-								}
-							}()
-
-							go func() {
-								bufReader := bufio.NewReaderSize(stderrPipe, 4096) // Use larger buffer
-								scanner := bufio.NewScanner(bufReader)
-								for scanner.Scan() {
-									line := scanner.Text() + "\n"
-
-									// Also check stderr for progress information
-									if matches := frameProgressRegex.FindStringSubmatch(line); len(matches) == 3 {
-										// Process frame progress from stderr (same as stdout handler)
-										currentFrame := 0
-										totalFrames := 0
-										fmt.Sscanf(matches[1], "%d", &currentFrame)
-										fmt.Sscanf(matches[2], "%d", &totalFrames)
-
-										progressMutex.Lock()
-										// Update progress data
-										if progressData.totalFrames == 0 {
-											progressData.totalFrames = totalFrames
-										}
-										progressData.currentFrame = currentFrame
-										progressMutex.Unlock()
-									}
-
-									if _, writeErr := stderrWriter.Write([]byte(line)); writeErr != nil {
-										break
-									}
-								}
-							}()
-
-							// Wait for the command to complete
-							err = cmd.Wait()
-							output = []byte(outputBuffer.String())
-
-							// Log the completion status
-							if logFile != nil && logger != nil {
-								if err != nil {
-									logger.Printf("\n\nCommand completed with error: %v\n", err)
-								} else {
-									logger.Printf("\n\nCommand completed successfully\n")
-								}
-								logger.Printf("Finished at: %s\n", time.Now().Format("15:04:05"))
-							}
-
-							// Copy the temporary file to the final destination regardless of command success/failure
-							// This allows us to potentially recover partial conversions even if the command had issues
-
-							// Check if the temporary file exists before attempting to copy
-							if _, statErr := os.Stat(tmpOutputPath); statErr == nil {
-								if logFile != nil && logger != nil {
-									logger.Printf("Copying temporary file %s to final destination %s\n", tmpOutputPath, absOutputPath)
-								}
-
-								// Create the parent directory for the output file if it doesn't exist
-								outputDir := filepath.Dir(absOutputPath)
-								if mkdirErr := os.MkdirAll(outputDir, 0755); mkdirErr != nil {
-									copyErr = fmt.Errorf("failed to create output directory: %v", mkdirErr)
-									if logFile != nil && logger != nil {
-										logger.Printf("Error creating output directory: %v\n", mkdirErr)
-									}
-								} else {
-									// Read the temporary file
-									tmpContent, readErr := os.ReadFile(tmpOutputPath)
-									if readErr != nil {
-										copyErr = fmt.Errorf("failed to read temporary file: %v", readErr)
-										if logFile != nil && logger != nil {
-											logger.Printf("Error reading temporary file: %v\n", readErr)
-										}
-									} else {
-										// Write to the final destination
-										writeErr := os.WriteFile(absOutputPath, tmpContent, 0644)
-										if writeErr != nil {
-											copyErr = fmt.Errorf("failed to write to final destination: %v", writeErr)
-											if logFile != nil && logger != nil {
-												logger.Printf("Error writing to final destination: %v\n", writeErr)
-											}
-										} else {
-											copySuccess = true
-											if logFile != nil && logger != nil {
-												logger.Printf("Successfully copied temporary file to final destination\n")
-											}
-
-											// Clean up the temporary file
-											removeErr := os.Remove(tmpOutputPath)
-											if removeErr != nil && logFile != nil && logger != nil {
-												logger.Printf("Warning: Could not remove temporary file: %v\n", removeErr)
-											} else if logFile != nil && logger != nil {
-												logger.Printf("Removed temporary file\n")
-											}
-										}
-									}
-								}
-							} else {
-								copyErr = fmt.Errorf("temporary file not found: %v", statErr)
-								if logFile != nil && logger != nil {
-									logger.Printf("Error: Temporary file not found: %v\n", statErr)
-								}
-							}
-
-							// If the command succeeded but copy failed, update the error
-							if err == nil && copyErr != nil {
-								err = copyErr
-							}
-						}
-
-						// Prepare output text in memory before updating UI
-						var outputText strings.Builder
-						outputText.WriteString("\nFull command output:\n")
-
-						// Limit output size to prevent UI sluggishness with very large outputs
-						outputStr := string(output)
-						const maxOutputLen = 10000 // Limit output to 10K chars
-						if len(outputStr) > maxOutputLen {
-							outputText.WriteString(outputStr[:maxOutputLen])
-							outputText.WriteString("\n... [Output truncated, full output in log file] ...")
-						} else {
-							outputText.WriteString(outputStr)
-						}
-
-						// Add error message if needed
-						if err != nil {
-							outputText.WriteString("\n\n❌ Command error: " + err.Error())
-						}
-
-						// Update UI in a single operation
-						fyne.Do(func() {
-							result.SetText(result.Text + outputText.String())
-						})
-
-						// Show output
-						fyne.Do(func() {
-							// Calculate total conversion time
-							conversionTime := time.Since(conversionStartTime).Round(time.Second)
-
-							// Update status based on success or failure
-							if err != nil {
-								currentTrackLabel.SetText(fmt.Sprintf("Conversion failed after %s", conversionTime))
-							} else {
-								currentTrackLabel.SetText(fmt.Sprintf("Conversion completed in %s", conversionTime))
+								currentTrackLabel.SetText(fmt.Sprintf("Conversion completed in %s", conversionTime))
 							}
 							progress.Show()
 
-							// Stop the ticker by removing the spinner container
-							// Find and remove the conversion spinner container
-							for i, obj := range trackList.Objects {
-								if box, ok := obj.(*fyne.Container); ok {
-									for _, child := range box.Objects {
-										if label, ok := child.(*widget.Label); ok && label.Text == "Converting PGS to SRT..." {
-											trackList.Objects = append(trackList.Objects[:i], trackList.Objects[i+1:]...)
-											break
-										}
-									}
-								}
-							}
-							trackList.Refresh()
+							// Remove this track's conversion box, leaving those of
+							// tracks still converting
+							trackList.Remove(conversionBox)
 
-							result.SetText(result.Text + "\n\n=== Conversion Results ===\n")
-							result.SetText(result.Text + "Completed at: " + time.Now().Format("15:04:05") + "\n")
+							logPane.Add("\n\n=== Conversion Results ===\n")
+							logPane.Add("Completed at: " + time.Now().Format("15:04:05") + "\n")
 
 							// Always show the full output for better debugging
 							outputStr := string(output)
-							result.SetText(result.Text + "\nFull output: \n" + outputStr + "\n")
+							logPane.Add("\nFull output: \n" + outputStr + "\n")
 
 							if err != nil {
-								result.SetText(result.Text + "\n❌ Error: " + err.Error() + "\n")
+								logPane.Add("\n❌ Error: " + err.Error() + "\n")
 							} else {
-								result.SetText(result.Text + "\n✅ Command completed successfully\n")
-
-								// Show file copy operation status
-								result.SetText(result.Text + "\n=== File Operations ===\n")
-								result.SetText(result.Text + fmt.Sprintf("✓ Temporary file created: %s\n", tmpOutputPath))
-								if copySuccess {
-									result.SetText(result.Text + fmt.Sprintf("✓ Copied to final destination: %s\n", absOutputPath))
-									result.SetText(result.Text + "✓ Temporary file cleaned up\n")
-								} else if copyErr != nil {
-									result.SetText(result.Text + fmt.Sprintf("❌ Failed to copy to final destination: %v\n", copyErr))
-								}
+								logPane.Add("\n✅ Command completed successfully\n")
 							}
 
 							// Ensure the text area scrolls to the bottom to show the latest output
@@ -2091,52 +1823,52 @@ func main() {
 						// Check current directory for debugging
 						currentDir, _ := os.Getwd()
 						fyne.Do(func() {
-							result.SetText(result.Text + "\n\n=== Path Debugging ===\n")
-							result.SetText(result.Text + fmt.Sprintf("Current working directory: %s\n", currentDir))
-							result.SetText(result.Text + fmt.Sprintf("Looking for output file at: %s\n", absOutputPath))
+							logPane.Add("\n\n=== Path Debugging ===\n")
+							logPane.Add(fmt.Sprintf("Current working directory: %s\n", currentDir))
+							logPane.Add(fmt.Sprintf("Looking for output file at: %s\n", absOutputPath))
 						})
 
 						// List files in output directory to see what was created
 						files, _ := os.ReadDir(outDir)
 						fyne.Do(func() {
-							result.SetText(result.Text + fmt.Sprintf("\nFiles in output directory (%s):\n", outDir))
+							logPane.Add(fmt.Sprintf("\nFiles in output directory (%s):\n", outDir))
 							for _, file := range files {
-								result.SetText(result.Text + fmt.Sprintf("- %s\n", file.Name()))
+								logPane.Add(fmt.Sprintf("- %s\n", file.Name()))
 							}
 						})
 
 						// Check if SRT file was created and show details
 						if fileInfo, statErr := os.Stat(absOutputPath); statErr == nil {
 							fyne.Do(func() {
-								result.SetText(result.Text + "\n✅ SRT file created successfully!")
-								result.SetText(result.Text + fmt.Sprintf("\n   - Path: %s", absOutputPath))
-								result.SetText(result.Text + fmt.Sprintf("\n   - Size: %d bytes", fileInfo.Size()))
-								result.SetText(result.Text + fmt.Sprintf("\n   - Modified: %s", fileInfo.ModTime().Format("15:04:05")))
+								logPane.Add("\n✅ SRT file created successfully!")
+								logPane.Add(fmt.Sprintf("\n   - Path: %s", absOutputPath))
+								logPane.Add(fmt.Sprintf("\n   - Size: %d bytes", fileInfo.Size()))
+								logPane.Add(fmt.Sprintf("\n   - Modified: %s", fileInfo.ModTime().Format("15:04:05")))
 
 								// Try to count lines in SRT file
 								if srtContent, readErr := os.ReadFile(absOutputPath); readErr == nil {
 									lines := strings.Split(string(srtContent), "\n")
-									result.SetText(result.Text + fmt.Sprintf("\n   - Lines: %d", len(lines)))
+									logPane.Add(fmt.Sprintf("\n   - Lines: %d", len(lines)))
 
 									// Count subtitle entries (every 4 lines is typically one subtitle)
 									subtitleCount := (len(lines) + 3) / 4 // rough estimate
-									result.SetText(result.Text + fmt.Sprintf("\n   - Estimated subtitles: ~%d", subtitleCount))
+									logPane.Add(fmt.Sprintf("\n   - Estimated subtitles: ~%d", subtitleCount))
 								}
 							})
 						} else {
 							err = fmt.Errorf("SRT file was not created: %v", statErr)
 							fyne.Do(func() {
-								result.SetText(result.Text + "\n❌ Error: " + err.Error())
+								logPane.Add("\n❌ Error: " + err.Error())
 							})
 						}
 					}
-				} else if t.ConvertOCR != nil && t.ConvertOCR.Checked && (strings.Contains(strings.ToLower(t.Codec), "ass") || strings.Contains(strings.ToLower(t.Codec), "ssa") || strings.Contains(strings.ToLower(t.Codec), "substation") || strings.Contains(strings.ToLower(t.Codec), "sub station")) {
+				} else if settings[t].Convert && (strings.Contains(strings.ToLower(t.Codec), "ass") || strings.Contains(strings.ToLower(t.Codec), "ssa") || strings.Contains(strings.ToLower(t.Codec), "substation") || strings.Contains(strings.ToLower(t.Codec), "sub station")) {
 					// ASS/SSA to SRT conversion
 					fyne.Do(func() {
-						result.SetText(result.Text + "\n\n[DEBUG] Starting ASS/SSA to SRT conversion process")
+						logPane.Add("\n\n[DEBUG] Starting ASS/SSA to SRT conversion process")
 					})
-					tempAssFile := fmt.Sprintf("%s.track%d_%s.ass", mkvBaseName, t.Num, t.Lang)
-					outFile = fmt.Sprintf("%s.track%d_%s.srt", mkvBaseName, t.Num, t.Lang) // Final output will be SRT
+					tempAssFile := outName + ".ass"
+					outFile = outName + ".srt" // Final output will be SRT
 
 					// Get absolute paths for extraction
 					absAssPath := filepath.Join(outDir, tempAssFile)
@@ -2144,31 +1876,33 @@ func main() {
 					// Debug output
 					fyne.Do(func() {
 						currentTrackLabel.SetText(fmt.Sprintf("Extracting ASS/SSA track %d...", t.Num))
-						result.SetText(result.Text + "\n\n=== ASS/SSA Extraction ===\n")
-						result.SetText(result.Text + fmt.Sprintf("Track: %d (%s)\n", t.Num, t.Lang))
-						result.SetText(result.Text + fmt.Sprintf("Output directory: %s\n", outDir))
-						result.SetText(result.Text + fmt.Sprintf("ASS/SSA file: %s\n", tempAssFile))
-						result.SetText(result.Text + fmt.Sprintf("Absolute path: %s\n", absAssPath))
+						logPane.Add("\n\n=== ASS/SSA Extraction ===\n")
+						logPane.Add(fmt.Sprintf("Track: %d (%s)\n", t.Num, t.Lang))
+						logPane.Add(fmt.Sprintf("Output directory: %s\n", outDir))
+						logPane.Add(fmt.Sprintf("ASS/SSA file: %s\n", tempAssFile))
+						logPane.Add(fmt.Sprintf("Absolute path: %s\n", absAssPath))
 					})
 
 					// Extract ASS/SSA first - use full command for debugging
 					cmdStr := fmt.Sprintf("mkvextract tracks \"%s\" %d:\"%s\"", mkvPath, t.Num, tempAssFile)
 					fyne.Do(func() {
-						result.SetText(result.Text + "\nRunning: " + cmdStr)
+						logPane.Add("\nRunning: " + cmdStr)
 					})
 
 					// Create the command with proper arguments
-					cmd := exec.Command("mkvextract", "tracks", mkvPath, fmt.Sprintf("%d:%s", t.Num, tempAssFile))
+					cmd := exec.CommandContext(ctx, "mkvextract", "tracks", mkvPath, fmt.Sprintf("%d:%s", t.Num, tempAssFile))
 					cmd.Dir = outDir
 
 					// Run the command and capture output
-					output, err = cmd.CombinedOutput()
+					output, err = runWithProgress(cmd, func(percent int) {
+						reportProgress(float64(percent) / 100)
+					})
 
 					// Debug output - show command result
 					fyne.Do(func() {
-						result.SetText(result.Text + "\nCommand output: " + string(output))
+						logPane.Add("\nCommand output: " + string(output))
 						if err != nil {
-							result.SetText(result.Text + "\nError: " + err.Error())
+							logPane.Add("\nError: " + err.Error())
 						}
 					})
 
@@ -2177,17 +1911,17 @@ func main() {
 					fileInfo, statErr := os.Stat(assFilePath)
 					if statErr != nil {
 						fyne.Do(func() {
-							result.SetText(result.Text + "\nCannot find extracted file: " + statErr.Error())
+							logPane.Add("\nCannot find extracted file: " + statErr.Error())
 						})
 						err = statErr
 					} else if fileInfo.Size() == 0 {
 						fyne.Do(func() {
-							result.SetText(result.Text + "\nExtracted file is empty (0 bytes)")
+							logPane.Add("\nExtracted file is empty (0 bytes)")
 						})
 						err = fmt.Errorf("extracted file is empty (0 bytes)")
 					} else {
 						fyne.Do(func() {
-							result.SetText(result.Text + fmt.Sprintf("\nSuccessfully extracted ASS/SSA file (%d bytes)", fileInfo.Size()))
+							logPane.Add(fmt.Sprintf("\nSuccessfully extracted ASS/SSA file (%d bytes)", fileInfo.Size()))
 						})
 					}
 
@@ -2198,21 +1932,27 @@ func main() {
 						conversionProgress.Max = 100
 						conversionProgress.SetValue(0)
 
-						conversionLabel := widget.NewLabel("Converting ASS/SSA to SRT...")
-						statusLabel := widget.NewLabel("Processing ASS/SSA file...")
-						elapsedLabel := widget.NewLabel("Elapsed: 0s")
-						remainingLabel := widget.NewLabel("Converting...")
+						conversionLabel := widget.NewLabel(tr("Converting ASS/SSA to SRT..."))
+						statusLabel := widget.NewLabel(tr("Processing ASS/SSA file..."))
+						elapsedLabel := widget.NewLabel(tr("Elapsed: 0s"))
+						remainingLabel := widget.NewLabel(tr("Converting..."))
 
 						// Track conversion start time
 						conversionStartTime := time.Now()
 
-						// Create a ticker to update elapsed time
+						// Create a ticker to update elapsed time, until tickerDone is closed
 						ticker := time.NewTicker(500 * time.Millisecond)
+						tickerDone := make(chan struct{})
 						go func() {
 							defer ticker.Stop()
 							var lastElapsedText string
 
-							for range ticker.C {
+							for {
+								select {
+								case <-tickerDone:
+									return
+								case <-ticker.C:
+								}
 								elapsed := time.Since(conversionStartTime).Round(time.Second)
 								newElapsedText := fmt.Sprintf("Elapsed: %s", elapsed)
 
@@ -2228,10 +1968,10 @@ func main() {
 						}()
 
 						fyne.Do(func() {
-							result.SetText(result.Text + "\n\n[DEBUG] ASS/SSA extraction completed successfully, starting conversion process")
+							logPane.Add("\n\n[DEBUG] ASS/SSA extraction completed successfully, starting conversion process")
 
 							// Show the conversion progress bar and labels
-							currentTrackLabel.SetText("Converting ASS/SSA to SRT...")
+							currentTrackLabel.SetText(tr("Converting ASS/SSA to SRT..."))
 							progress.Hide()
 							trackList.Add(container.NewVBox(
 								conversionLabel,
@@ -2252,8 +1992,8 @@ func main() {
 
 						// Use ffmpeg to convert ASS/SSA to SRT
 						fyne.Do(func() {
-							result.SetText(result.Text + "\n\n[DEBUG] Using ffmpeg to convert ASS/SSA to SRT")
-							statusLabel.SetText("Running ffmpeg conversion...")
+							logPane.Add("\n\n[DEBUG] Using ffmpeg to convert ASS/SSA to SRT")
+							statusLabel.SetText(tr("Running ffmpeg conversion..."))
 						})
 
 						// Get ffmpeg path - prioritize Homebrew version
@@ -2264,7 +2004,7 @@ func main() {
 						if _, err := os.Stat(homebrewPath); err == nil {
 							ffmpegPath = homebrewPath
 							fyne.Do(func() {
-								result.SetText(result.Text + "\n[DEBUG] Using Homebrew ffmpeg: " + homebrewPath)
+								logPane.Add("\n[DEBUG] Using Homebrew ffmpeg: " + homebrewPath)
 							})
 						} else {
 							// If Homebrew not found, check Miniconda as fallback
@@ -2274,60 +2014,60 @@ func main() {
 								if _, err := os.Stat(minicondaPath); err == nil {
 									ffmpegPath = minicondaPath
 									fyne.Do(func() {
-										result.SetText(result.Text + "\n[DEBUG] Using Miniconda ffmpeg: " + minicondaPath)
+										logPane.Add("\n[DEBUG] Using Miniconda ffmpeg: " + minicondaPath)
 									})
 								}
 							}
 						}
 
 						// Create the ffmpeg command with the appropriate path
-						cmd = exec.Command(ffmpegPath, "-i", absInputPath, "-f", "srt", absOutputPath)
+						cmd = exec.CommandContext(ctx, ffmpegPath, "-i", absInputPath, "-f", "srt", absOutputPath)
 						cmd.Dir = outDir
 
 						// Run the command and capture output
 						output, err = cmd.CombinedOutput()
 
 						// Stop the ticker
-						ticker.Stop()
+						close(tickerDone)
 
 						// Update UI with results
 						fyne.Do(func() {
-							result.SetText(result.Text + "\nffmpeg output: " + string(output))
+							logPane.Add("\nffmpeg output: " + string(output))
 
 							if err != nil {
-								result.SetText(result.Text + "\nError converting ASS/SSA to SRT: " + err.Error())
-								statusLabel.SetText("Conversion failed!")
+								logPane.Add("\nError converting ASS/SSA to SRT: " + err.Error())
+								statusLabel.SetText(tr("Conversion failed!"))
 								conversionProgress.SetValue(0)
 							} else {
-								result.SetText(result.Text + "\nSuccessfully converted ASS/SSA to SRT")
-								statusLabel.SetText("Conversion completed!")
+								logPane.Add("\nSuccessfully converted ASS/SSA to SRT")
+								statusLabel.SetText(tr("Conversion completed!"))
 								conversionProgress.SetValue(100)
 
 								// Check if the output file was created
 								if _, statErr := os.Stat(absOutputPath); statErr == nil {
-									result.SetText(result.Text + fmt.Sprintf("\nSRT file created at: %s", absOutputPath))
+									logPane.Add(fmt.Sprintf("\nSRT file created at: %s", absOutputPath))
 								} else {
-									result.SetText(result.Text + "\nWarning: Cannot find converted SRT file: " + statErr.Error())
+									logPane.Add("\nWarning: Cannot find converted SRT file: " + statErr.Error())
 								}
 							}
 
 							// Update elapsed time one last time
 							elapsed := time.Since(conversionStartTime).Round(time.Second)
 							elapsedLabel.SetText(fmt.Sprintf("Elapsed: %s", elapsed))
-							remainingLabel.SetText("Completed")
+							remainingLabel.SetText(tr("Completed"))
 						})
 					}
-				} else if t.ConvertOCR != nil && t.ConvertOCR.Checked && (t.Codec == "vobsub" || t.Codec == "VobSub") {
+				} else if settings[t].Convert && (t.Codec == "vobsub" || t.Codec == "VobSub") {
 					// VobSub to SRT conversion
 					fyne.Do(func() {
-						result.SetText(result.Text + "\n\n[DEBUG] Starting VobSub to SRT conversion process")
+						logPane.Add("\n\n[DEBUG] Starting VobSub to SRT conversion process")
 					})
 
 					// For VobSub, we extract both .idx and .sub files
 					// The .idx file is the main file that contains timing and positioning information
 					// The .sub file contains the actual subtitle images
-					idxFile := fmt.Sprintf("%s.track%d_%s.idx", mkvBaseName, t.Num, t.Lang)
-					outFile = fmt.Sprintf("%s.track%d_%s.srt", mkvBaseName, t.Num, t.Lang) // Final output will be SRT
+					idxFile := outName + ".idx"
+					outFile = outName + ".srt" // Final output will be SRT
 
 					// Get absolute paths for extraction
 					absIdxPath := filepath.Join(outDir, idxFile)
@@ -2335,31 +2075,33 @@ func main() {
 					// Debug output
 					fyne.Do(func() {
 						currentTrackLabel.SetText(fmt.Sprintf("Extracting VobSub track %d...", t.Num))
-						result.SetText(result.Text + "\n\n=== VobSub Extraction ===\n")
-						result.SetText(result.Text + fmt.Sprintf("Track: %d (%s)\n", t.Num, t.Lang))
-						result.SetText(result.Text + fmt.Sprintf("Output directory: %s\n", outDir))
-						result.SetText(result.Text + fmt.Sprintf("IDX file: %s\n", idxFile))
-						result.SetText(result.Text + fmt.Sprintf("Absolute path: %s\n", absIdxPath))
+						logPane.Add("\n\n=== VobSub Extraction ===\n")
+						logPane.Add(fmt.Sprintf("Track: %d (%s)\n", t.Num, t.Lang))
+						logPane.Add(fmt.Sprintf("Output directory: %s\n", outDir))
+						logPane.Add(fmt.Sprintf("IDX file: %s\n", idxFile))
+						logPane.Add(fmt.Sprintf("Absolute path: %s\n", absIdxPath))
 					})
 
 					// Extract VobSub first - use full command for debugging
 					cmdStr := fmt.Sprintf("mkvextract tracks \"%s\" %d:\"%s\"", mkvPath, t.Num, idxFile)
 					fyne.Do(func() {
-						result.SetText(result.Text + "\nRunning: " + cmdStr)
+						logPane.Add("\nRunning: " + cmdStr)
 					})
 
 					// Create the command with proper arguments
-					cmd := exec.Command("mkvextract", "tracks", mkvPath, fmt.Sprintf("%d:%s", t.Num, idxFile))
+					cmd := exec.CommandContext(ctx, "mkvextract", "tracks", mkvPath, fmt.Sprintf("%d:%s", t.Num, idxFile))
 					cmd.Dir = outDir
 
 					// Run the command and capture output
-					output, err = cmd.CombinedOutput()
+					output, err = runWithProgress(cmd, func(percent int) {
+						reportProgress(float64(percent) / 100)
+					})
 
 					// Debug output - show command result
 					fyne.Do(func() {
-						result.SetText(result.Text + "\nCommand output: " + string(output))
+						logPane.Add("\nCommand output: " + string(output))
 						if err != nil {
-							result.SetText(result.Text + "\nError: " + err.Error())
+							logPane.Add("\nError: " + err.Error())
 						}
 					})
 
@@ -2368,33 +2110,40 @@ func main() {
 					fileInfo, statErr := os.Stat(idxFilePath)
 					if statErr != nil {
 						fyne.Do(func() {
-							result.SetText(result.Text + "\nCannot find extracted file: " + statErr.Error())
+							logPane.Add("\nCannot find extracted file: " + statErr.Error())
 						})
 						err = statErr
 					} else if fileInfo.Size() == 0 {
 						fyne.Do(func() {
-							result.SetText(result.Text + "\nExtracted file is empty (0 bytes)")
+							logPane.Add("\nExtracted file is empty (0 bytes)")
 						})
 						err = fmt.Errorf("extracted file is empty")
 					} else {
 						// File exists and has content, proceed with conversion
 						fyne.Do(func() {
-							result.SetText(result.Text + fmt.Sprintf("\nIDX file extracted successfully (%d bytes)", fileInfo.Size()))
-							result.SetText(result.Text + "\n\n=== VobSub to SRT Conversion ===\n")
+							logPane.Add(fmt.Sprintf("\nIDX file extracted successfully (%d bytes)", fileInfo.Size()))
+							logPane.Add("\n\n=== VobSub to SRT Conversion ===\n")
 						})
 
 						// Create UI elements for conversion progress
 						conversionStartTime := time.Now()
-						conversionLabel := widget.NewLabel("Converting VobSub to SRT...")
-						statusLabel := widget.NewLabel("Starting conversion...")
+						conversionLabel := widget.NewLabel(tr("Converting VobSub to SRT..."))
+						statusLabel := widget.NewLabel(tr("Starting conversion..."))
 						conversionProgress := widget.NewProgressBar()
-						elapsedLabel := widget.NewLabel("Elapsed: 0s")
-						remainingLabel := widget.NewLabel("Estimating...")
+						elapsedLabel := widget.NewLabel(tr("Elapsed: 0s"))
+						remainingLabel := widget.NewLabel(tr("Estimating..."))
 
-						// Start a ticker to update the elapsed time
+						// Start a ticker to update the elapsed time, until tickerDone is closed
 						ticker := time.NewTicker(time.Second)
+						tickerDone := make(chan struct{})
 						go func() {
-							for range ticker.C {
+							defer ticker.Stop()
+							for {
+								select {
+								case <-tickerDone:
+									return
+								case <-ticker.C:
+								}
 								elapsed := time.Since(conversionStartTime).Round(time.Second)
 								fyne.Do(func() {
 									elapsedLabel.SetText(fmt.Sprintf("Elapsed: %s", elapsed))
@@ -2404,7 +2153,7 @@ func main() {
 
 						// Show the conversion progress bar and labels
 						fyne.Do(func() {
-							currentTrackLabel.SetText("Converting VobSub to SRT...")
+							currentTrackLabel.SetText(tr("Converting VobSub to SRT..."))
 							progress.Hide()
 							trackList.Add(container.NewVBox(
 								conversionLabel,
@@ -2420,242 +2169,201 @@ func main() {
 						})
 
 						// Get absolute paths for input and output
-						// For vobsub2srt, we need the base path without extension
 						basePath := strings.TrimSuffix(idxFilePath, filepath.Ext(idxFilePath))
-						absOutputPath := basePath + ".srt" // vobsub2srt will create this file
+						absOutputPath := basePath + ".srt"
 
 						// Check if both .idx and .sub files exist
 						idxFile := basePath + ".idx"
 						subFile := basePath + ".sub"
 
 						fyne.Do(func() {
-							result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] Checking for IDX file: %s", idxFile))
-							result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] Checking for SUB file: %s", subFile))
+							logPane.Add(fmt.Sprintf("\n[DEBUG] Checking for IDX file: %s", idxFile))
+							logPane.Add(fmt.Sprintf("\n[DEBUG] Checking for SUB file: %s", subFile))
 						})
 
 						// Check if the files exist
 						var filesExist bool = true
 						if _, err := os.Stat(idxFile); err == nil {
 							fyne.Do(func() {
-								result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] IDX file exists: %s", idxFile))
+								logPane.Add(fmt.Sprintf("\n[DEBUG] IDX file exists: %s", idxFile))
 							})
 						} else {
 							filesExist = false
 							fyne.Do(func() {
-								result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] IDX file does not exist: %s - %v", idxFile, err))
+								logPane.Add(fmt.Sprintf("\n[DEBUG] IDX file does not exist: %s - %v", idxFile, err))
 							})
 						}
 
 						if _, err := os.Stat(subFile); err == nil {
 							fyne.Do(func() {
-								result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] SUB file exists: %s", subFile))
+								logPane.Add(fmt.Sprintf("\n[DEBUG] SUB file exists: %s", subFile))
 							})
 						} else {
 							filesExist = false
 							fyne.Do(func() {
-								result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] SUB file does not exist: %s - %v", subFile, err))
+								logPane.Add(fmt.Sprintf("\n[DEBUG] SUB file does not exist: %s - %v", subFile, err))
 							})
 						}
 
 						// If either file is missing, show a warning
 						if !filesExist {
 							fyne.Do(func() {
-								result.SetText(result.Text + "\n[DEBUG] ⚠️ Warning: IDX or SUB file is missing, conversion may fail")
+								logPane.Add("\n[DEBUG] ⚠️ Warning: IDX or SUB file is missing, conversion may fail")
 							})
 						}
 
-						// Get language from user selection or use track language as default
-						langCode := t.Lang
-						if langCode == "" {
-							langCode = "eng" // Default to English if no language code is available
-						}
-
-						// Check if user has selected a specific language
-						if t.LangSelect != nil && t.LangSelect.Selected != "" && !strings.HasPrefix(t.LangSelect.Selected, "Auto") {
-							// Extract the language code from the selection (format: "Language (code)")
-							selection := t.LangSelect.Selected
-							// Extract the code part between parentheses
-							if start := strings.LastIndex(selection, "("); start != -1 {
-								if end := strings.LastIndex(selection, ")"); end != -1 && end > start {
-									// Extract the 2-letter code directly
-									twoLetterCode := selection[start+1 : end]
-									fyne.Do(func() {
-										result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] User selected language: %s (code: %s)", selection, twoLetterCode))
-									})
-									langCode = twoLetterCode
-								}
-							}
-						} else {
-							// Using auto-detected language, map 3-letter code to 2-letter code
-							langCodeMap := map[string]string{
-								"eng": "en", // English
-								"fre": "fr", // French
-								"fra": "fr", // French (alternate)
-								"ger": "de", // German
-								"deu": "de", // German (alternate)
-								"ita": "it", // Italian
-								"spa": "es", // Spanish
-								"por": "pt", // Portuguese
-								"dut": "nl", // Dutch
-								"nld": "nl", // Dutch (alternate)
-								"swe": "sv", // Swedish
-								"nor": "no", // Norwegian
-								"dan": "da", // Danish
-								"fin": "fi", // Finnish
-								"jpn": "ja", // Japanese
-								"kor": "ko", // Korean
-								"chi": "zh", // Chinese
-								"zho": "zh", // Chinese (alternate)
-								"rus": "ru", // Russian
-								"pol": "pl", // Polish
-								"cze": "cs", // Czech
-								"ces": "cs", // Czech (alternate)
-								"hun": "hu", // Hungarian
-								"gre": "el", // Greek
-								"ell": "el", // Greek (alternate)
-								"tur": "tr", // Turkish
-								"ara": "ar", // Arabic
-								"heb": "he", // Hebrew
-								"tha": "th", // Thai
-							}
-
-							// Convert 3-letter code to 2-letter code if a mapping exists
-							if twoLetterCode, exists := langCodeMap[strings.ToLower(langCode)]; exists {
-								fyne.Do(func() {
-									result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] Mapped language code: %s -> %s", langCode, twoLetterCode))
-								})
-								langCode = twoLetterCode
-							} else {
-								fyne.Do(func() {
-									result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] No mapping found for language code: %s, using as-is", langCode))
-								})
-							}
-						}
+						// Read the track in the language(s) selected for it, or its own language
+						langCode := settings[t].OCRLang
 
-						// Use vobsub2srt binary for conversion
-						conversionScript := "/usr/local/bin/vobsub2srt"
+						fyne.Do(func() {
+							logPane.Add(fmt.Sprintf("\n[DEBUG] Using language code: %s for VobSub conversion", langCode))
+							statusLabel.SetText(tr("Recognizing VobSub subtitles..."))
+						})
 
-						// Check if the binary exists
-						if _, err := os.Stat(conversionScript); err != nil {
-							fyne.Do(func() {
-								result.SetText(result.Text + fmt.Sprintf("\n[ERROR] vobsub2srt binary not found at %s", conversionScript))
-							})
-							err = fmt.Errorf("vobsub2srt binary not found at %s", conversionScript)
-						} else {
+						// Decode the idx/sub pair here and read every subtitle bitmap with the track's OCR engine
+						ocrSlots <- struct{}{}
+						ocrResult, ocrErr := convertVobSubToSRT(ctx, idxFile, absOutputPath, OCROptions{Engine: settings[t].Engine, Lang: langCode, Quality: ocrQuality, Correct: correctOCR, Italics: ocrItalics, Top: ocrTop, SavePartial: ocrSavePartial, Cache: ocrCache, Preprocess: ocrPreprocess, Fallback: fallbackEngines, FallbackThreshold: fallbackThreshold, Charset: ocrCharset(langCode), Rules: loadOCRRules(prefs), LearnRules: learnOCR, Review: ocrReview(t)}, func(done, total int) {
+							fraction := float64(done) / float64(total)
 							fyne.Do(func() {
-								result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] Using vobsub2srt binary: %s", conversionScript))
-								result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] Using language code: %s for VobSub conversion", langCode))
-								result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] Base path for vobsub2srt: %s", basePath))
+								statusLabel.SetText(fmt.Sprintf("Processing subtitle %d of %d (%.1f%%)", done, total, fraction*100))
+								conversionProgress.SetValue(fraction)
+								elapsed := time.Since(conversionStartTime)
+								remaining := time.Duration(float64(elapsed) / fraction * (1 - fraction)).Round(time.Second)
+								remainingLabel.SetText(fmt.Sprintf("Estimated time remaining: %s", remaining))
 							})
+							reportProgress(fraction)
+						})
+						err = ocrErr
+						<-ocrSlots
+						output = []byte(ocrResult.String())
 
-							// Check if the output SRT file already exists and delete it if it does
-							outputSrtFile := basePath + ".srt"
-							if _, err := os.Stat(outputSrtFile); err == nil {
-								fyne.Do(func() {
-									result.SetText(result.Text + fmt.Sprintf("\n[DEBUG] Removing existing SRT file: %s", outputSrtFile))
-								})
-								os.Remove(outputSrtFile)
-							}
+						// Stop the ticker
+						close(tickerDone)
 
-							// Run vobsub2srt with the language parameter
-							cmdStr = fmt.Sprintf("%s --lang %s \"%s\"", conversionScript, langCode, basePath)
-							fyne.Do(func() {
-								result.SetText(result.Text + "\n[DEBUG] Running command: " + cmdStr)
-								statusLabel.SetText("Running vobsub2srt conversion...")
-							})
+						// Update UI with results
+						fyne.Do(func() {
+							logPane.Add("\nVobSub OCR: " + string(output))
 
-							// Create the command
-							cmd = exec.Command(conversionScript, "--lang", langCode, basePath)
-							cmd.Dir = outDir
+							if err != nil {
+								logPane.Add("\nError converting VobSub to SRT: " + err.Error())
+								statusLabel.SetText(tr("Conversion failed!"))
+								conversionProgress.SetValue(0)
+							} else {
+								statusLabel.SetText(tr("Conversion completed!"))
+								conversionProgress.SetValue(1)
+								if fileInfo, statErr := os.Stat(absOutputPath); statErr == nil {
+									logPane.Add(fmt.Sprintf("\nSRT file created at: %s", absOutputPath))
+									logPane.Add(fmt.Sprintf("\nSRT file size: %d bytes", fileInfo.Size()))
+								}
+							}
 
-							// Run the command and capture output
-							output, err = cmd.CombinedOutput()
+							// Update elapsed time one last time
+							elapsed := time.Since(conversionStartTime).Round(time.Second)
+							elapsedLabel.SetText(fmt.Sprintf("Elapsed: %s", elapsed))
+							remainingLabel.SetText(tr("Completed"))
+						})
+					}
+				} else if settings[t].Convert && isDVBSubtitle(t.Codec) {
+					// DVB subtitles are copied into a transport stream with ffmpeg, then decoded and read here
+					tsFile := filepath.Join(outDir, outName+".ts")
+					outFile = outName + ".srt"
+					absOutputPath := filepath.Join(outDir, outFile)
+					fyne.Do(func() {
+						currentTrackLabel.SetText(fmt.Sprintf("Extracting DVB track %d...", t.Num))
+						logPane.Add("\n\n=== DVB Extraction ===\n")
+						logPane.Add(fmt.Sprintf("Track: %d (%s)\nTransport stream: %s\n", t.Num, t.Lang, tsFile))
+					})
+					output, err = extractDVBSubtitles(ctx, mkvPath, t.Num, tsFile)
 
-							// Stop the ticker
-							ticker.Stop()
+					if err == nil {
+						conversionStartTime := time.Now()
+						statusLabel := widget.NewLabel(tr("Starting conversion..."))
+						conversionProgress := widget.NewProgressBar()
+						remainingLabel := widget.NewLabel(tr("Estimating..."))
+						fyne.Do(func() {
+							currentTrackLabel.SetText(tr("Converting DVB subtitles to SRT..."))
+							progress.Hide()
+							trackList.Add(container.NewVBox(
+								widget.NewLabel(tr("Converting DVB subtitles to SRT...")),
+								statusLabel,
+								conversionProgress,
+								remainingLabel,
+							))
+							trackList.Refresh()
+						})
 
-							// Update UI with results
+						// Read the track in the language(s) selected for it, or its own language
+						langCode := settings[t].OCRLang
+						fyne.Do(func() {
+							logPane.Add("\n\n=== DVB OCR ===\n")
+							logPane.Add(fmt.Sprintf("Output SRT file: %s\nOCR language: %s\n", absOutputPath, langCode))
+						})
+						ocrSlots <- struct{}{}
+						ocrResult, ocrErr := convertDVBSubToSRT(ctx, tsFile, absOutputPath, OCROptions{Engine: settings[t].Engine, Lang: langCode, Quality: ocrQuality, Correct: correctOCR, Italics: ocrItalics, Top: ocrTop, SavePartial: ocrSavePartial, Cache: ocrCache, Preprocess: ocrPreprocess, Fallback: fallbackEngines, FallbackThreshold: fallbackThreshold, Charset: ocrCharset(langCode), Rules: loadOCRRules(prefs), LearnRules: learnOCR, Review: ocrReview(t)}, func(done, total int) {
+							fraction := float64(done) / float64(total)
 							fyne.Do(func() {
-								result.SetText(result.Text + "\nvobsub2srt output: " + string(output))
-
-								if err != nil {
-									result.SetText(result.Text + "\nError converting VobSub to SRT: " + err.Error())
-									statusLabel.SetText("Conversion failed!")
-									conversionProgress.SetValue(0)
-								} else {
-									result.SetText(result.Text + "\nSuccessfully ran vobsub2srt command")
-									statusLabel.SetText("Conversion completed!")
-									conversionProgress.SetValue(100)
-
-									// Check if the output file was created
-									if fileInfo, statErr := os.Stat(absOutputPath); statErr == nil {
-										result.SetText(result.Text + fmt.Sprintf("\nSRT file created at: %s", absOutputPath))
-										result.SetText(result.Text + fmt.Sprintf("\nSRT file size: %d bytes", fileInfo.Size()))
-
-										// Try to count lines in SRT file
-										if srtContent, readErr := os.ReadFile(absOutputPath); readErr == nil {
-											lines := strings.Split(string(srtContent), "\n")
-											result.SetText(result.Text + fmt.Sprintf("\nSRT file lines: %d", len(lines)))
-
-											// Count subtitle entries (every 4 lines is typically one subtitle)
-											subtitleCount := (len(lines) + 3) / 4 // rough estimate
-											result.SetText(result.Text + fmt.Sprintf("\nEstimated subtitles: ~%d", subtitleCount))
-										}
-									} else {
-										result.SetText(result.Text + "\nWarning: Cannot find converted SRT file: " + statErr.Error())
-									}
-								}
-
-								// Update elapsed time one last time
-								elapsed := time.Since(conversionStartTime).Round(time.Second)
-								elapsedLabel.SetText(fmt.Sprintf("Elapsed: %s", elapsed))
-								remainingLabel.SetText("Completed")
+								statusLabel.SetText(fmt.Sprintf("Processing subtitle %d of %d (%.1f%%)", done, total, fraction*100))
+								conversionProgress.SetValue(fraction)
+								elapsed := time.Since(conversionStartTime)
+								remaining := time.Duration(float64(elapsed) / fraction * (1 - fraction)).Round(time.Second)
+								remainingLabel.SetText(fmt.Sprintf("Estimated time remaining: %s", remaining))
 							})
-						}
+							reportProgress(fraction)
+						})
+						err = ocrErr
+						<-ocrSlots
+						output = []byte(ocrResult.String())
+
+						fyne.Do(func() {
+							logPane.Add("\nDVB OCR: " + string(output))
+							progress.Show()
+							if err != nil {
+								logPane.Add("\nError converting DVB subtitles to SRT: " + err.Error())
+								statusLabel.SetText(tr("Conversion failed!"))
+							} else {
+								statusLabel.SetText(tr("Conversion completed!"))
+								conversionProgress.SetValue(1)
+								remainingLabel.SetText(tr("Completed"))
+							}
+						})
 					}
 				} else {
 					// Normal extraction without conversion
 					// Use proper file extension based on codec
-					var fileExt string
-
-					// Handle special case for "SubRip/SRT" format
-					if strings.Contains(t.Codec, "SubRip") || strings.Contains(t.Codec, "subrip") || strings.Contains(t.Codec, "SRT") || strings.Contains(t.Codec, "srt") {
-						fileExt = "srt"
+					fileExt := codecFileExt(t.Codec)
+					if fileExt == "srt" {
 						fyne.Do(func() {
-							result.SetText(result.Text + "\nDetected SRT format, using .srt extension")
+							logPane.Add("\nDetected SRT format, using .srt extension")
 						})
-					} else if t.Codec == "hdmv_pgs_subtitle" || t.Codec == "HDMV PGS" {
-						fileExt = "sup"
-					} else if t.Codec == "ass" || t.Codec == "ssa" || t.Codec == "ASS" || t.Codec == "SSA" {
-						fileExt = "ass"
-					} else if t.Codec == "vobsub" || t.Codec == "VobSub" {
-						fileExt = "idx"
-					} else {
-						// Use lowercase codec name as fallback but remove any slashes
-						cleanCodec := strings.ReplaceAll(t.Codec, "/", "_")
-						fileExt = strings.ToLower(cleanCodec)
 					}
 
 					// Debug output for file naming
 					fyne.Do(func() {
-						result.SetText(result.Text + "\n\n=== Track Extraction ===\n")
-						result.SetText(result.Text + fmt.Sprintf("Track: %d (%s - %s)\n", t.Num, t.Lang, t.Codec))
+						logPane.Add("\n\n=== Track Extraction ===\n")
+						logPane.Add(fmt.Sprintf("Track: %d (%s - %s)\n", t.Num, t.Lang, t.Codec))
 					})
 
-					outFile = fmt.Sprintf("%s.track%d_%s.%s", mkvBaseName, t.Num, t.Lang, fileExt)
+					outFile = outName + "." + fileExt
 
 					fyne.Do(func() {
-						result.SetText(result.Text + fmt.Sprintf("Output file: %s\n", outFile))
+						logPane.Add(fmt.Sprintf("Output file: %s\n", outFile))
 					})
 					// Use absolute paths for all subtitle extractions to avoid directory creation issues
 					absOutFile := filepath.Join(outDir, outFile)
-					cmd := exec.Command("mkvextract", "tracks", mkvPath, fmt.Sprintf("%d:%s", t.Num, absOutFile))
+					cmd := exec.CommandContext(ctx, "mkvextract", "tracks", mkvPath, fmt.Sprintf("%d:%s", t.Num, absOutFile))
 
 					fyne.Do(func() {
-						result.SetText(result.Text + fmt.Sprintf("\nExtracting to: %s", absOutFile))
+						logPane.Add(fmt.Sprintf("\nExtracting to: %s", absOutFile))
 					})
 
-					output, err = cmd.CombinedOutput()
+					if isDVBSubtitle(t.Codec) {
+						// mkvextract cannot extract DVB subtitles
+						output, err = extractDVBSubtitles(ctx, mkvPath, t.Num, absOutFile)
+					} else {
+						output, err = runWithProgress(cmd, func(percent int) {
+							reportProgress(float64(percent) / 100)
+						})
+					}
 
 					// Set proper file permissions for subtitle files (read/write for user, read for group/others)
 					if err == nil {
@@ -2666,79 +2374,586 @@ func main() {
 
 				// Update UI on main thread
 				fyne.Do(func() {
-					if err != nil {
+					if err != nil && ctx.Err() != nil {
+						t.State = "Skipped"
+						t.Status.SetText(fmt.Sprintf("[-] Track %d: %s (%s) %s - Cancelled", t.Num, t.Lang, t.Codec, t.Name))
+					} else if err != nil {
 						t.State = "Error"
+						t.Error = failureReason(output, err)
 						t.Status.SetText(fmt.Sprintf("[!] Track %d: %s (%s) %s - Error", t.Num, t.Lang, t.Codec, t.Name))
-						result.SetText(string(output) + "\nExtraction failed: " + err.Error())
+						logPane.Add(string(output) + "\nExtraction failed: " + err.Error())
 					} else {
 						t.State = "Done"
+						t.Error = ""
+						t.OutputPath = filepath.Join(outDir, outFile)
 						t.Status.SetText(fmt.Sprintf("[✓] Track %d: %s (%s) %s - Done", t.Num, t.Lang, t.Codec, t.Name))
-						progress.SetValue(float64(tracksDone + 1))
+						mu.Lock()
+						trackFractions[i] = 1
+						progress.SetValue(overallProgress())
+						mu.Unlock()
 					}
 
-					// Update track list
-					trackList.Objects = nil
-					for _, tt := range trackItems {
-						trackInfo := widget.NewLabel(fmt.Sprintf("Track %d: %s (%s) %s", tt.Num, tt.Lang, tt.Codec, tt.Name))
-
-						if tt.ConvertOCR != nil {
-							// For PGS subtitles, show OCR option
-							ocrLabel := widget.NewLabel("Convert to SRT")
-							row := container.NewHBox(tt.Check, tt.Status, trackInfo, tt.ConvertOCR, ocrLabel)
-							trackList.Add(row)
-						} else {
-							// For other subtitle formats
-							row := container.NewHBox(tt.Check, tt.Status, trackInfo)
-							trackList.Add(row)
-						}
+					// Clear conversion progress once no other track is running, and show the new track state
+					mu.Lock()
+					if activeTracks == 1 {
+						trackList.Objects = nil
+						trackList.Refresh()
 					}
-					trackList.Refresh()
+					mu.Unlock()
+					trackTable.Refresh()
 				})
 
-				tracksDone++
+				estimate.TrackDone(time.Since(trackStart))
+			}
+
+			for i, t := range selected {
+				runPause.Wait(ctx)
+
+				// Skip the remaining tracks once the run is cancelled
+				if ctx.Err() != nil {
+					fyne.Do(func() {
+						t.State = "Skipped"
+						t.Status.SetText(fmt.Sprintf("[-] Track %d: %s (%s) %s - Skipped", t.Num, t.Lang, t.Codec, t.Name))
+						trackTable.Refresh()
+					})
+					continue
+				}
+
+				trackSlots <- struct{}{}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					extractTrack(i, t)
+					<-trackSlots
+				}()
 			}
+			wg.Wait()
 
 			// Final UI update on main thread
 			fyne.Do(func() {
 				currentTrackLabel.SetText("")
-				if tracksDone == len(selected) {
-					result.SetText("Extraction complete!")
+				trackProgress.SetValue(0)
+				etaLabel.SetText("")
+				if ctx.Err() == nil {
+					addHistoryEntry(a.Preferences(), newHistoryEntry(mkvPath, outDir, trackItems))
+					refreshHistory()
+				}
+				if ctx.Err() != nil {
+					logPane.Add(tr("Extraction cancelled. Remaining tracks were skipped."))
+				} else if tracksDone == len(selected) {
+					logPane.Add(tr("Extraction complete!"))
 					progress.SetValue(progress.Max)
 				} else {
-					result.SetText(fmt.Sprintf("Extraction stopped after %d of %d tracks", tracksDone, len(selected)))
+					logPane.Add(trf("Extraction stopped after %d of %d tracks", tracksDone, len(selected)))
 				}
 			})
 		}()
-	})
-
-	// Create Support button with improved UX
-	supportBtn := widget.NewButton("Donate ☕", func() {
-		// Show a confirmation dialog with information about the donation
-		confirm := dialog.NewConfirm(
-			"Support Subtitle Forge",
-			"Your donation helps maintain and improve Subtitle Forge. Would you like to proceed to PayPal?",
-			func(ok bool) {
-				if ok {
-					supportURL, _ := url.Parse("https://paypal.me/VenimK")
-					fyne.CurrentApp().OpenURL(supportURL)
-				}
-			},
-			w,
-		)
-		confirm.SetDismissText("Cancel")
-		confirm.SetConfirmText("Donate")
-		confirm.Show()
-	})
-	supportBtn.Importance = widget.HighImportance
+		return done
+	}
 
-	// Create button row for better layout
-	buttonRow := container.NewHBox(loadTracksBtn, startExtractBtn, layout.NewSpacer(), supportBtn)
+	// runExtraction extracts the checked tracks of one file in the background
+	var runExtraction func(path string, dir string, items []*TrackItem)
+	runExtraction = func(path string, dir string, items []*TrackItem) {
+		estimate.Reset(selectedTrackCount(items))
+		ctx, cancel := startRun()
+		go func() {
+			defer cancel()
+			<-extractTracks(ctx, path, dir, items)
 
-	// Setup keyboard shortcuts for main actions
-	setupKeyboardShortcuts(fileBtn.OnTapped, dirBtn.OnTapped, loadTracksBtn.OnTapped, startExtractBtn.OnTapped)
+			item := &QueueItem{Path: path, Tracks: items}
+			if ctx.Err() == nil && item.selectedCount() > 0 {
+				fyne.Do(func() {
+					message := trf("%s: %d of %d tracks extracted", filepath.Base(item.Path), item.doneCount(), item.selectedCount())
+					showRunComplete(w, message, dir, []*QueueItem{item}, func(retry []*QueueItem) {
+						if running {
+							dialog.ShowError(errors.New(tr("An extraction is already running.")), w)
+							return
+						}
+						trackTable.Refresh()
+						runExtraction(path, dir, items)
+					})
+				})
+			}
+		}()
+	}
 
-	// Use app.NewWithID for better performance and to avoid preferences API warnings
-	// This was already set at the beginning of main()
+	// Button to start extraction of selected tracks
+	startExtractBtn := widget.NewButton(tr("Start Extraction"), func() {
+		if mkvPath == "" || outDir == "" {
+			dialog.ShowError(errors.New(tr("Please select both MKV file and output directory.")), w)
+			return
+		}
+		if running {
+			dialog.ShowError(errors.New(tr("An extraction is already running.")), w)
+			return
+		}
+
+		runExtraction(mkvPath, outDir, trackItems)
+	})
+
+	// Button to extract every queued file that has not been processed yet.
+	// Files whose tracks were never loaded get all their subtitle tracks extracted.
+	startQueue = func() {
+		if running {
+			dialog.ShowError(errors.New(tr("An extraction is already running.")), w)
+			return
+		}
+		var pending []*QueueItem
+		for _, item := range queue {
+			if item.State != "Done" {
+				pending = append(pending, item)
+			}
+		}
+		if len(pending) == 0 {
+			dialog.ShowError(errors.New(tr("There are no pending files in the queue. Add MKV files first.")), w)
+			return
+		}
+
+		// Files whose tracks are not loaded yet are added to the estimate once loaded
+		queuedTracks := 0
+		for _, item := range pending {
+			queuedTracks += selectedTrackCount(item.Tracks)
+		}
+		estimate.Reset(queuedTracks)
+
+		ctx, cancel := startRun()
+		queueRunning = true
+		go func() {
+			defer cancel()
+
+			// Files queued while the queue runs are picked up as well
+			var processed []*QueueItem
+			attempted := map[*QueueItem]bool{}
+			for {
+				runPause.Wait(ctx)
+
+				// Cancelled files stay pending so a later run picks them up
+				if ctx.Err() != nil {
+					break
+				}
+
+				// The queue belongs to the UI thread, so the next file is picked there
+				var item *QueueItem
+				var tracks []*TrackItem
+				fyne.DoAndWait(func() {
+					for _, queued := range queue {
+						if queued.State != "Done" && !attempted[queued] {
+							item = queued
+							break
+						}
+					}
+					if item == nil {
+						return
+					}
+					attempted[item] = true
+					processed = append(processed, item)
+					tracks = item.Tracks
+				})
+				if item == nil {
+					break
+				}
+
+				// Reading the tracks runs mkvmerge, which would freeze the UI
+				loaded := tracks == nil
+				var loadErr error
+				if loaded {
+					tracks, loadErr = loadSubtitleTracks(item.Path)
+				}
+				fyne.Do(func() {
+					if loadErr != nil {
+						item.State = "Error"
+						logPane.Add(loadErr.Error())
+						refreshQueue()
+						return
+					}
+					if loaded {
+						item.Tracks = tracks
+						estimate.AddTracks(selectedTrackCount(tracks))
+					}
+					item.State = "Running"
+					showQueueItem(item)
+				})
+				if loadErr != nil {
+					continue
+				}
+
+				itemOutDir := filepath.Dir(item.Path)
+				if customOutDir {
+					itemOutDir = outDir
+				}
+				<-extractTracks(ctx, item.Path, itemOutDir, tracks)
+
+				fyne.DoAndWait(func() {
+					if ctx.Err() != nil {
+						item.State = "Pending"
+					} else {
+						item.State = item.finishedState()
+					}
+					refreshQueue()
+				})
+			}
+
+			fyne.Do(func() {
+				extracted := 0
+				for _, item := range processed {
+					if item.State == "Done" {
+						extracted++
+					}
+				}
+				if ctx.Err() != nil {
+					logPane.Add(trf("Queue cancelled: %d of %d files extracted", extracted, len(processed)))
+					return
+				}
+				if len(processed) == 0 {
+					return
+				}
+				message := trf("Queue finished: %d of %d files extracted", extracted, len(processed))
+				logPane.Add(message)
+
+				queueOutDir := filepath.Dir(processed[0].Path)
+				if customOutDir {
+					queueOutDir = outDir
+				}
+				showRunComplete(w, message, queueOutDir, processed, func(retry []*QueueItem) {
+					for _, item := range retry {
+						item.State = "Pending"
+					}
+					trackTable.Refresh()
+					refreshQueue()
+					startQueue()
+				})
+			})
+		}()
+	}
+	startQueueBtn := widget.NewButton(tr("Start Queue"), func() {
+		startQueue()
+	})
+
+	// Button to pause the running extraction or queue after the current track
+	var pauseBtn *widget.Button
+	pauseBtn = widget.NewButton(tr("Pause"), func() {
+		if runPause.Paused() {
+			runPause.Resume()
+			pauseBtn.SetText(tr("Pause"))
+			currentTrackLabel.SetText(tr("Resumed"))
+			return
+		}
+		runPause.Pause()
+		pauseBtn.SetText(tr("Resume"))
+		currentTrackLabel.SetText(tr("Paused after the current track. Click 'Resume' to continue."))
+	})
+
+	// Button to cancel the running extraction or queue
+	cancelBtn := widget.NewButton(tr("Cancel"), func() {
+		if cancelRun == nil {
+			return
+		}
+		cancelRun()
+		runPause.Resume()
+		pauseBtn.SetText(tr("Pause"))
+		currentTrackLabel.SetText(tr("Cancelling..."))
+	})
+
+	// Button to empty the queue
+	clearQueueBtn := widget.NewButton(tr("Clear Queue"), func() {
+		queue = nil
+		refreshQueue()
+	})
+
+	// Quick selection buttons for files with many tracks
+	setTracksChecked := func(checked func(t *TrackItem) bool) {
+		for _, t := range trackItems {
+			t.Check.SetChecked(checked(t))
+		}
+		trackTable.Refresh()
+		refreshQueue()
+	}
+	selectAllBtn := widget.NewButton(tr("Select All"), func() {
+		setTracksChecked(func(t *TrackItem) bool { return true })
+	})
+	selectNoneBtn := widget.NewButton(tr("Select None"), func() {
+		setTracksChecked(func(t *TrackItem) bool { return false })
+	})
+	invertSelectionBtn := widget.NewButton(tr("Invert"), func() {
+		setTracksChecked(func(t *TrackItem) bool { return !t.Check.Checked })
+	})
+	selectLanguageBtn := widget.NewButton(tr("Select by language…"), func() {
+		langs := trackLanguages(trackItems)
+		if len(langs) == 0 {
+			dialog.ShowError(errors.New(tr("Please load the tracks first.")), w)
+			return
+		}
+		langSelect := widget.NewSelect(langs, nil)
+		langSelect.SetSelected(langs[0])
+		dialog.ShowCustomConfirm(tr("Select by language"), "Select", "Cancel", langSelect, func(ok bool) {
+			if !ok {
+				return
+			}
+			// Add the tracks of the language to the current selection
+			setTracksChecked(func(t *TrackItem) bool { return t.Check.Checked || t.Lang == langSelect.Selected })
+		}, w)
+	})
+
+	// Extraction presets configure the selection and conversions of newly loaded tracks
+	noPreset := tr("No preset")
+	presetSelect := widget.NewSelect(nil, nil)
+	refreshPresets := func() {
+		options := []string{noPreset}
+		for _, p := range loadPresets(a.Preferences()) {
+			options = append(options, p.Name)
+		}
+		presetSelect.SetOptions(options)
+		if preset := activePreset(a.Preferences()); preset != nil {
+			presetSelect.SetSelected(preset.Name)
+		} else {
+			presetSelect.SetSelected(noPreset)
+		}
+	}
+	refreshPresets()
+	presetSelect.OnChanged = func(name string) {
+		if name == noPreset {
+			a.Preferences().SetString("active_preset", "")
+			return
+		}
+		a.Preferences().SetString("active_preset", name)
+		if preset := findPreset(loadPresets(a.Preferences()), name); preset != nil {
+			preset.apply(trackItems)
+			trackTable.Refresh()
+			refreshQueue()
+		}
+	}
+
+	savePresetBtn := widget.NewButton(tr("Save Preset…"), func() {
+		// Start from the active preset, or from the languages of the checked tracks
+		nameEntry := widget.NewEntry()
+		langEntry := widget.NewEntry()
+		langEntry.SetPlaceHolder(tr("e.g. eng, dut (empty for all languages)"))
+		forcedCheck := widget.NewCheck(tr("Forced tracks only"), nil)
+		conversions := []string{tr("Codec defaults"), tr("Convert to SRT"), tr("Keep original format")}
+		conversionValues := []string{PresetConvertDefault, PresetConvertSRT, PresetConvertOriginal}
+		conversionSelect := widget.NewSelect(conversions, nil)
+		conversionSelect.SetSelected(conversions[0])
+		if preset := activePreset(a.Preferences()); preset != nil {
+			nameEntry.SetText(preset.Name)
+			langEntry.SetText(strings.Join(preset.Languages, ", "))
+			forcedCheck.SetChecked(preset.ForcedOnly)
+			for i, value := range conversionValues {
+				if value == preset.Conversion {
+					conversionSelect.SetSelected(conversions[i])
+				}
+			}
+		} else {
+			var checked []*TrackItem
+			for _, t := range trackItems {
+				if t.Check.Checked {
+					checked = append(checked, t)
+				}
+			}
+			langEntry.SetText(strings.Join(trackLanguages(checked), ", "))
+		}
+
+		items := []*widget.FormItem{
+			widget.NewFormItem(tr("Name:"), nameEntry),
+			widget.NewFormItem(tr("Languages:"), langEntry),
+			widget.NewFormItem("", forcedCheck),
+			widget.NewFormItem(tr("Conversion:"), conversionSelect),
+		}
+		dialog.ShowForm(tr("Save Preset"), tr("Save"), tr("Cancel"), items, func(ok bool) {
+			name := strings.TrimSpace(nameEntry.Text)
+			if !ok || name == "" || name == noPreset {
+				return
+			}
+			preset := Preset{Name: name, ForcedOnly: forcedCheck.Checked}
+			for _, lang := range strings.Split(langEntry.Text, ",") {
+				if lang = strings.TrimSpace(lang); lang != "" {
+					preset.Languages = append(preset.Languages, lang)
+				}
+			}
+			for i, conversion := range conversions {
+				if conversion == conversionSelect.Selected {
+					preset.Conversion = conversionValues[i]
+				}
+			}
+
+			presets := loadPresets(a.Preferences())
+			if existing := findPreset(presets, name); existing != nil {
+				*existing = preset
+			} else {
+				presets = append(presets, preset)
+			}
+			savePresets(a.Preferences(), presets)
+			a.Preferences().SetString("active_preset", name)
+			refreshPresets()
+		}, w)
+	})
+
+	deletePresetBtn := widget.NewButton(tr("Delete Preset"), func() {
+		preset := activePreset(a.Preferences())
+		if preset == nil {
+			return
+		}
+		dialog.ShowConfirm(tr("Delete Preset"), trf("Delete the preset \"%s\"?", preset.Name), func(ok bool) {
+			if !ok {
+				return
+			}
+			var presets []Preset
+			for _, p := range loadPresets(a.Preferences()) {
+				if p.Name != preset.Name {
+					presets = append(presets, p)
+				}
+			}
+			savePresets(a.Preferences(), presets)
+			a.Preferences().SetString("active_preset", "")
+			refreshPresets()
+		}, w)
+	})
+
+	// Previews are cached per file and track, as extracting a track reads the whole MKV file
+	previews := map[string]*trackPreview{}
+	var previewKey string
+	showPreview := func(preview *trackPreview) {
+		previewLabel.SetText(preview.Text)
+		previewImages.RemoveAll()
+		for _, img := range preview.Images {
+			// Thumbnails keep the aspect ratio of the subtitle bitmap
+			thumb := canvas.NewImageFromImage(img)
+			thumb.FillMode = canvas.ImageFillContain
+			size := img.Bounds().Size()
+			thumb.SetMinSize(fyne.NewSize(240, float32(max(240*size.Y/max(size.X, 1), 24))))
+			previewImages.Add(thumb)
+		}
+		previewScroll.ScrollToTop()
+	}
+	var selectedTrack *TrackItem
+	trackTable.OnSelected = func(t *TrackItem) {
+		selectedTrack = t
+		showDetails(t)
+		key := fmt.Sprintf("%s#%d", mkvPath, t.Num)
+		previewKey = key
+		if preview, ok := previews[key]; ok {
+			showPreview(preview)
+			return
+		}
+
+		showPreview(&trackPreview{Text: trf("Loading preview of track %d...", t.Num)})
+		path := mkvPath
+		go func() {
+			preview, err := loadTrackPreview(path, t)
+			fyne.Do(func() {
+				if err != nil {
+					preview = &trackPreview{Text: err.Error()}
+				} else {
+					previews[key] = preview
+				}
+				// Ignore previews of tracks that are no longer selected
+				if previewKey != key {
+					return
+				}
+				showPreview(preview)
+			})
+		}()
+	}
+
+	// OCR quality of the next run: fast models are quicker, best models more accurate
+	ocrQualities := []string{OCRQualityFast, OCRQualityBest}
+	ocrQualityNames := []string{tr("Fast"), tr("Best")}
+	ocrQualitySelect := widget.NewSelect(ocrQualityNames, func(selected string) {
+		for i, name := range ocrQualityNames {
+			if name == selected {
+				a.Preferences().SetString("ocr_quality", ocrQualities[i])
+			}
+		}
+	})
+	for i, quality := range ocrQualities {
+		if quality == a.Preferences().StringWithFallback("ocr_quality", OCRQualityFast) {
+			ocrQualitySelect.SetSelected(ocrQualityNames[i])
+		}
+	}
+
+	selectionRow := container.NewHBox(selectAllBtn, selectNoneBtn, invertSelectionBtn, selectLanguageBtn,
+		layout.NewSpacer(), widget.NewLabel(tr("OCR quality:")), ocrQualitySelect,
+		widget.NewLabel(tr("Preset:")), presetSelect, savePresetBtn, deletePresetBtn)
+
+	// Buttons to show the extraction results in the system file manager
+	openOutDirBtn := widget.NewButton(tr("Open Output Folder"), func() {
+		if outDir == "" {
+			dialog.ShowError(errors.New(tr("No output directory selected.")), w)
+			return
+		}
+		if err := openFolder(outDir); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	previewVideoBtn := widget.NewButton(tr("Preview with Video"), func() {
+		subIndex := -1
+		for i, t := range trackItems {
+			if t == selectedTrack {
+				subIndex = i
+			}
+		}
+		if subIndex < 0 {
+			dialog.ShowInformation(tr("Preview with Video"), tr("Select a track in the table first."), w)
+			return
+		}
+
+		cmd, err := videoPreviewCommand(mkvPath, selectedTrack, subIndex)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		logPane.Add(fmt.Sprintf("[DEBUG] Running: %s", strings.Join(cmd.Args, " ")))
+		if err := cmd.Start(); err != nil {
+			dialog.ShowError(fmt.Errorf("Error starting %s: %v", cmd.Args[0], err), w)
+			return
+		}
+		go cmd.Wait()
+	})
+	revealFileBtn := widget.NewButton(tr("Reveal File"), func() {
+		if selectedTrack == nil || selectedTrack.OutputPath == "" {
+			dialog.ShowInformation(tr("Reveal File"), tr("Select an extracted track in the table first."), w)
+			return
+		}
+		if err := revealFile(selectedTrack.OutputPath); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+
+	// Create Support button with improved UX
+	supportBtn := widget.NewButton(tr("Donate ☕"), func() {
+		// Show a confirmation dialog with information about the donation
+		confirm := dialog.NewConfirm(
+			tr("Support Subtitle Forge"),
+			tr("Your donation helps maintain and improve Subtitle Forge. Would you like to proceed to PayPal?"),
+			func(ok bool) {
+				if ok {
+					supportURL, _ := url.Parse("https://paypal.me/VenimK")
+					fyne.CurrentApp().OpenURL(supportURL)
+				}
+			},
+			w,
+		)
+		confirm.SetDismissText(tr("Cancel"))
+		confirm.SetConfirmText(tr("Donate"))
+		confirm.Show()
+	})
+	supportBtn.Importance = widget.HighImportance
+
+	// Toggle to extract dropped files right away
+	autoExtractCheck := widget.NewCheck(tr("Auto-extract on drop"), func(checked bool) {
+		a.Preferences().SetBool("auto_extract_on_drop", checked)
+	})
+	autoExtractCheck.SetChecked(a.Preferences().Bool("auto_extract_on_drop"))
+
+	// Create button row for better layout
+	buttonRow := container.NewHBox(loadTracksBtn, startExtractBtn, startQueueBtn, pauseBtn, cancelBtn, clearQueueBtn, autoExtractCheck, layout.NewSpacer(), supportBtn)
+
+	// Setup keyboard shortcuts for main actions
+	setupKeyboardShortcuts(fileBtn.OnTapped, dirBtn.OnTapped, loadTracksBtn.OnTapped, startExtractBtn.OnTapped, func() {
+		trackTable.Focus(w.Canvas())
+	})
+
+	// Use app.NewWithID for better performance and to avoid preferences API warnings
+	// This was already set at the beginning of main()
 
 	// Use a more efficient layout with container.NewBorder for better performance
 	// Create app title with version
@@ -2747,41 +2962,60 @@ func main() {
 
 	topContent := container.NewVBox(
 		titleLabel,
-		fileBtn,
+		container.NewBorder(nil, nil, nil, recentBtn, fileBtn),
 		selectedFile,
 		dirBtn,
 		selectedDir,
+		container.NewHBox(openOutDirBtn, revealFileBtn, previewVideoBtn),
 		buttonRow,
 		currentTrackLabel,
 		progress,
+		container.NewBorder(nil, nil, widget.NewLabel(tr("Current track:")), nil, trackProgress),
+		etaLabel,
 	)
 
 	middleContent := container.NewVBox(
-		widget.NewLabel("Subtitle Tracks:"),
-		trackListScroll,
+		widget.NewLabel(tr("Queue:")),
+		queueListScroll,
+		widget.NewLabel(tr("Subtitle Tracks:")),
+		selectionRow,
+		trackTable.Content(),
+		trackList,
 	)
 
 	bottomContent := container.NewVBox(
-		widget.NewLabel("Results:"),
-		resultScroll,
+		widget.NewLabel(tr("Log:")),
+		logPane.Content(),
 		dependencyButtons,
 	)
 
+	previewContent := container.NewAppTabs(
+		container.NewTabItem(tr("Preview"), previewScroll),
+		container.NewTabItem(tr("Details"), detailsScroll),
+	)
+
+	tracksSplit := container.NewHSplit(middleContent, previewContent)
+	tracksSplit.Offset = 0.7
+
 	// Create tab for subtitle extraction (existing functionality)
 	extractTabContent := container.NewBorder(
 		topContent,
 		bottomContent,
 		nil,
 		nil,
-		middleContent,
+		tracksSplit,
 	)
 
 	// Create tab for subtitle insertion
 	// Create file selection widgets for subtitle insertion
-	insertMkvFileLabel := widget.NewLabel("No MKV file selected")
-	insertSrtFileLabel := widget.NewLabel("No SRT file selected")
+	insertMkvFileLabel := widget.NewLabel(tr("No MKV file selected"))
 
-	selectInsertMkvBtn := widget.NewButton("Select MKV File", func() {
+	// Subtitle files to insert, each with its own track options
+	var insertSubs []*InsertSubtitle
+	insertSubsBox := container.NewVBox()
+	var addInsertSubtitle func(path string)
+
+	selectInsertMkvBtn := widget.NewButton(tr("Select MKV File"), func() {
 		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
 			if err != nil {
 				dialog.ShowError(err, w)
@@ -2793,7 +3027,7 @@ func main() {
 
 			filePath := reader.URI().Path()
 			if !strings.HasSuffix(strings.ToLower(filePath), ".mkv") {
-				dialog.ShowInformation("Invalid File", "Please select an MKV file", w)
+				dialog.ShowInformation(tr("Invalid File"), tr("Please select an MKV file"), w)
 				return
 			}
 
@@ -2803,7 +3037,7 @@ func main() {
 		fd.Show()
 	})
 
-	selectInsertSrtBtn := widget.NewButton("Select SRT File", func() {
+	selectInsertSrtBtn := widget.NewButton(tr("Add Subtitle File"), func() {
 		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
 			if err != nil {
 				dialog.ShowError(err, w)
@@ -2814,14 +3048,14 @@ func main() {
 			}
 
 			filePath := reader.URI().Path()
-			if !strings.HasSuffix(strings.ToLower(filePath), ".srt") {
-				dialog.ShowInformation("Invalid File", "Please select an SRT file", w)
+			if !isInsertSubtitle(filePath) {
+				dialog.ShowInformation(tr("Invalid File"), tr("Please select an SRT, ASS, SSA, VTT, SUP or IDX/SUB subtitle file"), w)
 				return
 			}
 
-			insertSrtFileLabel.SetText(filePath)
+			addInsertSubtitle(filePath)
 		}, w)
-		fd.SetFilter(storage.NewExtensionFileFilter([]string{".srt"}))
+		fd.SetFilter(storage.NewExtensionFileFilter(insertSubtitleExts))
 		fd.Show()
 	})
 
@@ -2906,23 +3140,199 @@ func main() {
 	insertResultScroll := container.NewScroll(insertResultLabel)
 	insertResultScroll.SetMinSize(fyne.NewSize(800, 150))
 
+	// Outcome of checking the muxed file against the planned track layout
+	insertVerifyIcon := widget.NewIcon(nil)
+	insertVerifyLabel := widget.NewLabel("")
+	insertVerifyLabel.Wrapping = fyne.TextWrapWord
+	insertVerifyBox := container.NewBorder(nil, nil, insertVerifyIcon, nil, insertVerifyLabel)
+	insertVerifyBox.Hide()
+
+	// showInsertVerification shows a green check when the inserted subtitles
+	// are in the output as planned, and what differs otherwise
+	showInsertVerification := func(mismatches []string, err error) {
+		switch {
+		case err != nil:
+			insertVerifyIcon.SetResource(theme.NewWarningThemedResource(theme.WarningIcon()))
+			insertVerifyLabel.Importance = widget.WarningImportance
+			insertVerifyLabel.SetText(trf("Could not verify the output: %v", err))
+		case len(mismatches) > 0:
+			insertVerifyIcon.SetResource(theme.NewErrorThemedResource(theme.ErrorIcon()))
+			insertVerifyLabel.Importance = widget.DangerImportance
+			insertVerifyLabel.SetText(tr("The output does not match the planned tracks:") + "\n- " + strings.Join(mismatches, "\n- "))
+		default:
+			insertVerifyIcon.SetResource(theme.NewSuccessThemedResource(theme.ConfirmIcon()))
+			insertVerifyLabel.Importance = widget.SuccessImportance
+			insertVerifyLabel.SetText(tr("Verified: the inserted subtitles are in the output with the planned language, name and flags"))
+		}
+		insertVerifyBox.Show()
+	}
+
+	// verificationNote sums up the verification of a batch or queued job
+	verificationNote := func(mismatches []string, err error) string {
+		switch {
+		case err != nil:
+			return "Not verified: " + err.Error()
+		case len(mismatches) > 0:
+			return "Verification failed: " + strings.Join(mismatches, "; ")
+		}
+		return "Verified"
+	}
+
+	// Progress of the running mkvmerge, which can take minutes for large files
+	insertProgress := widget.NewProgressBar()
+	insertProgressLabel := widget.NewLabel("")
+	insertProgressBox := container.NewVBox(insertProgress, insertProgressLabel)
+	insertProgressBox.Hide()
+
 	// Create default track options
-	defaultTrack := widget.NewCheck("Set as default subtitle track", nil)
-	defaultTrack.SetChecked(true)
+	defaultTrack := widget.NewCheck(tr("Set as default subtitle track"), func(checked bool) {
+		a.Preferences().SetBool("insert_default_track", checked)
+	})
+	defaultTrack.SetChecked(a.Preferences().BoolWithFallback("insert_default_track", true))
 
 	// Create forced track option
-	forcedTrack := widget.NewCheck("Mark as forced subtitle track", nil)
+	forcedTrack := widget.NewCheck(tr("Mark as forced subtitle track"), func(checked bool) {
+		a.Preferences().SetBool("insert_forced_track", checked)
+	})
+	forcedTrack.SetChecked(a.Preferences().Bool("insert_forced_track"))
 	
 	// Create option to remove other subtitle tracks
-	removeOtherTracks := widget.NewCheck("Remove all other subtitle tracks", nil)
+	removeOtherTracks := widget.NewCheck(tr("Remove all other subtitle tracks"), func(checked bool) {
+		a.Preferences().SetBool("insert_remove_other_tracks", checked)
+	})
+	removeOtherTracks.SetChecked(a.Preferences().Bool("insert_remove_other_tracks"))
+
+	// Create option to replace only the existing tracks in the inserted languages
+	replaceSameLang := widget.NewCheck(tr("Replace existing subtitle tracks in the same language"), func(checked bool) {
+		a.Preferences().SetBool("insert_replace_same_language", checked)
+	})
+	replaceSameLang.SetChecked(a.Preferences().Bool("insert_replace_same_language"))
+
+	// Removing all subtitle tracks already replaces those in the same language
+	removeOtherTracks.OnChanged = func(checked bool) {
+		a.Preferences().SetBool("insert_remove_other_tracks", checked)
+		if checked {
+			replaceSameLang.Disable()
+		} else {
+			replaceSameLang.Enable()
+		}
+	}
+	removeOtherTracks.OnChanged(removeOtherTracks.Checked)
+
+	// Create option for where the new tracks go among the existing ones
+	trackPositions := map[string]string{
+		tr("After all tracks"):             InsertPositionEnd,
+		tr("As the first subtitle tracks"): InsertPositionFirstSubtitle,
+		tr("After track number"):           InsertPositionAfter,
+	}
+	afterTrackEntry := widget.NewEntry()
+	afterTrackEntry.SetPlaceHolder(tr("Track ID"))
+	afterTrackEntry.SetText(a.Preferences().String("insert_after_track"))
+	afterTrackEntry.OnChanged = func(s string) {
+		a.Preferences().SetString("insert_after_track", s)
+	}
+	trackPositionSelect := widget.NewSelect([]string{tr("After all tracks"), tr("As the first subtitle tracks"), tr("After track number")}, func(selected string) {
+		position := trackPositions[selected]
+		a.Preferences().SetString("insert_track_position", position)
+		if position == InsertPositionAfter {
+			afterTrackEntry.Show()
+		} else {
+			afterTrackEntry.Hide()
+		}
+	})
+	trackPositionSelect.SetSelectedIndex(0)
+	for label, position := range trackPositions {
+		if position == a.Preferences().StringWithFallback("insert_track_position", InsertPositionEnd) {
+			trackPositionSelect.SetSelected(label)
+		}
+	}
+
+	// Create timing offset option, e.g. +500 for subtitles that show too early
+	offsetEntry := widget.NewEntry()
+	offsetEntry.SetPlaceHolder("0")
+	offsetEntry.Validator = validateOffset
+
+	// Create character set option for legacy text subtitles, e.g. CP1250
+	charsetSelect := widget.NewSelect(append([]string{tr("Auto-detect")}, insertCharsets...), func(selected string) {
+		if selected == tr("Auto-detect") {
+			a.Preferences().RemoveValue("insert_charset")
+		} else {
+			a.Preferences().SetString("insert_charset", selected)
+		}
+	})
+	charsetSelect.SetSelected(a.Preferences().StringWithFallback("insert_charset", tr("Auto-detect")))
 
 	// Create output file name options
 	outputNameEntry := widget.NewEntry()
-	outputNameEntry.SetPlaceHolder("Leave empty for auto naming")
+	outputNameEntry.SetPlaceHolder(tr("Leave empty for auto naming"))
+
+	// Create output folder option, empty to write next to the MKV file
+	insertOutputDir := a.Preferences().String("insert_output_dir")
+	insertOutputDirLabel := widget.NewLabel("")
+	insertOutputDirLabel.Truncation = fyne.TextTruncateEllipsis
+	var useSourceDirBtn *widget.Button
+	setInsertOutputDir := func(dir string) {
+		insertOutputDir = dir
+		a.Preferences().SetString("insert_output_dir", dir)
+		if dir == "" {
+			insertOutputDirLabel.SetText(tr("Same folder as the MKV file"))
+			useSourceDirBtn.Disable()
+		} else {
+			insertOutputDirLabel.SetText(dir)
+			useSourceDirBtn.Enable()
+		}
+	}
+	insertOutputDirBtn := widget.NewButton(tr("Change Output Directory"), func() {
+		fd := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil || uri == nil {
+				return
+			}
+			setInsertOutputDir(uri.Path())
+		}, w)
+		if dir := listableDir(insertOutputDir); dir != nil {
+			fd.SetLocation(dir)
+		}
+		fd.Show()
+	})
+	useSourceDirBtn = widget.NewButton(tr("Use Source Folder"), func() {
+		setInsertOutputDir("")
+	})
+	setInsertOutputDir(insertOutputDir)
+
+	// Create options to keep library scanners from taking the output for a new file
+	keepAttributesCheck := widget.NewCheck(tr("Keep the date and permissions of the original file"), func(checked bool) {
+		a.Preferences().SetBool("insert_keep_attributes", checked)
+	})
+	keepAttributesCheck.SetChecked(a.Preferences().Bool("insert_keep_attributes"))
+	replaceOriginalCheck := widget.NewCheck(tr("Replace the original file once the output is verified"), nil)
+	replaceOriginalCheck.OnChanged = func(checked bool) {
+		a.Preferences().SetBool("insert_replace_original", checked)
+		// The output is written next to the original, so it can be renamed over it
+		if checked {
+			insertOutputDirBtn.Disable()
+			useSourceDirBtn.Disable()
+			outputNameEntry.Disable()
+		} else {
+			insertOutputDirBtn.Enable()
+			outputNameEntry.Enable()
+			setInsertOutputDir(insertOutputDir)
+		}
+	}
+	replaceOriginalCheck.SetChecked(a.Preferences().Bool("insert_replace_original"))
+
+	// insertOutputFor returns the file a subtitle insertion into mkvPath writes
+	insertOutputFor := func(mkvPath string) string {
+		outputPath := insertOutputPath(mkvPath)
+		if insertOutputDir != "" && !replaceOriginalCheck.Checked {
+			outputPath = filepath.Join(insertOutputDir, filepath.Base(outputPath))
+		}
+		return outputPath
+	}
 
 	// Show language dropdown change handler
 	langDropdown.OnChanged = func(selected string) {
 		selectedLang = selected
+		a.Preferences().SetString("insert_language", selected)
 		if selected == "Custom" {
 			customLangDropdown.Show()
 			// Don't auto-update track name for custom selection
@@ -2948,97 +3358,768 @@ func main() {
 		}
 	}
 
-	// Create insert button
-	insertSubtitleBtn := widget.NewButton("Insert Subtitle", func() {
-		// Check if files are selected
-		mkvPath := insertMkvFileLabel.Text
-		srtPath := insertSrtFileLabel.Text
-
-		if mkvPath == "No MKV file selected" || srtPath == "No SRT file selected" {
-			dialog.ShowInformation("Missing Files", "Please select both MKV and SRT files", w)
-			return
+	// Restore the language used for the last insertion
+	langDropdown.SetSelected(a.Preferences().StringWithFallback("insert_language", "English"))
+
+	// Fonts to attach for ASS/SSA subtitles, so their styling renders as intended
+	var insertFonts []string
+	insertFontsBox := container.NewVBox()
+	fontStatusLabel := widget.NewLabel("")
+	fontStatusLabel.Wrapping = fyne.TextWrapWord
+
+	// refreshInsertFonts lists the attached fonts and the fonts the ASS/SSA
+	// subtitles use, marking the ones no attached font provides
+	var refreshInsertFonts func()
+	refreshInsertFonts = func() {
+		insertFontsBox.RemoveAll()
+		for _, font := range insertFonts {
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				insertFonts = slices.DeleteFunc(insertFonts, func(other string) bool { return other == font })
+				refreshInsertFonts()
+			})
+			insertFontsBox.Add(container.NewBorder(nil, nil, nil, removeBtn, widget.NewLabel(filepath.Base(font))))
 		}
 
-		// Get language code based on selection
-		var lang string
-		if selectedLang == "Custom" {
-			lang = selectedLangCode // Use the selected language code from dropdown
-		} else {
-			lang = languages[selectedLang]
+		var used []string
+		for _, sub := range insertSubs {
+			if isASSSubtitle(sub.Path) {
+				fonts, _ := assFontNames(sub.Path)
+				used = append(used, fonts...)
+			}
 		}
-
-		// Get track name
-		trackName := trackNameEntry.Text
-		if trackName == "" {
-			trackName = selectedLang // Use selected language name as default
+		if len(used) == 0 {
+			fontStatusLabel.SetText("")
+			return
 		}
+		missing := missingFonts(used, insertFonts, nil)
+		var status []string
+		for _, font := range used {
+			if slices.Contains(missing, font) {
+				status = append(status, trf("%s (missing)", font))
+			} else {
+				status = append(status, font)
+			}
+		}
+		fontStatusLabel.SetText(tr("Fonts used by the subtitles: ") + strings.Join(status, ", "))
+	}
 
-		// Create output file path
-		dir := filepath.Dir(mkvPath)
-		baseName := filepath.Base(mkvPath)
-		baseName = strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	// addInsertFont attaches a font file to the insertion
+	addInsertFont := func(path string) {
+		if !slices.Contains(insertFonts, path) {
+			insertFonts = append(insertFonts, path)
+		}
+		refreshInsertFonts()
+	}
 
-		// Use custom output name if provided
-		outputName := outputNameEntry.Text
-		if outputName == "" {
-			outputName = baseName + "_with_subtitles.mkv"
-		} else if !strings.HasSuffix(strings.ToLower(outputName), ".mkv") {
-			outputName = outputName + ".mkv"
+	attachFontBtn := widget.NewButton(tr("Attach Font..."), func() {
+		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			reader.Close()
+			addInsertFont(reader.URI().Path())
+		}, w)
+		fd.SetFilter(storage.NewExtensionFileFilter(fontFileExts))
+		fd.Show()
+	})
+
+	// Chapters muxed with the subtitles, replacing those of the MKV
+	var insertChapters []*Chapter
+	insertChaptersBox := container.NewVBox()
+
+	// refreshInsertChapters rebuilds the editable rows of the chapters
+	var refreshInsertChapters func()
+	refreshInsertChapters = func() {
+		insertChaptersBox.RemoveAll()
+		for _, chapter := range insertChapters {
+			timeEntry := widget.NewEntry()
+			timeEntry.SetText(formatChapterTime(chapter.Start))
+			timeEntry.Validator = func(s string) error {
+				_, err := parseChapterTime(s)
+				return err
+			}
+			timeEntry.OnChanged = func(s string) {
+				if start, err := parseChapterTime(s); err == nil {
+					chapter.Start = start
+				}
+			}
+			nameEntry := widget.NewEntry()
+			nameEntry.SetText(chapter.Name)
+			nameEntry.OnChanged = func(s string) {
+				chapter.Name = s
+			}
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				insertChapters = slices.DeleteFunc(insertChapters, func(other *Chapter) bool { return other == chapter })
+				refreshInsertChapters()
+			})
+			insertChaptersBox.Add(container.NewBorder(nil, nil, nil, removeBtn, container.NewGridWithColumns(2, timeEntry, nameEntry)))
 		}
+	}
 
-		outputPath := filepath.Join(dir, outputName)
+	importChaptersBtn := widget.NewButton(tr("Import Chapters..."), func() {
+		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			chapters, err := parseChapters(data)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			insertChapters = nil
+			for _, chapter := range chapters {
+				insertChapters = append(insertChapters, &chapter)
+			}
+			refreshInsertChapters()
+		}, w)
+		fd.SetFilter(storage.NewExtensionFileFilter([]string{".xml", ".txt"}))
+		fd.Show()
+	})
+	addChapterBtn := widget.NewButton(tr("Add Chapter"), func() {
+		chapter := &Chapter{Name: trf("Chapter %d", len(insertChapters)+1)}
+		if len(insertChapters) > 0 {
+			chapter.Start = insertChapters[len(insertChapters)-1].Start
+		}
+		insertChapters = append(insertChapters, chapter)
+		refreshInsertChapters()
+	})
+	clearChaptersBtn := widget.NewButton(tr("Clear"), func() {
+		insertChapters = nil
+		refreshInsertChapters()
+	})
 
-		insertResultLabel.SetText("Adding subtitle to MKV file...\n")
+	// writeInsertChapters writes the chapters to a temporary file for
+	// mkvmerge, returning "" when there are none
+	writeInsertChapters := func() (string, error) {
+		if len(insertChapters) == 0 {
+			return "", nil
+		}
+		var chapters []Chapter
+		for _, chapter := range insertChapters {
+			chapters = append(chapters, *chapter)
+		}
+		file, err := os.CreateTemp("", "chapters_*.txt")
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+		if _, err := file.WriteString(formatOGMChapters(chapters)); err != nil {
+			os.Remove(file.Name())
+			return "", err
+		}
+		return file.Name(), nil
+	}
 
-		// Build mkvmerge command with options
-		mkvmergeArgs := []string{
-			"-o", outputPath,
+	// refreshInsertSubs rebuilds the rows of the subtitle files to insert
+	var refreshInsertSubs func()
+	refreshInsertSubs = func() {
+		defer refreshInsertFonts()
+		insertSubsBox.RemoveAll()
+		if len(insertSubs) == 0 {
+			insertSubsBox.Add(widget.NewLabel(tr("No subtitle files selected")))
+			return
 		}
-		
-		// If removing other subtitle tracks is checked, use --no-subtitles option
-		if removeOtherTracks.Checked {
-			mkvmergeArgs = append(mkvmergeArgs, "--no-subtitles", mkvPath)
-			insertResultLabel.SetText(insertResultLabel.Text + "\nRemoving all existing subtitle tracks...")
-		} else {
-			mkvmergeArgs = append(mkvmergeArgs, mkvPath)
+		for _, sub := range insertSubs {
+			langEntry := widget.NewSelectEntry(langCodes)
+			langEntry.SetText(sub.Lang)
+			langEntry.OnChanged = func(s string) {
+				sub.Lang = strings.TrimSpace(s)
+			}
+			nameEntry := widget.NewEntry()
+			nameEntry.SetPlaceHolder(tr("Track Name:"))
+			nameEntry.SetText(sub.Name)
+			nameEntry.OnChanged = func(s string) {
+				sub.Name = s
+			}
+			offsetEntry := widget.NewEntry()
+			offsetEntry.SetPlaceHolder(tr("Offset (ms)"))
+			if sub.Offset != 0 {
+				offsetEntry.SetText(strconv.Itoa(sub.Offset))
+			}
+			offsetEntry.Validator = validateOffset
+			offsetEntry.OnChanged = func(s string) {
+				if offset, err := parseOffset(s); err == nil {
+					sub.Offset = offset
+				}
+			}
+
+			defaultCheck := widget.NewCheck(tr("Default"), nil)
+			defaultCheck.SetChecked(sub.Default)
+			defaultCheck.OnChanged = func(checked bool) {
+				sub.Default = checked
+				if !checked {
+					return
+				}
+				// Only one of the inserted tracks can be the default
+				for _, other := range insertSubs {
+					other.Default = other == sub
+				}
+				refreshInsertSubs()
+			}
+			forcedCheck := widget.NewCheck(tr("Forced"), nil)
+			forcedCheck.SetChecked(sub.Forced)
+			forcedCheck.OnChanged = func(checked bool) {
+				sub.Forced = checked
+			}
+
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				for i, other := range insertSubs {
+					if other == sub {
+						insertSubs = append(insertSubs[:i], insertSubs[i+1:]...)
+						break
+					}
+				}
+				refreshInsertSubs()
+			})
+
+			insertSubsBox.Add(container.NewBorder(
+				nil,
+				nil,
+				widget.NewLabel(filepath.Base(sub.Path)),
+				container.NewHBox(defaultCheck, forcedCheck, removeBtn),
+				container.NewGridWithColumns(3, langEntry, nameEntry, offsetEntry),
+			))
 		}
-		
-		// Add language and track name options for the SRT file
-		mkvmergeArgs = append(mkvmergeArgs, 
-			"--language", "0:" + lang,
-			"--track-name", "0:" + trackName,
-		)
+	}
+	refreshInsertSubs()
+
+	// newInsertSubtitle creates a subtitle to insert with the options chosen
+	// under Subtitle Options, or the language detected from the file
+	newInsertSubtitle := func(path string) *InsertSubtitle {
+		lang := languages[selectedLang]
+		if selectedLang == "Custom" {
+			lang = selectedLangCode
+		}
+		name := trackNameEntry.Text
+		if name == "" {
+			name = selectedLang
+		}
+		// Prefer the language the file names or is written in
+		if detected := detectSubtitleLanguage(path); detected != "" {
+			lang = detected
+			name = detected
+			for langName, code := range languages {
+				if code == detected {
+					name = langName
+				}
+			}
+		}
+		offset, _ := parseOffset(offsetEntry.Text)
+		return &InsertSubtitle{
+			Path:   path,
+			Lang:   lang,
+			Name:   name,
+			Forced: forcedTrack.Checked || isForcedSubtitleFile(path),
+			Offset: offset,
+		}
+	}
 
-		// Add default track option if checked
+	// addInsertSubtitle adds a subtitle file to insert; the first file added
+	// becomes the default track
+	addInsertSubtitle = func(path string) {
+		path, err := insertSubtitlePath(path)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		for _, sub := range insertSubs {
+			if sub.Path == path {
+				return
+			}
+		}
+
+		sub := newInsertSubtitle(path)
 		if defaultTrack.Checked {
-			mkvmergeArgs = append(mkvmergeArgs, "--default-track", "0:yes")
+			sub.Default = true
+			for _, other := range insertSubs {
+				if other.Default {
+					sub.Default = false
+				}
+			}
 		}
+		insertSubs = append(insertSubs, sub)
+		refreshInsertSubs()
+	}
 
-		// Add forced track option if checked
-		if forcedTrack.Checked {
-			mkvmergeArgs = append(mkvmergeArgs, "--forced-track", "0:yes")
+	// insertOptions reads the options that apply to every insertion
+	insertOptions := func() (InsertOptions, error) {
+		opts := InsertOptions{
+			RemoveOther:     removeOtherTracks.Checked,
+			ReplaceSameLang: replaceSameLang.Checked,
+			Position:        trackPositions[trackPositionSelect.Selected],
+			KeepAttributes:  keepAttributesCheck.Checked,
+			ReplaceOriginal: replaceOriginalCheck.Checked,
+		}
+		if charsetSelect.SelectedIndex() > 0 {
+			opts.Charset = charsetSelect.Selected
+		}
+		opts.Fonts = slices.Clone(insertFonts)
+		if opts.Position == InsertPositionAfter {
+			n, err := strconv.Atoi(strings.TrimSpace(afterTrackEntry.Text))
+			if err != nil {
+				return opts, errors.New(tr("Please enter the ID of the track to insert after"))
+			}
+			opts.AfterTrack = n
 		}
+		return opts, nil
+	}
 
-		// Add SRT file at the end
-		mkvmergeArgs = append(mkvmergeArgs, srtPath)
+	// insertMux runs a prepared insertion, reporting mkvmerge's progress;
+	// prefix names the file in batch runs
+	insertMux := func(plan *InsertPlan, prefix string) ([]byte, error) {
+		start := time.Now()
+		output, err := runWithProgress(exec.Command("mkvmerge", plan.Args...), func(percent int) {
+			fyne.Do(func() {
+				insertProgress.SetValue(float64(percent) / 100)
+				insertProgressLabel.SetText(fmt.Sprintf("%s%d%%, %s", prefix, percent, percentRemaining(start, percent)))
+			})
+		})
+		return output, mkvmergeError(err)
+	}
 
-		// Run mkvmerge command to add subtitle
-		go func() {
-			cmd := exec.Command("mkvmerge", mkvmergeArgs...)
+	var insertSubtitleBtn, batchInsertBtn, runQueueBtn *widget.Button
+
+	// setInserting shows the progress bar and blocks new insertions while mkvmerge runs
+	setInserting := func(inserting bool) {
+		if inserting {
+			insertVerifyBox.Hide()
+			insertProgress.SetValue(0)
+			insertProgressLabel.SetText(percentRemaining(time.Now(), 0))
+			insertProgressBox.Show()
+			insertSubtitleBtn.Disable()
+			batchInsertBtn.Disable()
+			runQueueBtn.Disable()
+		} else {
+			insertProgressBox.Hide()
+			insertSubtitleBtn.Enable()
+			batchInsertBtn.Enable()
+			runQueueBtn.Enable()
+		}
+	}
 
-			output, err := cmd.CombinedOutput()
+	// insertRequest checks that the Insert tab is ready to mux and returns the
+	// MKV file, the file to write and the options, or false after telling the
+	// user what is missing
+	insertRequest := func() (mkvPath, outputPath string, opts InsertOptions, ok bool) {
+		mkvPath = insertMkvFileLabel.Text
+		if mkvPath == tr("No MKV file selected") || len(insertSubs) == 0 {
+			dialog.ShowInformation(tr("Missing Files"), tr("Please select an MKV file and at least one subtitle file"), w)
+			return
+		}
+		for _, sub := range insertSubs {
+			if sub.Lang == "" {
+				dialog.ShowInformation(tr("Missing Language"), trf("Please enter a language code for %s", filepath.Base(sub.Path)), w)
+				return
+			}
+		}
+		opts, err := insertOptions()
+		if err != nil {
+			dialog.ShowInformation(tr("Invalid Track"), err.Error(), w)
+			return
+		}
+
+		// Use custom output name if provided
+		outputPath = insertOutputFor(mkvPath)
+		outputName := outputNameEntry.Text
+		if outputName != "" && !opts.ReplaceOriginal {
+			if !strings.HasSuffix(strings.ToLower(outputName), ".mkv") {
+				outputName = outputName + ".mkv"
+			}
+			outputPath = filepath.Join(filepath.Dir(outputPath), outputName)
+		}
+		return mkvPath, outputPath, opts, true
+	}
+
+	// Create insert button
+	insertSubtitleBtn = widget.NewButton(tr("Insert Subtitle"), func() {
+		mkvPath, outputPath, opts, ok := insertRequest()
+		if !ok {
+			return
+		}
+
+		subs := append([]*InsertSubtitle(nil), insertSubs...)
+
+		var err error
+		opts.ChaptersFile, err = writeInsertChapters()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		removeChaptersFile := func() {
+			if opts.ChaptersFile != "" {
+				os.Remove(opts.ChaptersFile)
+			}
+		}
+
+		// runMux muxes all subtitles in one mkvmerge run
+		runMux := func(plan *InsertPlan) {
+			insertResultLabel.SetText(fmt.Sprintf("Adding %d subtitle file(s) to MKV file...\n", len(subs)))
+			if opts.RemoveOther {
+				insertResultLabel.SetText(insertResultLabel.Text + "\nRemoving all existing subtitle tracks...")
+			}
+			if len(plan.Replaced) > 0 {
+				insertResultLabel.SetText(insertResultLabel.Text + fmt.Sprintf("\nReplacing %d existing subtitle track(s) in the same language...", len(plan.Replaced)))
+			}
+
+			setInserting(true)
+
+			// Run mkvmerge command to add subtitle
+			go func() {
+				output, err := insertMux(plan, "")
+				removeChaptersFile()
+				var mismatches []string
+				var verifyErr, finishErr error
+				finalPath := outputPath
+				if err == nil {
+					mismatches, verifyErr = verifyInsert(outputPath, plan)
+					finalPath, finishErr = finishInsert(mkvPath, outputPath, opts, verifyErr == nil && len(mismatches) == 0)
+				}
+
+				fyne.Do(func() {
+					setInserting(false)
+					if err != nil {
+						insertResultLabel.SetText(insertResultLabel.Text + "\nError: " + err.Error() + "\n" + string(output))
+						return
+					}
+					showInsertVerification(mismatches, verifyErr)
+
+					insertResultLabel.SetText(insertResultLabel.Text + "\nSubtitle added successfully!\nOutput file: " + finalPath)
+					if finishErr != nil {
+						insertResultLabel.SetText(insertResultLabel.Text + "\nWarning: " + finishErr.Error())
+					}
+					if warnings := mkvmergeWarnings(output); len(warnings) > 0 {
+						for _, warning := range warnings {
+							insertResultLabel.SetText(insertResultLabel.Text + "\nWarning: " + warning.String())
+						}
+						showMuxWarnings(w, outputPath, warnings)
+					}
+				})
+			}()
+		}
 
+		// Show the tracks the output will contain before muxing
+		go func() {
+			// Fail before planning when the remuxed file cannot fit
+			err := checkFreeSpace(filepath.Dir(outputPath), insertOutputSize(mkvPath, subs, opts))
+			var plan *InsertPlan
+			if err == nil {
+				plan, err = planInsert(mkvPath, outputPath, subs, opts)
+			}
 			fyne.Do(func() {
 				if err != nil {
-					insertResultLabel.SetText(insertResultLabel.Text + "\nError: " + err.Error() + "\n" + string(output))
+					removeChaptersFile()
+					dialog.ShowError(err, w)
 					return
 				}
+				layoutLabel := widget.NewLabel(formatMuxLayout(plan.Tracks))
+				layoutLabel.TextStyle = fyne.TextStyle{Monospace: true}
+				layoutScroll := container.NewScroll(layoutLabel)
+				layoutScroll.SetMinSize(fyne.NewSize(700, 250))
+				header := container.NewVBox(widget.NewLabel(trf("%s will contain these tracks:", filepath.Base(outputPath))))
+				if opts.ChaptersFile != "" {
+					header.Add(widget.NewLabel(trf("%d chapter(s) will replace the chapters of the MKV.", len(insertChapters))))
+				}
+				if opts.ReplaceOriginal {
+					replaceLabel := widget.NewLabel(trf("%s will be replaced by the output once it is verified.", filepath.Base(mkvPath)))
+					replaceLabel.Importance = widget.WarningImportance
+					header.Add(replaceLabel)
+				}
+				if len(plan.MissingFonts) > 0 {
+					warning := widget.NewLabel(trf("Warning: these fonts used by the subtitles are not attached: %s", strings.Join(plan.MissingFonts, ", ")))
+					warning.Importance = widget.WarningImportance
+					warning.Wrapping = fyne.TextWrapWord
+					header.Add(warning)
+				}
+				content := container.NewBorder(header, nil, nil, nil, layoutScroll)
+				dialog.ShowCustomConfirm(tr("Track Layout"), tr("Insert Subtitle"), tr("Cancel"), content, func(ok bool) {
+					if ok {
+						runMux(plan)
+					} else {
+						removeChaptersFile()
+					}
+				}, w)
+			})
+		}()
+	})
+
+	// runBatchInsert muxes the subtitles of every job in turn into
+	// <name>_with_subtitles.mkv in the output folder
+	runBatchInsert := func(jobs []InsertJob, opts InsertOptions) {
+		var batch [][]*InsertSubtitle
+		for _, job := range jobs {
+			var subs []*InsertSubtitle
+			for i, path := range job.Subs {
+				sub := newInsertSubtitle(path)
+				sub.Default = i == 0 && defaultTrack.Checked
+				subs = append(subs, sub)
+			}
+			batch = append(batch, subs)
+		}
+
+		insertResultLabel.SetText(fmt.Sprintf("Batch inserting subtitles into %d MKV file(s)...\n", len(jobs)))
+		setInserting(true)
+
+		go func() {
+			succeeded := 0
+			for i, job := range jobs {
+				outputPath := insertOutputFor(job.MkvPath)
+				prefix := fmt.Sprintf("File %d of %d: %s, ", i+1, len(jobs), filepath.Base(job.MkvPath))
+				err := checkFreeSpace(filepath.Dir(outputPath), insertOutputSize(job.MkvPath, batch[i], opts))
+				var plan *InsertPlan
+				if err == nil {
+					plan, err = planInsert(job.MkvPath, outputPath, batch[i], opts)
+				}
+				var output []byte
+				if err == nil {
+					output, err = insertMux(plan, prefix)
+				}
+				note := ""
+				if err == nil {
+					mismatches, verifyErr := verifyInsert(outputPath, plan)
+					note = verificationNote(mismatches, verifyErr)
+					var finishErr error
+					outputPath, finishErr = finishInsert(job.MkvPath, outputPath, opts, verifyErr == nil && len(mismatches) == 0)
+					if finishErr != nil {
+						note += "\n    Warning: " + finishErr.Error()
+					}
+				}
+				fyne.Do(func() {
+					if err != nil {
+						insertResultLabel.SetText(insertResultLabel.Text + fmt.Sprintf("\n[!] %s: %v\n%s", filepath.Base(job.MkvPath), err, output))
+					} else {
+						succeeded++
+						insertResultLabel.SetText(insertResultLabel.Text + fmt.Sprintf("\n[x] %s: %d subtitle file(s) added, output: %s", filepath.Base(job.MkvPath), len(batch[i]), outputPath))
+						if len(plan.MissingFonts) > 0 {
+							insertResultLabel.SetText(insertResultLabel.Text + fmt.Sprintf("\n    Fonts not attached: %s", strings.Join(plan.MissingFonts, ", ")))
+						}
+						for _, warning := range mkvmergeWarnings(output) {
+							insertResultLabel.SetText(insertResultLabel.Text + "\n    Warning: " + warning.String())
+						}
+						insertResultLabel.SetText(insertResultLabel.Text + "\n    " + note)
+					}
+					insertResultScroll.ScrollToBottom()
+				})
+			}
+			fyne.DoAndWait(func() {
+				setInserting(false)
+				message := trf("%d of %d files muxed", succeeded, len(jobs))
+				insertResultLabel.SetText(insertResultLabel.Text + "\n\n" + message)
+				a.SendNotification(&fyne.Notification{Title: tr("Batch Insert"), Content: message})
+			})
+		}()
+	}
+
+	// Batch mode: pair the MKV files in a folder with their subtitles and mux them all
+	batchInsertBtn = widget.NewButton(tr("Batch Insert Folder..."), func() {
+		opts, err := insertOptions()
+		if err != nil {
+			dialog.ShowInformation(tr("Invalid Track"), err.Error(), w)
+			return
+		}
+		dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if uri == nil {
+				return
+			}
+			jobs, err := matchInsertJobs(uri.Path())
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if len(jobs) == 0 {
+				dialog.ShowInformation(tr("Batch Insert"), trf("No MKV files with matching subtitle files found in %s", uri.Path()), w)
+				return
+			}
+
+			var b strings.Builder
+			for _, job := range jobs {
+				b.WriteString(filepath.Base(job.MkvPath) + "\n")
+				for _, sub := range job.Subs {
+					b.WriteString("    " + filepath.Base(sub) + "\n")
+				}
+			}
+			pairsLabel := widget.NewLabel(b.String())
+			pairsScroll := container.NewScroll(pairsLabel)
+			pairsScroll.SetMinSize(fyne.NewSize(600, 300))
+			content := container.NewBorder(widget.NewLabel(trf("%d MKV file(s) with matching subtitles found:", len(jobs))), nil, nil, nil, pairsScroll)
+			dialog.ShowCustomConfirm(tr("Batch Insert"), tr("Insert All"), tr("Cancel"), content, func(ok bool) {
+				if ok {
+					runBatchInsert(jobs, opts)
+				}
+			}, w)
+		}, w)
+	})
+
+	// Mux queue: insertions set up one by one and run in turn, e.g. overnight
+	var muxQueue []*MuxJob
+	muxQueueBox := container.NewVBox()
+	queueStopping := false
+
+	// refreshMuxQueue lists the queued jobs with their state
+	var refreshMuxQueue func()
+	refreshMuxQueue = func() {
+		muxQueueBox.RemoveAll()
+		for _, job := range muxQueue {
+			nameLabel := widget.NewLabel(trf("%s -> %s (%d subtitle file(s))", filepath.Base(job.MkvPath), filepath.Base(job.OutputPath), len(job.Subs)))
+			nameLabel.Truncation = fyne.TextTruncateEllipsis
+			stateLabel := widget.NewLabel(tr(job.State))
+			switch job.State {
+			case "Done":
+				stateLabel.Importance = widget.SuccessImportance
+			case "Error":
+				stateLabel.Importance = widget.DangerImportance
+			}
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				if job.State == "Pending" && job.Opts.ChaptersFile != "" {
+					os.Remove(job.Opts.ChaptersFile)
+				}
+				muxQueue = slices.DeleteFunc(muxQueue, func(other *MuxJob) bool { return other == job })
+				refreshMuxQueue()
+			})
+			if job.State == "Running" {
+				removeBtn.Disable()
+			}
+			row := container.NewVBox(container.NewBorder(nil, nil, nil, container.NewHBox(stateLabel, removeBtn), nameLabel))
+			if job.Message != "" {
+				messageLabel := widget.NewLabel(job.Message)
+				messageLabel.Wrapping = fyne.TextWrapWord
+				messageLabel.SizeName = theme.SizeNameCaptionText
+				row.Add(messageLabel)
+			}
+			muxQueueBox.Add(row)
+		}
+	}
+
+	addToQueueBtn := widget.NewButton(tr("Add to Queue"), func() {
+		mkvPath, outputPath, opts, ok := insertRequest()
+		if !ok {
+			return
+		}
+		var err error
+		opts.ChaptersFile, err = writeInsertChapters()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		muxQueue = append(muxQueue, newMuxJob(mkvPath, outputPath, insertSubs, opts))
+		refreshMuxQueue()
+	})
+
+	stopQueueBtn := widget.NewButton(tr("Stop After Current"), func() {
+		queueStopping = true
+	})
+	stopQueueBtn.Disable()
+
+	// Run the pending jobs in turn until none are left or the
+	// user stops the queue
+	runQueueBtn = widget.NewButton(tr("Run Queue"), func() {
+		if !slices.ContainsFunc(muxQueue, func(job *MuxJob) bool { return job.State == "Pending" }) {
+			dialog.ShowInformation(tr("Mux Queue"), tr("There are no pending jobs in the queue"), w)
+			return
+		}
+		queueStopping = false
+		stopQueueBtn.Enable()
+		setInserting(true)
+		insertResultLabel.SetText(tr("Running the mux queue...") + "\n")
+
+		go func() {
+			done, failed := 0, 0
+			for {
+				var job *MuxJob
+				prefix := ""
+				fyne.DoAndWait(func() {
+					i := slices.IndexFunc(muxQueue, func(job *MuxJob) bool { return job.State == "Pending" })
+					if queueStopping || i < 0 {
+						return
+					}
+					job = muxQueue[i]
+					job.State = "Running"
+					prefix = fmt.Sprintf("Job %d of %d: %s, ", i+1, len(muxQueue), filepath.Base(job.MkvPath))
+					refreshMuxQueue()
+				})
+				if job == nil {
+					break
+				}
 
-				insertResultLabel.SetText(insertResultLabel.Text + "\nSubtitle added successfully!\nOutput file: " + outputPath + "\n" + string(output))
+				err := checkFreeSpace(filepath.Dir(job.OutputPath), insertOutputSize(job.MkvPath, job.Subs, job.Opts))
+				var plan *InsertPlan
+				if err == nil {
+					plan, err = planInsert(job.MkvPath, job.OutputPath, job.Subs, job.Opts)
+				}
+				var output []byte
+				if err == nil {
+					output, err = insertMux(plan, prefix)
+				}
+				if job.Opts.ChaptersFile != "" {
+					os.Remove(job.Opts.ChaptersFile)
+				}
+				note := ""
+				finalPath := job.OutputPath
+				if err == nil {
+					mismatches, verifyErr := verifyInsert(job.OutputPath, plan)
+					note = verificationNote(mismatches, verifyErr)
+					var finishErr error
+					finalPath, finishErr = finishInsert(job.MkvPath, job.OutputPath, job.Opts, verifyErr == nil && len(mismatches) == 0)
+					if finishErr != nil {
+						note += "\nWarning: " + finishErr.Error()
+					}
+				}
+
+				fyne.Do(func() {
+					if err != nil {
+						failed++
+						job.State = "Error"
+						job.Message = failureReason(output, err)
+						insertResultLabel.SetText(insertResultLabel.Text + fmt.Sprintf("\n[!] %s: %s", filepath.Base(job.MkvPath), job.Message))
+					} else {
+						done++
+						job.State = "Done"
+						notes := []string{note}
+						if len(plan.MissingFonts) > 0 {
+							notes = append(notes, "Fonts not attached: "+strings.Join(plan.MissingFonts, ", "))
+						}
+						for _, warning := range mkvmergeWarnings(output) {
+							notes = append(notes, "Warning: "+warning.String())
+						}
+						job.Message = strings.Join(notes, "\n")
+						insertResultLabel.SetText(insertResultLabel.Text + fmt.Sprintf("\n[x] %s: output: %s", filepath.Base(job.MkvPath), finalPath))
+					}
+					insertResultScroll.ScrollToBottom()
+					refreshMuxQueue()
+				})
+			}
+			fyne.DoAndWait(func() {
+				setInserting(false)
+				stopQueueBtn.Disable()
+				message := trf("Mux queue finished: %d done, %d failed", done, failed)
+				insertResultLabel.SetText(insertResultLabel.Text + "\n\n" + message)
+				a.SendNotification(&fyne.Notification{Title: tr("Mux Queue"), Content: message})
 			})
 		}()
 	})
 
+	clearFinishedBtn := widget.NewButton(tr("Clear Finished"), func() {
+		muxQueue = slices.DeleteFunc(muxQueue, func(job *MuxJob) bool { return job.State == "Done" || job.State == "Error" })
+		refreshMuxQueue()
+	})
+
 	// Create layout for subtitle insertion tab
 	insertTitleLabel := widget.NewLabelWithStyle("Insert Subtitles into MKV", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
 
@@ -3052,7 +4133,7 @@ func main() {
 	mkvDropContainer.Resize(fyne.NewSize(300, 60))
 	
 	srtDropArea := canvas.NewRectangle(color.NRGBA{R: 200, G: 200, B: 200, A: 100})
-	srtDropLabel := widget.NewLabelWithStyle("Drop SRT File Here", fyne.TextAlignCenter, fyne.TextStyle{})
+	srtDropLabel := widget.NewLabelWithStyle("Drop Subtitle Files Here", fyne.TextAlignCenter, fyne.TextStyle{})
 	srtDropContainer := container.NewStack(
 		srtDropArea,
 		srtDropLabel,
@@ -3060,139 +4141,911 @@ func main() {
 	srtDropContainer.Resize(fyne.NewSize(300, 60))
 	
 	// Group file selection
-	fileSelectionGroup := widget.NewCard("File Selection", "", container.NewVBox(
+	fileSelectionGroup := widget.NewCard(tr("File Selection"), "", container.NewVBox(
 		container.NewHBox(selectInsertMkvBtn, insertMkvFileLabel),
 		mkvDropContainer,
-		container.NewHBox(selectInsertSrtBtn, insertSrtFileLabel),
+		selectInsertSrtBtn,
+		insertSubsBox,
 		srtDropContainer,
 	))
 
+	// Group fonts for ASS/SSA subtitles
+	fontsGroup := widget.NewCard(tr("Fonts"), tr("Attached for ASS/SSA subtitles"), container.NewVBox(
+		attachFontBtn,
+		insertFontsBox,
+		fontStatusLabel,
+	))
+
+	// Group chapters muxed with the subtitles
+	chaptersGroup := widget.NewCard(tr("Chapters"), tr("Imported or edited chapters replace those of the MKV"), container.NewVBox(
+		container.NewHBox(importChaptersBtn, addChapterBtn, clearChaptersBtn),
+		insertChaptersBox,
+	))
+
 	// Group subtitle options
-	subtitleOptionsGroup := widget.NewCard("Subtitle Options", "", container.NewVBox(
+	subtitleOptionsGroup := widget.NewCard(tr("Subtitle Options"), tr("Applied to newly added subtitle files"), container.NewVBox(
 		container.NewPadded(
-			container.NewHBox(layout.NewSpacer(), widget.NewLabel("Language:"), layout.NewSpacer(), langDropdown, layout.NewSpacer()),
+			container.NewHBox(layout.NewSpacer(), widget.NewLabel(tr("Language:")), layout.NewSpacer(), langDropdown, layout.NewSpacer()),
 		),
 		container.NewPadded(
-			container.NewHBox(layout.NewSpacer(), widget.NewLabel("Language Code:"), layout.NewSpacer(), customLangDropdown, layout.NewSpacer()),
+			container.NewHBox(layout.NewSpacer(), widget.NewLabel(tr("Language Code:")), layout.NewSpacer(), customLangDropdown, layout.NewSpacer()),
 		),
 		container.NewPadded(
-			container.NewHBox(layout.NewSpacer(), widget.NewLabel("Track Name:"), layout.NewSpacer(), trackNameEntry, layout.NewSpacer()),
+			container.NewHBox(layout.NewSpacer(), widget.NewLabel(tr("Track Name:")), layout.NewSpacer(), trackNameEntry, layout.NewSpacer()),
+		),
+		container.NewPadded(
+			container.NewHBox(layout.NewSpacer(), widget.NewLabel(tr("Timing Offset (ms):")), layout.NewSpacer(), offsetEntry, layout.NewSpacer()),
+		),
+		container.NewPadded(
+			container.NewHBox(layout.NewSpacer(), widget.NewLabel(tr("Character Set:")), layout.NewSpacer(), charsetSelect, layout.NewSpacer()),
 		),
 		container.NewPadded(defaultTrack),
 		container.NewPadded(forcedTrack),
 		container.NewPadded(removeOtherTracks),
+		container.NewPadded(replaceSameLang),
+		container.NewPadded(
+			container.NewHBox(layout.NewSpacer(), widget.NewLabel(tr("New Track Position:")), layout.NewSpacer(), trackPositionSelect, afterTrackEntry, layout.NewSpacer()),
+		),
 	))
 
 	// Group output options
-	outputOptionsGroup := widget.NewCard("Output Options", "", container.NewVBox(
-		container.NewHBox(widget.NewLabel("Output Filename:"), layout.NewSpacer(), outputNameEntry),
-		container.NewHBox(layout.NewSpacer(), insertSubtitleBtn, layout.NewSpacer()),
+	outputOptionsGroup := widget.NewCard(tr("Output Options"), "", container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel(tr("Output Folder:")), container.NewHBox(insertOutputDirBtn, useSourceDirBtn), insertOutputDirLabel),
+		container.NewHBox(widget.NewLabel(tr("Output Filename:")), layout.NewSpacer(), outputNameEntry),
+		keepAttributesCheck,
+		replaceOriginalCheck,
+		container.NewHBox(layout.NewSpacer(), insertSubtitleBtn, addToQueueBtn, batchInsertBtn, layout.NewSpacer()),
+		insertProgressBox,
+	))
+
+	// Group the mux queue
+	muxQueueGroup := widget.NewCard(tr("Mux Queue"), tr("Queued insertions run one after another"), container.NewVBox(
+		container.NewHBox(runQueueBtn, stopQueueBtn, clearFinishedBtn),
+		muxQueueBox,
 	))
 
 	// Results group
-	resultsGroup := widget.NewCard("Results", "", insertResultScroll)
+	resultsGroup := widget.NewCard(tr("Results"), "", container.NewVBox(insertVerifyBox, insertResultScroll))
 
 	// Create layout for subtitle insertion tab
 	insertTabContent := container.NewVBox(
 		insertTitleLabel,
 		fileSelectionGroup,
+		fontsGroup,
+		chaptersGroup,
 		subtitleOptionsGroup,
 		outputOptionsGroup,
+		muxQueueGroup,
 		resultsGroup,
 	)
 
+	// Create tab for editing track properties in place with mkvpropedit,
+	// which takes a moment where a remux takes minutes
+	editMkvFileLabel := widget.NewLabel(tr("No MKV file selected"))
+	editTracksBox := container.NewVBox()
+	editResultLabel := widget.NewLabel("")
+	editResultLabel.Wrapping = fyne.TextWrapWord
+	var editTracks []*EditTrack
+	var loadEditFile func(path string)
+
+	// refreshEditTracks rebuilds the editable rows of the loaded tracks
+	refreshEditTracks := func() {
+		editTracksBox.RemoveAll()
+		if len(editTracks) == 0 {
+			editTracksBox.Add(widget.NewLabel(tr("No tracks loaded")))
+			return
+		}
+		for _, t := range editTracks {
+			langEntry := widget.NewSelectEntry(langCodes)
+			langEntry.SetText(t.Props.Lang)
+			langEntry.OnChanged = func(s string) {
+				t.Props.Lang = strings.TrimSpace(s)
+			}
+			nameEntry := widget.NewEntry()
+			nameEntry.SetPlaceHolder(tr("Track Name:"))
+			nameEntry.SetText(t.Props.Name)
+			nameEntry.OnChanged = func(s string) {
+				t.Props.Name = s
+			}
+			defaultCheck := widget.NewCheck(tr("Default"), nil)
+			defaultCheck.SetChecked(t.Props.Default)
+			defaultCheck.OnChanged = func(checked bool) {
+				t.Props.Default = checked
+			}
+			forcedCheck := widget.NewCheck(tr("Forced"), nil)
+			forcedCheck.SetChecked(t.Props.Forced)
+			forcedCheck.OnChanged = func(checked bool) {
+				t.Props.Forced = checked
+			}
+			flags := container.NewHBox(defaultCheck, forcedCheck)
+			if t.Type == "subtitles" {
+				removeCheck := widget.NewCheck(tr("Remove"), func(checked bool) {
+					t.Remove = checked
+				})
+				removeCheck.SetChecked(t.Remove)
+				flags.Add(removeCheck)
+			}
+			if textSubtitleExt(t.Codec) != "" {
+				// Extract, correct and re-insert the track in one go
+				flags.Add(widget.NewButtonWithIcon(tr("Correct..."), theme.DocumentCreateIcon(), func() {
+					mkvPath := editMkvFileLabel.Text
+					showRoundtripWizard(w, mkvPath, t, func(outputPath string) {
+						editResultLabel.SetText(trf("Corrected file written to %s", outputPath))
+						if outputPath == mkvPath {
+							loadEditFile(mkvPath)
+						}
+					})
+				}))
+			}
+
+			editTracksBox.Add(container.NewBorder(
+				nil,
+				nil,
+				widget.NewLabel(fmt.Sprintf("#%d %s (%s)", t.ID, t.Type, t.Codec)),
+				flags,
+				container.NewGridWithColumns(2, langEntry, nameEntry),
+			))
+		}
+	}
+	refreshEditTracks()
+
+	// loadEditFile loads the tracks of an MKV file into the Edit Tracks tab
+	loadEditFile = func(path string) {
+		editMkvFileLabel.SetText(path)
+		editResultLabel.SetText(tr("Loading tracks..."))
+		go func() {
+			tracks, err := loadEditTracks(path)
+			fyne.Do(func() {
+				if err != nil {
+					editTracks = nil
+					editResultLabel.SetText(err.Error())
+				} else {
+					editTracks = tracks
+					editResultLabel.SetText("")
+				}
+				refreshEditTracks()
+			})
+		}()
+	}
+
+	selectEditMkvBtn := widget.NewButton(tr("Select MKV File"), func() {
+		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			reader.Close()
+			loadEditFile(reader.URI().Path())
+		}, w)
+		fd.SetFilter(storage.NewExtensionFileFilter([]string{".mkv"}))
+		fd.Show()
+	})
+
+	var applyEditBtn *widget.Button
+	applyEditBtn = widget.NewButton(tr("Apply Changes"), func() {
+		mkvPath := editMkvFileLabel.Text
+		args := propEditArgs(mkvPath, editTracks)
+		if args == nil {
+			dialog.ShowInformation(tr("Edit Tracks"), tr("No track properties were changed."), w)
+			return
+		}
+		changed := len(changedTracks(editTracks))
+		applyEditBtn.Disable()
+		editResultLabel.SetText(tr("Writing track properties..."))
+		go func() {
+			output, err := exec.Command("mkvpropedit", args...).CombinedOutput()
+			fyne.Do(func() {
+				applyEditBtn.Enable()
+				if err != nil {
+					editResultLabel.SetText(trf("Error: %v", err) + "\n" + string(output))
+					return
+				}
+				editResultLabel.SetText(trf("Updated %d track(s) of %s in place.", changed, filepath.Base(mkvPath)))
+				// The file now holds the edited properties
+				for _, t := range editTracks {
+					t.Original = t.Props
+				}
+			})
+		}()
+	})
+	applyEditBtn.Importance = widget.HighImportance
+
+	// Progress of the remux that strips subtitle tracks
+	stripProgress := widget.NewProgressBar()
+	stripProgress.Hide()
+
+	// Remux the file without the subtitle tracks checked for removal
+	var stripTracksBtn *widget.Button
+	stripTracksBtn = widget.NewButton(tr("Remove Checked Subtitle Tracks"), func() {
+		mkvPath := editMkvFileLabel.Text
+		outputPath := stripOutputPath(mkvPath)
+		args := stripTracksArgs(mkvPath, outputPath, editTracks)
+		if args == nil {
+			dialog.ShowInformation(tr("Edit Tracks"), tr("Check the Remove box of the subtitle tracks to drop first."), w)
+			return
+		}
+
+		runStrip := func() {
+			stripTracksBtn.Disable()
+			stripProgress.SetValue(0)
+			stripProgress.Show()
+			editResultLabel.SetText(tr("Remuxing without the removed subtitle tracks..."))
+			go func() {
+				start := time.Now()
+				output, err := runWithProgress(exec.Command("mkvmerge", args...), func(percent int) {
+					fyne.Do(func() {
+						stripProgress.SetValue(float64(percent) / 100)
+						editResultLabel.SetText(fmt.Sprintf("%d%%, %s", percent, percentRemaining(start, percent)))
+					})
+				})
+				fyne.Do(func() {
+					stripTracksBtn.Enable()
+					stripProgress.Hide()
+					if err != nil {
+						editResultLabel.SetText(trf("Error: %v", err) + "\n" + string(output))
+						return
+					}
+					editResultLabel.SetText(trf("Cleaned file written to %s", outputPath))
+				})
+			}()
+		}
+
+		if _, err := os.Stat(outputPath); err == nil {
+			dialog.ShowConfirm(tr("File Exists"), trf("%s already exists. Overwrite it?", filepath.Base(outputPath)), func(ok bool) {
+				if ok {
+					runStrip()
+				}
+			}, w)
+			return
+		}
+		runStrip()
+	})
+
+	revertEditBtn := widget.NewButton(tr("Revert"), func() {
+		for _, t := range editTracks {
+			t.Props = t.Original
+			t.Remove = false
+		}
+		refreshEditTracks()
+	})
+
+	editTabContent := container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle(tr("Edit Track Properties"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+			widget.NewLabel(tr("Change the language, name and flags of the tracks without remuxing the file.")),
+			container.NewBorder(nil, nil, selectEditMkvBtn, nil, editMkvFileLabel),
+		),
+		container.NewVBox(
+			container.NewHBox(layout.NewSpacer(), revertEditBtn, stripTracksBtn, applyEditBtn),
+			stripProgress,
+			editResultLabel,
+		),
+		nil,
+		nil,
+		container.NewVScroll(editTracksBox),
+	)
+
+	// handleEditDrop loads a dropped MKV file into the Edit Tracks tab
+	handleEditDrop := func(pos fyne.Position, uris []fyne.URI) {
+		for _, uri := range uris {
+			if strings.EqualFold(filepath.Ext(uri.Path()), ".mkv") {
+				loadEditFile(uri.Path())
+				return
+			}
+		}
+		dialog.ShowInformation(tr("Invalid File"), tr("Please select an MKV file"), w)
+	}
+
 	// Create settings tab content
-	settingsLabel := widget.NewLabel("System Dependency Check:\n")
+	settingsLabel := widget.NewLabel(tr("System Dependency Check:") + "\n")
 	settingsLabel.Wrapping = fyne.TextWrapWord
 
+	// Output filename template used for extracted and converted subtitles
+	filenameTemplateEntry := widget.NewEntry()
+	filenameTemplateEntry.SetText(filenameTemplate())
+	filenameTemplateEntry.OnChanged = func(s string) {
+		if strings.TrimSpace(s) == "" {
+			a.Preferences().RemoveValue("filename_template")
+		} else {
+			a.Preferences().SetString("filename_template", s)
+		}
+		trackTable.Refresh()
+	}
+	resetTemplateBtn := widget.NewButton(tr("Reset"), func() {
+		filenameTemplateEntry.SetText(defaultFilenameTemplate)
+	})
+	filenameTemplateGroup := widget.NewCard(tr("Output Filenames"), tr("Tokens: ")+filenameTemplateTokens, container.NewBorder(
+		nil,
+		nil,
+		widget.NewLabel(tr("Template:")),
+		resetTemplateBtn,
+		filenameTemplateEntry,
+	))
+
+	// Default conversion of each codec, applied when tracks are loaded
+	conversionCheck := func(label string, prefKey string) *widget.Check {
+		check := widget.NewCheck(label, func(checked bool) {
+			a.Preferences().SetBool(prefKey, checked)
+		})
+		check.SetChecked(a.Preferences().BoolWithFallback(prefKey, true))
+		return check
+	}
+	conversionGroup := widget.NewCard(tr("Default Conversions"), tr("Applied to the 'Convert' option of newly loaded tracks"), container.NewVBox(
+		conversionCheck(tr("Convert PGS subtitles to SRT (OCR)"), "convert_pgs"),
+		conversionCheck(tr("Convert VobSub subtitles to SRT (OCR)"), "convert_vobsub"),
+		conversionCheck(tr("Convert DVB subtitles to SRT (OCR)"), "convert_dvbsub"),
+		conversionCheck(tr("Convert ASS/SSA subtitles to SRT (uncheck to keep the original ASS)"), "convert_ass"),
+		conversionCheck(tr("Tell dialogue from signs & songs tracks of the same language when tracks are loaded (reads the whole file)"), "detect_track_content"),
+	))
+
+	// Number of tracks of a file extracted at the same time, applied to the next run
+	parallelSlider := func(prefKey string, maxValue float64) fyne.CanvasObject {
+		value := widget.NewLabel("")
+		slider := widget.NewSlider(1, maxValue)
+		slider.OnChanged = func(v float64) {
+			value.SetText(fmt.Sprintf("%d", int(v)))
+			a.Preferences().SetInt(prefKey, int(v))
+		}
+		slider.SetValue(float64(a.Preferences().IntWithFallback(prefKey, 1)))
+		value.SetText(fmt.Sprintf("%d", int(slider.Value)))
+		return container.NewBorder(nil, nil, nil, value, slider)
+	}
+	concurrencyGroup := widget.NewCard(tr("Parallel Extraction"), tr("Tracks of the same file extracted at the same time; OCR conversions are limited separately"), container.New(layout.NewFormLayout(),
+		widget.NewLabel(tr("Parallel tracks:")), parallelSlider("parallel_tracks", 8),
+		widget.NewLabel(tr("Parallel OCR jobs:")), parallelSlider("parallel_ocr", 4),
+	))
+
+	// Tesseract languages: those installed with it and those downloaded into the app data dir
+	ocrLanguagesLabel := widget.NewLabel(tr("Checking installed languages..."))
+	ocrLanguagesLabel.Wrapping = fyne.TextWrapWord
+	refreshOCRLanguages := func() {
+		go func() {
+			system, err := systemTesseractLanguages(context.Background())
+			downloaded := downloadedTesseractLanguages(OCRQualityFast)
+			best := downloadedTesseractLanguages(OCRQualityBest)
+			fyne.Do(func() {
+				text := trf("In the tessdata folder: %s", strings.Join(downloaded, ", "))
+				if len(best) > 0 {
+					text += "\n" + trf("Best models: %s", strings.Join(best, ", "))
+				}
+				if err != nil {
+					text = err.Error() + "\n" + text
+				} else {
+					text = trf("Installed with Tesseract: %s", strings.Join(system, ", ")) + "\n" + text
+				}
+				ocrLanguagesLabel.SetText(text)
+			})
+		}()
+	}
+	tessdataDirLabel := widget.NewLabel("")
+	tessdataDirLabel.Truncation = fyne.TextTruncateEllipsis
+	var defaultTessdataDirBtn *widget.Button
+	setTessdataDir := func(dir string) {
+		a.Preferences().SetString("tessdata_dir", dir)
+		if dir == "" {
+			tessdataDirLabel.SetText(tr("Default (app data folder)"))
+			defaultTessdataDirBtn.Disable()
+		} else {
+			tessdataDirLabel.SetText(dir)
+			defaultTessdataDirBtn.Enable()
+		}
+		refreshOCRLanguages()
+	}
+	tessdataDirBtn := widget.NewButton(tr("Choose..."), func() {
+		fd := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil || uri == nil {
+				return
+			}
+			setTessdataDir(uri.Path())
+		}, w)
+		if dir := listableDir(tessdataDir(OCRQualityFast)); dir != nil {
+			fd.SetLocation(dir)
+		}
+		fd.Show()
+	})
+	defaultTessdataDirBtn = widget.NewButton(tr("Default"), func() {
+		setTessdataDir("")
+	})
+	ocrLanguageEntry := widget.NewEntry()
+	ocrLanguageEntry.SetPlaceHolder(tr("Language code, e.g. fra or chi_sim"))
+	ocrDownloadProgress := widget.NewProgressBar()
+	ocrDownloadProgress.Hide()
+	var ocrDownloadBtn *widget.Button
+	ocrDownloadBtn = widget.NewButton(tr("Download"), func() {
+		if strings.TrimSpace(ocrLanguageEntry.Text) == "" {
+			return
+		}
+		lang := tesseractLanguage(ocrLanguageEntry.Text)
+		ocrDownloadBtn.Disable()
+		ocrDownloadProgress.SetValue(0)
+		ocrDownloadProgress.Show()
+		go func() {
+			quality := a.Preferences().StringWithFallback("ocr_quality", OCRQualityFast)
+			err := downloadTessdata(context.Background(), lang, quality, func(done, total int64) {
+				if total > 0 {
+					fyne.Do(func() {
+						ocrDownloadProgress.SetValue(float64(done) / float64(total))
+					})
+				}
+			})
+			fyne.Do(func() {
+				ocrDownloadBtn.Enable()
+				ocrDownloadProgress.Hide()
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				ocrLanguageEntry.SetText("")
+				refreshOCRLanguages()
+			})
+		}()
+	})
+	// OCR results cached per subtitle content and settings
+	ocrCacheLabel := widget.NewLabel("")
+	refreshOCRCache := func() {
+		count, size := ocrCacheSize()
+		ocrCacheLabel.SetText(trf("Cached OCR results: %d (%s)", count, formatSize(size)))
+	}
+	refreshOCRCache()
+	ocrCacheClearBtn := widget.NewButton(tr("Clear OCR Cache"), func() {
+		if err := clearOCRCache(); err != nil {
+			dialog.ShowError(err, w)
+		}
+		refreshOCRCache()
+	})
+
+	// Characters OCR may read, per Tesseract language
+	ocrCharsetLangEntry := widget.NewEntry()
+	ocrCharsetLangEntry.SetPlaceHolder(tr("Language code, e.g. eng"))
+	ocrBlacklistEntry := widget.NewEntry()
+	ocrBlacklistEntry.SetPlaceHolder(tr("None"))
+	ocrWhitelistEntry := widget.NewEntry()
+	ocrWhitelistEntry.SetPlaceHolder(tr("Any character"))
+	loadingOCRCharset := false
+	showOCRCharset := func(lang string) {
+		loadingOCRCharset = true
+		defer func() { loadingOCRCharset = false }()
+		charset := languageOCRCharset(tesseractLanguage(lang))
+		ocrBlacklistEntry.SetText(charset.Blacklist)
+		ocrWhitelistEntry.SetText(charset.Whitelist)
+	}
+	ocrCharsetLangEntry.OnChanged = showOCRCharset
+	ocrBlacklistEntry.OnChanged = func(text string) {
+		if !loadingOCRCharset {
+			a.Preferences().SetString(ocrBlacklistPref(tesseractLanguage(ocrCharsetLangEntry.Text)), text)
+		}
+	}
+	ocrWhitelistEntry.OnChanged = func(text string) {
+		if !loadingOCRCharset {
+			a.Preferences().SetString(ocrWhitelistPref(tesseractLanguage(ocrCharsetLangEntry.Text)), text)
+		}
+	}
+	showOCRCharset("")
+
+	ocrLanguagesGroup := widget.NewCard(tr("OCR"), tr("Missing languages are downloaded from tessdata_fast or tessdata_best when a conversion needs them"), container.NewVBox(
+		conversionCheck(tr("Review OCR results before the SRT is written"), "ocr_review"),
+		conversionCheck(tr("Fix common OCR errors with a spell checker (Hunspell)"), "ocr_correct"),
+		conversionCheck(tr("Keep italics: tag lines of slanted text with <i>"), "ocr_italics"),
+		conversionCheck(tr("Keep positions: tag subtitles at the top of the screen with {\\an8}"), "ocr_positions"),
+		conversionCheck(tr("Save the subtitles recognized so far when an OCR conversion is cancelled"), "ocr_save_partial"),
+		conversionCheck(tr("Reuse the OCR results of subtitles converted before"), "ocr_cache"),
+		container.NewBorder(nil, nil, nil, ocrCacheClearBtn, ocrCacheLabel),
+		container.NewBorder(nil, nil, widget.NewLabel(tr("Tessdata folder:")), container.NewHBox(tessdataDirBtn, defaultTessdataDirBtn), tessdataDirLabel),
+		ocrLanguagesLabel,
+		container.NewBorder(nil, nil, nil, ocrDownloadBtn, ocrLanguageEntry),
+		ocrDownloadProgress,
+		widget.NewLabel(tr("Characters to read per language, e.g. never | or ~ in Latin scripts:")),
+		container.New(layout.NewFormLayout(),
+			widget.NewLabel(tr("Language:")), ocrCharsetLangEntry,
+			widget.NewLabel(tr("Never read:")), ocrBlacklistEntry,
+			widget.NewLabel(tr("Only read:")), ocrWhitelistEntry,
+		),
+	))
+	setTessdataDir(customTessdataDir())
+
+	// Preparation of subtitle bitmaps for OCR, applied to the next run
+	ocrScales := []string{"1x", "2x", "3x", "4x"}
+	ocrScaleSelect := widget.NewSelect(ocrScales, func(selected string) {
+		if scale, err := strconv.Atoi(strings.TrimSuffix(selected, "x")); err == nil {
+			a.Preferences().SetInt("ocr_scale", scale)
+		}
+	})
+	ocrScaleSelect.SetSelected(fmt.Sprintf("%dx", a.Preferences().IntWithFallback("ocr_scale", defaultOCRPreprocess.Scale)))
+	ocrIntSlider := func(prefKey string, minValue, maxValue float64, fallback int) (*widget.Slider, fyne.CanvasObject) {
+		value := widget.NewLabel("")
+		slider := widget.NewSlider(minValue, maxValue)
+		slider.OnChanged = func(v float64) {
+			value.SetText(fmt.Sprintf("%d", int(v)))
+			a.Preferences().SetInt(prefKey, int(v))
+		}
+		slider.SetValue(float64(a.Preferences().IntWithFallback(prefKey, fallback)))
+		value.SetText(fmt.Sprintf("%d", int(slider.Value)))
+		return slider, container.NewBorder(nil, nil, nil, value, slider)
+	}
+	ocrThresholdSlider, ocrThresholdRow := ocrIntSlider("ocr_threshold", 0, 255, defaultOCRPreprocess.Threshold)
+	ocrPaddingSlider, ocrPaddingRow := ocrIntSlider("ocr_padding", 0, 50, defaultOCRPreprocess.Padding)
+	ocrInvertCheck := widget.NewCheck(tr("Invert: the text is darker than its outline"), func(checked bool) {
+		a.Preferences().SetBool("ocr_invert", checked)
+	})
+	ocrInvertCheck.SetChecked(a.Preferences().BoolWithFallback("ocr_invert", defaultOCRPreprocess.Invert))
+	ocrPreprocessDefaultsBtn := widget.NewButton(tr("Restore Defaults"), func() {
+		ocrScaleSelect.SetSelected(fmt.Sprintf("%dx", defaultOCRPreprocess.Scale))
+		ocrThresholdSlider.SetValue(float64(defaultOCRPreprocess.Threshold))
+		ocrPaddingSlider.SetValue(float64(defaultOCRPreprocess.Padding))
+		ocrInvertCheck.SetChecked(defaultOCRPreprocess.Invert)
+	})
+	ocrPreprocessGroup := widget.NewCard(tr("OCR Image Preprocessing"), tr("Lower the threshold for low-contrast or yellow subtitles, enlarge small DVD subtitles"), container.NewVBox(
+		container.New(layout.NewFormLayout(),
+			widget.NewLabel(tr("Scale:")), ocrScaleSelect,
+			widget.NewLabel(tr("Brightness threshold:")), ocrThresholdRow,
+			widget.NewLabel(tr("Border padding:")), ocrPaddingRow,
+		),
+		ocrInvertCheck,
+		container.NewHBox(ocrPreprocessDefaultsBtn),
+	))
+
+	// Engines low confidence subtitles are retried on, in the order of ocrEngines
+	ocrFallbackChecks := widget.NewCheckGroup(ocrEngineNames(), func(selected []string) {
+		var chain []string
+		for _, name := range ocrEngineNames() {
+			if slices.Contains(selected, name) {
+				chain = append(chain, name)
+			}
+		}
+		a.Preferences().SetStringList("ocr_fallback_engines", chain)
+	})
+	ocrFallbackChecks.Horizontal = true
+	ocrFallbackChecks.SetSelected(a.Preferences().StringList("ocr_fallback_engines"))
+	_, ocrFallbackThresholdRow := ocrIntSlider("ocr_fallback_threshold", 0, 100, lowConfidenceThreshold)
+	ocrFallbackGroup := widget.NewCard(tr("OCR Engine Fallback"), tr("Subtitles read with low confidence are read again by the next engine checked; the text read with the highest confidence is kept"), container.New(layout.NewFormLayout(),
+		widget.NewLabel(tr("Retry with:")), ocrFallbackChecks,
+		widget.NewLabel(tr("Below confidence:")), ocrFallbackThresholdRow,
+	))
+
+	// API keys of the cloud OCR engines, which can be selected per track
+	cloudOCREntry := func(prefKey string, password bool) *widget.Entry {
+		entry := widget.NewEntry()
+		if password {
+			entry = widget.NewPasswordEntry()
+		}
+		entry.SetText(a.Preferences().String(prefKey))
+		entry.OnChanged = func(text string) {
+			a.Preferences().SetString(prefKey, strings.TrimSpace(text))
+		}
+		return entry
+	}
+	azureEndpointEntry := cloudOCREntry("azure_read_endpoint", false)
+	azureEndpointEntry.SetPlaceHolder("https://<resource>.cognitiveservices.azure.com")
+	cloudOCRGroup := widget.NewCard(tr("Cloud OCR"), tr("Google Vision and Azure Read are selected per track in the OCR Engine column; the subtitle bitmaps are sent to the provider, which may charge for them"), container.New(layout.NewFormLayout(),
+		widget.NewLabel(tr("Google Vision API key:")), cloudOCREntry("google_vision_key", true),
+		widget.NewLabel(tr("Azure Read endpoint:")), azureEndpointEntry,
+		widget.NewLabel(tr("Azure Read key:")), cloudOCREntry("azure_read_key", true),
+	))
+
+	// OCR replace list, applied to every OCR result and extended by the review
+	ocrRules := loadOCRRules(a.Preferences())
+	selectedOCRRule := -1
+	ocrRuleList := widget.NewList(
+		func() int {
+			return len(ocrRules)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if id < len(ocrRules) {
+				o.(*widget.Label).SetText(ocrRules[id].String())
+			}
+		},
+	)
+	ocrRuleList.OnSelected = func(id widget.ListItemID) {
+		selectedOCRRule = id
+	}
+	refreshOCRRules := func() {
+		ocrRules = loadOCRRules(a.Preferences())
+		selectedOCRRule = -1
+		ocrRuleList.UnselectAll()
+		ocrRuleList.Refresh()
+	}
+	ocrRuleFindEntry := widget.NewEntry()
+	ocrRuleFindEntry.SetPlaceHolder(tr("Find"))
+	ocrRuleReplaceEntry := widget.NewEntry()
+	ocrRuleReplaceEntry.SetPlaceHolder(tr("Replace with"))
+	ocrRuleLangEntry := widget.NewEntry()
+	ocrRuleLangEntry.SetPlaceHolder(tr("All languages"))
+	ocrRuleRegexCheck := widget.NewCheck(tr("Regular expression"), nil)
+	ocrRuleAddBtn := widget.NewButton(tr("Add Rule"), func() {
+		rule := OCRRule{Find: ocrRuleFindEntry.Text, Replace: ocrRuleReplaceEntry.Text, Regex: ocrRuleRegexCheck.Checked}
+		if rule.Find == "" {
+			return
+		}
+		if lang := strings.TrimSpace(ocrRuleLangEntry.Text); lang != "" {
+			rule.Lang = tesseractLanguage(lang)
+		}
+		if rule.Regex {
+			if _, err := regexp.Compile(rule.Find); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+		}
+		saveOCRRules(a.Preferences(), append(loadOCRRules(a.Preferences()), rule))
+		ocrRuleFindEntry.SetText("")
+		ocrRuleReplaceEntry.SetText("")
+		refreshOCRRules()
+	})
+	ocrRuleRemoveBtn := widget.NewButton(tr("Remove Rule"), func() {
+		if selectedOCRRule < 0 || selectedOCRRule >= len(ocrRules) {
+			return
+		}
+		saveOCRRules(a.Preferences(), slices.Delete(ocrRules, selectedOCRRule, selectedOCRRule+1))
+		refreshOCRRules()
+	})
+	ocrRuleScroll := container.NewVScroll(ocrRuleList)
+	ocrRuleScroll.SetMinSize(fyne.NewSize(0, 150))
+	ocrRulesGroup := widget.NewCard(tr("OCR Replace List"), tr("Fixes applied to every OCR result; whole words unless the rule is a regular expression"), container.NewVBox(
+		ocrRuleScroll,
+		container.New(layout.NewFormLayout(),
+			widget.NewLabel(tr("Find:")), ocrRuleFindEntry,
+			widget.NewLabel(tr("Replace with:")), ocrRuleReplaceEntry,
+			widget.NewLabel(tr("Language:")), ocrRuleLangEntry,
+		),
+		ocrRuleRegexCheck,
+		container.NewHBox(ocrRuleAddBtn, ocrRuleRemoveBtn),
+		conversionCheck(tr("Learn rules from the words corrected in the OCR review"), "ocr_learn_rules"),
+	))
+
+	// Theme and UI scale, applied right away
+	themeVariants := []string{ThemeSystem, ThemeLight, ThemeDark}
+	themeNames := []string{tr(ThemeSystem), tr(ThemeLight), tr(ThemeDark)}
+	themeSelect := widget.NewRadioGroup(themeNames, func(selected string) {
+		for i, name := range themeNames {
+			if name == selected {
+				a.Preferences().SetString("theme_variant", themeVariants[i])
+			}
+		}
+		applyTheme(a)
+	})
+	themeSelect.Horizontal = true
+	for i, variant := range themeVariants {
+		if variant == a.Preferences().StringWithFallback("theme_variant", ThemeSystem) {
+			themeSelect.SetSelected(themeNames[i])
+		}
+	}
+
+	// Interface language, applied on the next start
+	var languageNames []string
+	for _, l := range uiLanguages {
+		languageNames = append(languageNames, tr(l.Name))
+	}
+	languageSelect := widget.NewSelect(languageNames, nil)
+	for i, l := range uiLanguages {
+		if l.Code == a.Preferences().String("ui_language") {
+			languageSelect.SetSelected(languageNames[i])
+		}
+	}
+	languageSelect.OnChanged = func(selected string) {
+		for i, name := range languageNames {
+			if name == selected {
+				a.Preferences().SetString("ui_language", uiLanguages[i].Code)
+			}
+		}
+		dialog.ShowInformation(tr("Language"), tr("Restart Subtitle Forge to apply the new language."), w)
+	}
+	scaleSelect := widget.NewSelect(uiScales, func(selected string) {
+		percent, err := strconv.Atoi(strings.TrimSuffix(selected, "%"))
+		if err != nil {
+			return
+		}
+		a.Preferences().SetFloat("ui_scale", float64(percent)/100)
+		applyTheme(a)
+	})
+	scaleSelect.SetSelected(fmt.Sprintf("%d%%", int(a.Preferences().FloatWithFallback("ui_scale", 1)*100+0.5)))
+	appearanceGroup := widget.NewCard(tr("Appearance"), "", container.NewVBox(
+		container.NewHBox(widget.NewLabel(tr("Theme:")), themeSelect),
+		container.NewHBox(widget.NewLabel(tr("UI scale:")), scaleSelect),
+		container.NewHBox(widget.NewLabel(tr("Language:")), languageSelect),
+	))
+
 	settingsTabContent := container.NewVBox(
-		widget.NewLabel("Settings"),
+		widget.NewLabel(tr("Settings")),
+		appearanceGroup,
+		filenameTemplateGroup,
+		conversionGroup,
+		ocrLanguagesGroup,
+		ocrPreprocessGroup,
+		ocrFallbackGroup,
+		cloudOCRGroup,
+		ocrRulesGroup,
+		concurrencyGroup,
 		settingsLabel,
 		dependencyButtons,
 	)
 	updateDependencyStatus(w)
 
+	// History tab: finished jobs, newest first, that can be run again
+	historyList := container.NewVBox()
+	var tabs *container.AppTabs
+	rerunJob := func(entry HistoryEntry) {
+		if running {
+			dialog.ShowError(errors.New(tr("An extraction is already running.")), w)
+			return
+		}
+		if _, err := os.Stat(entry.Path); err != nil {
+			dialog.ShowError(errors.New(trf("File no longer exists: %s", entry.Path)), w)
+			return
+		}
+		items, err := loadSubtitleTracks(entry.Path)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if entry.applyTo(items) == 0 {
+			dialog.ShowError(errors.New(tr("None of the tracks of this job were found in the file.")), w)
+			return
+		}
+
+		// Show the file in the Extract tab to follow the progress
+		item := findQueueItem(queue, entry.Path)
+		if item == nil {
+			item = &QueueItem{Path: entry.Path, State: "Pending"}
+			queue = append(queue, item)
+		}
+		item.Tracks = items
+		showQueueItem(item)
+		tabs.SelectIndex(0)
+
+		runExtraction(entry.Path, entry.OutDir, items)
+	}
+	refreshHistory = func() {
+		historyList.Objects = nil
+		history := loadHistory(a.Preferences())
+		if len(history) == 0 {
+			historyList.Add(widget.NewLabel(tr("No finished extractions yet.")))
+		}
+		for _, entry := range history {
+			entry := entry
+			historyList.Add(container.NewHBox(
+				widget.NewLabel(entry.Time.Format("2006-01-02 15:04")),
+				widget.NewLabel(filepath.Base(entry.Path)),
+				widget.NewLabel(trf("%d of %d tracks extracted", entry.doneCount(), len(entry.Tracks))),
+				layout.NewSpacer(),
+				widget.NewButton(tr("Summary"), func() {
+					var b strings.Builder
+					b.WriteString(entry.Path + "\n" + trf("Output directory: %s", entry.OutDir) + "\n\n")
+					for _, t := range entry.Tracks {
+						b.WriteString(trf("  Track %d (%s, %s): %s", t.Num, t.Lang, t.Codec, tr(t.State)))
+						if t.OutputPath != "" {
+							b.WriteString(" → " + filepath.Base(t.OutputPath))
+						}
+						b.WriteString("\n")
+					}
+					summaryLabel := widget.NewLabel(b.String())
+					summaryLabel.TextStyle = fyne.TextStyle{Monospace: true}
+					scroll := container.NewScroll(summaryLabel)
+					scroll.SetMinSize(fyne.NewSize(500, 300))
+					dialog.ShowCustom(tr("Summary"), tr("Close"), scroll, w)
+				}),
+				widget.NewButton(tr("Open Output Folder"), func() {
+					if err := openFolder(entry.OutDir); err != nil {
+						dialog.ShowError(err, w)
+					}
+				}),
+				widget.NewButton(tr("Re-run"), func() {
+					rerunJob(entry)
+				}),
+			))
+		}
+		historyList.Refresh()
+	}
+	refreshHistory()
+	clearHistoryBtn := widget.NewButton(tr("Clear History"), func() {
+		dialog.ShowConfirm(tr("Clear History"), tr("Remove all finished jobs from the history?"), func(ok bool) {
+			if ok {
+				saveHistory(a.Preferences(), nil)
+				refreshHistory()
+			}
+		}, w)
+	})
+	historyTabContent := container.NewBorder(
+		container.NewHBox(widget.NewLabel(tr("Finished extractions:")), layout.NewSpacer(), clearHistoryBtn),
+		nil,
+		nil,
+		nil,
+		container.NewScroll(historyList),
+	)
+
 	// Create tabs
-	tabs := container.NewAppTabs(
-		container.NewTabItem("Extract Subtitles", extractTabContent),
-		container.NewTabItem("Insert Subtitles", insertTabContent),
-		container.NewTabItem("Settings", settingsTabContent),
+	tabs = container.NewAppTabs(
+		container.NewTabItem(tr("Extract Subtitles"), extractTabContent),
+		container.NewTabItem(tr("Insert Subtitles"), insertTabContent),
+		container.NewTabItem(tr("Edit Tracks"), editTabContent),
+		container.NewTabItem(tr("History"), historyTabContent),
+		container.NewTabItem(tr("Settings"), settingsTabContent),
 	)
 	tabs.SetTabLocation(container.TabLocationTop)
+	tabs.OnSelected = func(item *container.TabItem) {
+		// Conversions fill the OCR cache and the replace list while the Settings tab is not shown
+		if item.Content == settingsTabContent {
+			refreshOCRCache()
+			refreshOCRRules()
+		}
+	}
 
-	// Set up tab change handler for drag and drop
-	tabs.OnChanged = func(tab *container.TabItem) {
-		if tab.Text == "Insert Subtitles" {
-			// Set up drag and drop for Insert Subtitles tab
-			w.SetOnDropped(func(pos fyne.Position, uris []fyne.URI) {
-				if len(uris) > 0 {
-					filePath := uris[0].Path()
-					fileExt := strings.ToLower(filepath.Ext(filePath))
-					
-					if fileExt == ".mkv" {
-						// Handle MKV file drop
-						insertMkvFileLabel.SetText(filePath)
-						mkvDropLabel.SetText(filepath.Base(filePath))
-						mkvDropArea.FillColor = color.NRGBA{R: 100, G: 200, B: 100, A: 100}
-						mkvDropArea.Refresh()
-						a.SendNotification(&fyne.Notification{
-							Title:   "File Dropped",
-							Content: "MKV file loaded: " + filepath.Base(filePath),
-						})
-					} else if fileExt == ".srt" {
-						// Handle SRT file drop
-						insertSrtFileLabel.SetText(filePath)
-						srtDropLabel.SetText(filepath.Base(filePath))
-						srtDropArea.FillColor = color.NRGBA{R: 100, G: 200, B: 100, A: 100}
-						srtDropArea.Refresh()
-						a.SendNotification(&fyne.Notification{
-							Title:   "File Dropped",
-							Content: "SRT file loaded: " + filepath.Base(filePath),
-						})
-					} else {
-						a.SendNotification(&fyne.Notification{
-							Title:   "Invalid File",
-							Content: "Please drop an MKV or SRT file only.",
-						})
-					}
+	// handleInsertDrop fills the MKV slot of the Insert tab and adds the
+	// subtitle files from the dropped files, so an MKV and its subtitles can be
+	// dropped together
+	handleInsertDrop := func(pos fyne.Position, uris []fyne.URI) {
+		var mkvFile string
+		var subFiles []string
+		fonts, ignored := 0, 0
+		for _, uri := range uris {
+			switch strings.ToLower(filepath.Ext(uri.Path())) {
+			case ".mkv":
+				if mkvFile != "" {
+					ignored++
+					continue
 				}
-			})
-		} else if tab.Text == "Extract Subtitles" {
-			// Restore original drag and drop for Extract Subtitles tab
-			w.SetOnDropped(func(pos fyne.Position, uris []fyne.URI) {
-				if len(uris) > 0 {
-					filePath := uris[0].Path()
-					fileExt := strings.ToLower(filepath.Ext(filePath))
-					
-					if fileExt == ".mkv" {
-						// Handle MKV file drop
-						mkvPath = filePath
-						a.SendNotification(&fyne.Notification{
-							Title:   "File Dropped",
-							Content: "MKV file loaded: " + filepath.Base(filePath),
-						})
-						
-						// Update UI
-						selectedFile.SetText(mkvPath)
-						
-						// Set output directory to the same directory as the MKV file
-						outDir = filepath.Dir(mkvPath)
-						selectedDir.SetText(outDir)
-						
-						// Clear previous tracks
-						trackItems = []*TrackItem{}
-						trackList.Objects = nil
-						trackList.Refresh()
-						
-						result.SetText("MKV file dropped and loaded. Output directory automatically set to MKV location. Click 'Load Tracks' to analyze the MKV file.")
-					} else {
-						a.SendNotification(&fyne.Notification{
-							Title:   "Invalid File",
-							Content: "Please drop an MKV file only.",
-						})
-					}
+				mkvFile = uri.Path()
+			case ".ttf", ".otf", ".ttc":
+				addInsertFont(uri.Path())
+				fonts++
+			default:
+				if !isInsertSubtitle(uri.Path()) {
+					ignored++
+					continue
 				}
+				subFiles = append(subFiles, uri.Path())
+			}
+		}
+
+		if mkvFile == "" && len(subFiles) == 0 && fonts == 0 {
+			a.SendNotification(&fyne.Notification{
+				Title:   tr("Invalid File"),
+				Content: tr("Please drop an MKV or subtitle file only."),
 			})
+			return
+		}
+
+		if mkvFile != "" {
+			insertMkvFileLabel.SetText(mkvFile)
+			mkvDropLabel.SetText(filepath.Base(mkvFile))
+			mkvDropArea.FillColor = color.NRGBA{R: 100, G: 200, B: 100, A: 100}
+			mkvDropArea.Refresh()
+			a.SendNotification(&fyne.Notification{
+				Title:   tr("File Dropped"),
+				Content: tr("MKV file loaded: ") + filepath.Base(mkvFile),
+			})
+		}
+		if len(subFiles) > 0 {
+			for _, subFile := range subFiles {
+				addInsertSubtitle(subFile)
+			}
+			srtDropLabel.SetText(trf("%d subtitle file(s) added", len(insertSubs)))
+			srtDropArea.FillColor = color.NRGBA{R: 100, G: 200, B: 100, A: 100}
+			srtDropArea.Refresh()
+			a.SendNotification(&fyne.Notification{
+				Title:   tr("File Dropped"),
+				Content: trf("%d subtitle file(s) added", len(subFiles)),
+			})
+		}
+		if ignored > 0 {
+			dialog.ShowInformation(tr("Files Dropped"), trf("Only one MKV file can be inserted into at a time; %d other file(s) were ignored.", ignored), w)
+		}
+	}
+
+	// Set up tab change handler for drag and drop
+	tabs.OnChanged = func(tab *container.TabItem) {
+		if tab.Text == tr("Insert Subtitles") {
+			// Set up drag and drop for Insert Subtitles tab
+			w.SetOnDropped(handleInsertDrop)
+		} else if tab.Text == tr("Extract Subtitles") {
+			// Restore queue drag and drop for Extract Subtitles tab
+			w.SetOnDropped(handleExtractDrop)
+		} else if tab.Text == tr("Edit Tracks") {
+			w.SetOnDropped(handleEditDrop)
 		}
 	}
 
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// runSummary describes the outcome of every checked track of the processed files
+func runSummary(items []*QueueItem) string {
+	var b strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(trf("%s: %d of %d tracks extracted", filepath.Base(item.Path), item.doneCount(), item.selectedCount()))
+		b.WriteString("\n")
+		for _, t := range item.Tracks {
+			if !t.Check.Checked {
+				continue
+			}
+			state := t.State
+			if state == "" {
+				state = "Pending"
+			}
+			b.WriteString(trf("  Track %d (%s, %s): %s", t.Num, t.Lang, t.Codec, tr(state)))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// failureReason returns why a track failed: the error and the last line the
+// tool printed, which usually names the problem
+func failureReason(output []byte, err error) string {
+	reason := err.Error()
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		reason += ": " + last
+	}
+	return reason
+}
+
+// failureSummary lists the failed tracks of the processed files with their reasons
+func failureSummary(items []*QueueItem) string {
+	var b strings.Builder
+	for _, item := range items {
+		for _, t := range item.failedTracks() {
+			reason := t.Error
+			if reason == "" {
+				reason = tr("See the log for details.")
+			}
+			b.WriteString(trf("%s, track %d (%s, %s): %s", filepath.Base(item.Path), t.Num, t.Lang, t.Codec, reason))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// selectFailedTracks leaves only the failed tracks checked, to retry them,
+// and returns the files that have any
+func selectFailedTracks(items []*QueueItem) []*QueueItem {
+	var retry []*QueueItem
+	for _, item := range items {
+		failed := item.failedTracks()
+		if len(failed) == 0 {
+			continue
+		}
+		for _, t := range item.Tracks {
+			t.Check.SetChecked(false)
+		}
+		for _, t := range failed {
+			t.Check.SetChecked(true)
+		}
+		retry = append(retry, item)
+	}
+	return retry
+}
+
+// showRunComplete sends a system notification for a finished run, so users
+// who switched away know it is done, and offers to open the output folder
+// or read the per-track summary. Failed tracks are listed with their reasons
+// and can be retried with onRetry.
+func showRunComplete(w fyne.Window, message string, outDir string, items []*QueueItem, onRetry func(retry []*QueueItem)) {
+	summary := runSummary(items)
+	fyne.CurrentApp().SendNotification(&fyne.Notification{
+		Title:   "Subtitle Forge",
+		Content: message,
+	})
+
+	var d *dialog.CustomDialog
+	openBtn := widget.NewButton(tr("Open output folder"), func() {
+		if err := openFolder(outDir); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	summaryBtn := widget.NewButton(tr("Show summary"), func() {
+		summaryLabel := widget.NewLabel(summary)
+		summaryLabel.TextStyle = fyne.TextStyle{Monospace: true}
+		scroll := container.NewScroll(summaryLabel)
+		scroll.SetMinSize(fyne.NewSize(500, 300))
+		dialog.ShowCustom(tr("Summary"), tr("Close"), scroll, w)
+	})
+	closeBtn := widget.NewButton(tr("Close"), func() {
+		d.Hide()
+	})
+
+	failures := failureSummary(items)
+	if failures == "" {
+		d = dialog.NewCustomWithoutButtons(tr("Run complete"), widget.NewLabel(message), w)
+		d.SetButtons([]fyne.CanvasObject{closeBtn, summaryBtn, openBtn})
+		d.Show()
+		return
+	}
+
+	failuresLabel := widget.NewLabel(failures)
+	failuresLabel.Wrapping = fyne.TextWrapWord
+	failuresScroll := container.NewScroll(failuresLabel)
+	failuresScroll.SetMinSize(fyne.NewSize(500, 150))
+	retryBtn := widget.NewButton(tr("Retry failed"), func() {
+		d.Hide()
+		onRetry(selectFailedTracks(items))
+	})
+	retryBtn.Importance = widget.HighImportance
+
+	d = dialog.NewCustomWithoutButtons(tr("Run complete"), container.NewBorder(
+		container.NewVBox(widget.NewLabel(message), widget.NewLabel(tr("Failed tracks:"))),
+		nil,
+		nil,
+		nil,
+		failuresScroll,
+	), w)
+	d.SetButtons([]fyne.CanvasObject{closeBtn, summaryBtn, openBtn, retryBtn})
+	d.Show()
+}
+
+// showMuxWarnings lists the warnings mkvmerge printed while muxing into
+// outputPath, one per row, instead of its raw output
+func showMuxWarnings(w fyne.Window, outputPath string, warnings []*MuxWarning) {
+	list := container.NewVBox()
+	for _, warning := range warnings {
+		label := widget.NewLabel(warning.String())
+		label.Wrapping = fyne.TextWrapWord
+		list.Add(label)
+	}
+	scroll := container.NewScroll(list)
+	scroll.SetMinSize(fyne.NewSize(600, 250))
+	header := widget.NewLabel(trf("%s was written, but mkvmerge reported %d warning(s):", filepath.Base(outputPath), len(warnings)))
+	header.Wrapping = fyne.TextWrapWord
+	dialog.ShowCustom(tr("Mux Warnings"), tr("Close"), container.NewBorder(header, nil, nil, nil, scroll), w)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// cachedOCRCue is a subtitle of the OCR cache, without its bitmap
+type cachedOCRCue struct {
+	Start      time.Duration `json:"start"`
+	End        time.Duration `json:"end"`
+	Text       string        `json:"text"`
+	Confidence float64       `json:"confidence"`
+}
+
+// cachedOCRResult is an OCR result of the cache: the subtitles written and
+// how many subtitle bitmaps no text was read from
+type cachedOCRResult struct {
+	Empty int            `json:"empty"`
+	Cues  []cachedOCRCue `json:"cues"`
+}
+
+// ocrCacheDir returns the folder OCR results are cached in, in the app data dir
+func ocrCacheDir() string {
+	return filepath.Join(fyne.CurrentApp().Storage().RootURI().Path(), "ocr-cache")
+}
+
+// ocrCacheKey returns the key of the OCR result of subtitle files read with
+// opts: the hash of their content and of every setting that changes the
+// text recognized, so the same track extracted from another copy of a movie
+// shares the result
+func ocrCacheKey(opts OCROptions, paths ...string) (string, error) {
+	h := sha256.New()
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(h, file)
+		file.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	fmt.Fprintf(h, "\x00%s|%s|%s|%+v|%+q|%t|%t|%t", ocrEngineByName(opts.Engine).Name(), opts.Lang, opts.Quality,
+		opts.Preprocess, opts.Charset, opts.Correct, opts.Italics, opts.Top)
+	fmt.Fprintf(h, "|%q|%g", opts.Fallback, opts.FallbackThreshold)
+	for _, rule := range opts.Rules {
+		if rule.appliesTo(opts.Lang) {
+			fmt.Fprintf(h, "|%+q", rule)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// loadOCRCache returns the subtitles cached for key and how many subtitle
+// bitmaps no text was read from, if any
+func loadOCRCache(key string) ([]OCRCue, int, bool) {
+	data, err := os.ReadFile(filepath.Join(ocrCacheDir(), key+".json"))
+	if err != nil {
+		return nil, 0, false
+	}
+	var cached cachedOCRResult
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, 0, false
+	}
+	cues := make([]OCRCue, len(cached.Cues))
+	for i, c := range cached.Cues {
+		cues[i] = OCRCue{BitmapCue: BitmapCue{Start: c.Start, End: c.End}, Text: c.Text, Confidence: c.Confidence}
+	}
+	return cues, cached.Empty, true
+}
+
+// saveOCRCache caches the subtitles recognized for key, as they were written
+// after review
+func saveOCRCache(key string, cues []OCRCue, empty int) error {
+	cached := cachedOCRResult{Empty: empty, Cues: make([]cachedOCRCue, len(cues))}
+	for i, cue := range cues {
+		cached.Cues[i] = cachedOCRCue{Start: cue.Start, End: cue.End, Text: cue.Text, Confidence: cue.Confidence}
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(ocrCacheDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(ocrCacheDir(), key+".json"), data, 0644)
+}
+
+// ocrCacheSize returns how many OCR results are cached and their total size
+func ocrCacheSize() (int, int64) {
+	entries, err := os.ReadDir(ocrCacheDir())
+	if err != nil {
+		return 0, 0
+	}
+	count, size := 0, int64(0)
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && !entry.IsDir() {
+			count++
+			size += info.Size()
+		}
+	}
+	return count, size
+}
+
+// clearOCRCache removes every cached OCR result
+func clearOCRCache() error {
+	return os.RemoveAll(ocrCacheDir())
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// defaultOCRBlacklist are the characters Tesseract misreads for I, l or
+// noise in Latin script subtitles, which are never read unless a language is
+// set otherwise
+const defaultOCRBlacklist = "|~"
+
+// ocrBlacklistExceptions are the languages whose subtitles do use the
+// characters of defaultOCRBlacklist, such as the wave dash of Japanese
+var ocrBlacklistExceptions = map[string]bool{
+	"jpn": true, "jpn_vert": true, "chi_sim": true, "chi_tra": true, "kor": true,
+}
+
+// OCRCharset limits the characters an OCR engine may read
+type OCRCharset struct {
+	Whitelist string // Only these characters are read, when not empty
+	Blacklist string // These characters are never read
+}
+
+// allows reports whether the charset lets the OCR engine read r
+func (c OCRCharset) allows(r rune) bool {
+	if strings.ContainsRune(c.Blacklist, r) {
+		return false
+	}
+	return c.Whitelist == "" || strings.ContainsRune(c.Whitelist, r)
+}
+
+// filter removes the characters the charset does not allow from text read by
+// an engine that cannot be limited itself; spaces and line breaks are kept
+func (c OCRCharset) filter(text string) string {
+	if c.Whitelist == "" && c.Blacklist == "" {
+		return text
+	}
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\n' || c.allows(r) {
+			return r
+		}
+		return -1
+	}, text)
+}
+
+// ocrBlacklistPref and ocrWhitelistPref are the preferences holding the
+// characters set for a Tesseract language in the Settings tab
+func ocrBlacklistPref(lang string) string { return "ocr_blacklist_" + lang }
+func ocrWhitelistPref(lang string) string { return "ocr_whitelist_" + lang }
+
+// languageOCRCharset returns the characters set for a single Tesseract
+// language, or its default
+func languageOCRCharset(lang string) OCRCharset {
+	blacklist := defaultOCRBlacklist
+	if ocrBlacklistExceptions[lang] {
+		blacklist = ""
+	}
+	prefs := fyne.CurrentApp().Preferences()
+	return OCRCharset{
+		Whitelist: prefs.String(ocrWhitelistPref(lang)),
+		Blacklist: prefs.StringWithFallback(ocrBlacklistPref(lang), blacklist),
+	}
+}
+
+// ocrCharset returns the characters to read text in the Tesseract languages
+// lang, joined with +. Of several languages, only the characters all of them
+// blacklist are left out, and the whitelist only applies when each of them
+// has one.
+func ocrCharset(lang string) OCRCharset {
+	var charset OCRCharset
+	for i, code := range strings.Split(lang, "+") {
+		c := languageOCRCharset(code)
+		if i == 0 {
+			charset = c
+			continue
+		}
+		charset.Blacklist = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(c.Blacklist, r) {
+				return r
+			}
+			return -1
+		}, charset.Blacklist)
+		if charset.Whitelist == "" || c.Whitelist == "" {
+			charset.Whitelist = ""
+		} else {
+			charset.Whitelist += c.Whitelist
+		}
+	}
+	return charset
+}
+
+// tesseractCharsetArgs returns the Tesseract arguments limiting the
+// characters it reads to charset
+func tesseractCharsetArgs(charset OCRCharset) []string {
+	var args []string
+	if charset.Whitelist != "" {
+		args = append(args, "-c", "tessedit_char_whitelist="+charset.Whitelist)
+	}
+	if charset.Blacklist != "" {
+		args = append(args, "-c", "tessedit_char_blacklist="+charset.Blacklist)
+	}
+	return args
+}
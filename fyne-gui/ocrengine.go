@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// OCREngine reads the text of subtitle bitmaps
+type OCREngine interface {
+	// Name is shown in the OCR Engine column and stored with the track
+	Name() string
+
+	// Recognize returns the text of every bitmap and the confidence it was
+	// read with, from 0 to 100, calling onProgress after every bitmap. The
+	// language, model quality and preprocessing are taken from opts. When ctx
+	// is cancelled, the bitmaps read before the first one not read yet are
+	// returned with its error.
+	Recognize(ctx context.Context, cues []BitmapCue, opts OCROptions, onProgress func(done, total int)) ([]string, []float64, error)
+}
+
+// ocrEngines are the OCR engines a track can be read with, the default first
+// and the cloud providers last
+var ocrEngines = []OCREngine{tesseractEngine{}, paddleOCREngine{}, googleVisionEngine{}, azureReadEngine{}}
+
+// ocrEngineNames returns the names of ocrEngines, for selecting one
+func ocrEngineNames() []string {
+	var names []string
+	for _, engine := range ocrEngines {
+		names = append(names, engine.Name())
+	}
+	return names
+}
+
+// ocrEngineByName returns the OCR engine with name, or the default one
+func ocrEngineByName(name string) OCREngine {
+	for _, engine := range ocrEngines {
+		if engine.Name() == name {
+			return engine
+		}
+	}
+	return ocrEngines[0]
+}
+
+// ocrEngine returns the name of the OCR engine selected for a track
+func ocrEngine(t *TrackItem) string {
+	if t.Engine == nil {
+		return ""
+	}
+	return t.Engine.Selected
+}
+
+// tesseractEngine reads bitmaps with the tesseract command, one bitmap per
+// CPU core at a time. Missing traineddata of the quality for the language is
+// downloaded first.
+type tesseractEngine struct{}
+
+func (tesseractEngine) Name() string {
+	return "Tesseract"
+}
+
+func (tesseractEngine) Recognize(ctx context.Context, cues []BitmapCue, opts OCROptions, onProgress func(done, total int)) ([]string, []float64, error) {
+	tessdata, err := tesseractDataDir(ctx, opts.Lang, opts.Quality)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "ocr_*")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	texts := make([]string, len(cues))
+	confidences := make([]float64, len(cues))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	done := 0
+	read := make([]bool, len(cues))
+	next := make(chan int)
+	for range min(runtime.NumCPU(), len(cues)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				path := filepath.Join(dir, fmt.Sprintf("cue%d.png", i))
+				text, confidence, err := recognizeImage(ctx, cues[i].Image, opts.Preprocess, opts.Charset, opts.Lang, tessdata, path)
+				os.Remove(path)
+				mu.Lock()
+				if err != nil {
+					// The first error stops the other workers; theirs follow from
+					// it, as do those of a cancelled run
+					if firstErr == nil && ctx.Err() == nil {
+						firstErr = err
+						cancel()
+					}
+				} else {
+					texts[i], confidences[i] = text, confidence
+					read[i] = true
+					done++
+					onProgress(done, len(cues))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for i := range cues {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		n := 0
+		for n < len(read) && read[n] {
+			n++
+		}
+		return texts[:n], confidences[:n], err
+	}
+	return texts, confidences, nil
+}
+
+// paddleOCRBatch is how many bitmaps one paddleocr run reads. Every run
+// loads the models again, so runs are few, but often enough to show progress.
+const paddleOCRBatch = 200
+
+// paddleLanguages maps Tesseract languages to PaddleOCR ones
+var paddleLanguages = map[string]string{
+	"eng": "en", "fra": "fr", "deu": "german", "spa": "es", "ita": "it",
+	"por": "pt", "nld": "nl", "rus": "ru", "jpn": "japan", "chi_sim": "ch",
+	"chi_tra": "chinese_cht", "kor": "korean", "ces": "cs", "pol": "pl",
+	"swe": "sv", "dan": "da", "nor": "no", "hun": "hu", "tur": "tr",
+	"ara": "ar", "fas": "fa", "urd": "ur", "hin": "hi", "ukr": "uk",
+	"tha": "th", "ell": "el", "vie": "vi",
+}
+
+// paddleOCREngine reads bitmaps with the paddleocr command of PaddleOCR,
+// whose neural models read scripts such as Chinese, Japanese, Korean and
+// Arabic better than Tesseract. PaddleOCR reads one language at a time, so
+// of several languages the first is used.
+type paddleOCREngine struct{}
+
+func (paddleOCREngine) Name() string {
+	return "PaddleOCR"
+}
+
+func (paddleOCREngine) Recognize(ctx context.Context, cues []BitmapCue, opts OCROptions, onProgress func(done, total int)) ([]string, []float64, error) {
+	lang, _, _ := strings.Cut(opts.Lang, "+")
+	paddleLang, ok := paddleLanguages[lang]
+	if !ok {
+		return nil, nil, fmt.Errorf("PaddleOCR does not read %s; use Tesseract for this track", lang)
+	}
+	if _, err := exec.LookPath("paddleocr"); err != nil {
+		return nil, nil, fmt.Errorf("paddleocr was not found; install it with pip install paddleocr paddlepaddle")
+	}
+
+	dir, err := os.MkdirTemp("", "paddleocr_*")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	texts := make([]string, len(cues))
+	confidences := make([]float64, len(cues))
+	for first := 0; first < len(cues); first += paddleOCRBatch {
+		last := min(first+paddleOCRBatch, len(cues))
+		inputDir := filepath.Join(dir, fmt.Sprintf("batch%d", first))
+		outputDir := inputDir + "_res"
+		if err := os.Mkdir(inputDir, 0755); err != nil {
+			return nil, nil, err
+		}
+		for i := first; i < last; i++ {
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, ocrImage(cues[i].Image, opts.Preprocess)); err != nil {
+				return nil, nil, err
+			}
+			if err := os.WriteFile(filepath.Join(inputDir, fmt.Sprintf("cue%d.png", i)), buf.Bytes(), 0644); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		// Subtitles are upright lines of text, so the document models are left out
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "paddleocr", "ocr", "-i", inputDir, "--lang", paddleLang, "--save_path", outputDir,
+			"--use_doc_orientation_classify", "False", "--use_doc_unwarping", "False", "--use_textline_orientation", "False")
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return texts[:first], confidences[:first], ctx.Err()
+			}
+			return nil, nil, fmt.Errorf("Error running paddleocr: %v\n%s", err, stderr.String())
+		}
+		for i := first; i < last; i++ {
+			// Bitmaps without text have no result
+			data, err := os.ReadFile(filepath.Join(outputDir, fmt.Sprintf("cue%d_res.json", i)))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			texts[i], confidences[i], err = parsePaddleOCRResult(data)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Error reading the paddleocr result of subtitle %d: %v", i+1, err)
+			}
+			// paddleocr has no character set option
+			texts[i] = opts.Charset.filter(texts[i])
+		}
+		onProgress(last, len(cues))
+	}
+	return texts, confidences, nil
+}
+
+// parsePaddleOCRResult returns the lines of text of a PaddleOCR result file,
+// which are listed top to bottom, and their mean confidence from 0 to 100
+func parsePaddleOCRResult(data []byte) (string, float64, error) {
+	var result struct {
+		Texts  []string  `json:"rec_texts"`
+		Scores []float64 `json:"rec_scores"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", 0, err
+	}
+	var lines []string
+	var total float64
+	for i, text := range result.Texts {
+		text = strings.TrimSpace(text)
+		if text == "" || i >= len(result.Scores) {
+			continue
+		}
+		lines = append(lines, text)
+		total += result.Scores[i]
+	}
+	if len(lines) == 0 {
+		return "", 0, nil
+	}
+	return strings.Join(lines, "\n"), total / float64(len(lines)) * 100, nil
+}
+
+// ocrFallback sums up the retries of low confidence subtitles on the
+// fallback engines of a conversion
+type ocrFallback struct {
+	Retried  int   // Subtitles read again by a fallback engine
+	Improved int   // Subtitles a fallback engine read with higher confidence
+	Err      error // Why a fallback engine could not be used
+}
+
+// recognizeWithFallback reads bitmaps with the OCR engine of opts, then
+// retries those read with less than opts.FallbackThreshold confidence on each
+// engine of opts.Fallback in turn, keeping the text read with the highest
+// confidence. A fallback engine that fails is skipped. When ctx is cancelled
+// during the retries, every bitmap has been read once and is returned with
+// the error.
+func recognizeWithFallback(ctx context.Context, cues []BitmapCue, opts OCROptions, onProgress func(done, total int)) ([]string, []float64, ocrFallback, error) {
+	var fallback ocrFallback
+	primary := ocrEngineByName(opts.Engine)
+	texts, confidences, err := primary.Recognize(ctx, cues, opts, onProgress)
+	if err != nil {
+		return texts, confidences, fallback, err
+	}
+
+	tried := map[string]bool{primary.Name(): true}
+	retried := make([]bool, len(cues))
+	for _, name := range opts.Fallback {
+		engine := ocrEngineByName(name)
+		if engine.Name() != name || tried[name] {
+			continue
+		}
+		tried[name] = true
+
+		var low []int
+		for i, confidence := range confidences {
+			if confidence < opts.FallbackThreshold {
+				low = append(low, i)
+			}
+		}
+		if len(low) == 0 {
+			break
+		}
+		subset := make([]BitmapCue, len(low))
+		for j, i := range low {
+			subset[j] = cues[i]
+		}
+		// Progress starts over for the subtitles retried
+		retryTexts, retryConfidences, err := engine.Recognize(ctx, subset, opts, onProgress)
+		for j := range retryTexts {
+			i := low[j]
+			if !retried[i] {
+				retried[i] = true
+				fallback.Retried++
+			}
+			if retryConfidences[j] > confidences[i] && retryTexts[j] != "" {
+				texts[i], confidences[i] = retryTexts[j], retryConfidences[j]
+				fallback.Improved++
+			}
+		}
+		if ctx.Err() != nil {
+			return texts, confidences, fallback, ctx.Err()
+		}
+		if err != nil && fallback.Err == nil {
+			fallback.Err = fmt.Errorf("%s: %v", engine.Name(), err)
+		}
+	}
+	return texts, confidences, fallback, nil
+}
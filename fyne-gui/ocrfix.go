@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// hunspellDictionaries maps Tesseract languages to the Hunspell dictionaries
+// OCR output is spell checked with
+var hunspellDictionaries = map[string]string{
+	"eng": "en_US", "fra": "fr_FR", "deu": "de_DE", "spa": "es_ES", "ita": "it_IT",
+	"por": "pt_PT", "nld": "nl_NL", "rus": "ru_RU", "pol": "pl_PL", "ces": "cs_CZ",
+	"swe": "sv_SE", "dan": "da_DK", "nor": "nb_NO", "fin": "fi_FI", "hun": "hu_HU",
+	"tur": "tr_TR", "ell": "el_GR", "ron": "ro_RO", "hrv": "hr_HR", "ukr": "uk_UA",
+}
+
+// ocrConfusions are the characters OCR mistakes for one another, tried in
+// turn on a misspelled word until the spell checker accepts it
+var ocrConfusions = [][2]string{
+	{"0", "o"}, {"0", "O"}, {"1", "l"}, {"1", "I"}, {"l", "I"}, {"I", "l"},
+	{"rn", "m"}, {"vv", "w"}, {"5", "s"}, {"5", "S"}, {"8", "B"}, {"cl", "d"},
+}
+
+// ocrSubstitutions fix OCR errors that need no dictionary
+var ocrSubstitutions = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	// A vertical bar is always a misread I
+	{regexp.MustCompile(`\|`), "I"},
+	{regexp.MustCompile(`''`), `"`},
+	{regexp.MustCompile(` +([,.])`), "$1"},
+	{regexp.MustCompile(`  +`), " "},
+}
+
+// englishSubstitutions fix the lowercase l read for the pronoun I
+var englishSubstitutions = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\bl(['’](m|ll|ve|d))\b`), "I$1"},
+	{regexp.MustCompile(`(?m)(^|[\s">}-])l(\s|$)`), "${1}I$2"},
+}
+
+// ocrWord matches the words spell checked, digits included as OCR reads
+// letters as digits
+var ocrWord = regexp.MustCompile(`[\p{L}\d]+`)
+
+// correctOCRCues fixes common OCR errors in the text of recognized subtitles:
+// substitutions that always apply, then misspelled words that one of
+// ocrConfusions turns into a word of the Hunspell dictionary of lang. The
+// spell check is left out when hunspell or the dictionary is not installed.
+// It returns how many subtitles were changed.
+func correctOCRCues(ctx context.Context, cues []OCRCue, lang string) int {
+	lang, _, _ = strings.Cut(lang, "+")
+	texts := make([]string, len(cues))
+	for i, cue := range cues {
+		text := cue.Text
+		for _, s := range ocrSubstitutions {
+			text = s.re.ReplaceAllString(text, s.replacement)
+		}
+		if lang == "eng" {
+			for _, s := range englishSubstitutions {
+				text = s.re.ReplaceAllString(text, s.replacement)
+			}
+		}
+		texts[i] = text
+	}
+
+	// Every word and its candidates are checked in one hunspell run
+	candidates := make(map[string][]string)
+	var words []string
+	for _, text := range texts {
+		for _, word := range ocrWord.FindAllString(text, -1) {
+			if _, ok := candidates[word]; ok || len([]rune(word)) < 2 || isNumber(word) {
+				continue
+			}
+			candidates[word] = ocrCandidates(word)
+			words = append(words, word)
+			words = append(words, candidates[word]...)
+		}
+	}
+	if correct, err := hunspellCorrectWords(ctx, hunspellDictionaries[lang], words); err == nil {
+		for i, text := range texts {
+			texts[i] = ocrWord.ReplaceAllStringFunc(text, func(word string) string {
+				if correct[word] {
+					return word
+				}
+				for _, candidate := range candidates[word] {
+					if correct[candidate] {
+						return candidate
+					}
+				}
+				return word
+			})
+		}
+	}
+
+	changed := 0
+	for i := range cues {
+		if texts[i] != cues[i].Text {
+			cues[i].Text = texts[i]
+			changed++
+		}
+	}
+	return changed
+}
+
+// ocrCandidates returns the words ocrConfusions turn word into
+func ocrCandidates(word string) []string {
+	var candidates []string
+	for _, c := range ocrConfusions {
+		if candidate := strings.ReplaceAll(word, c[0], c[1]); candidate != word {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// isNumber reports whether word is made of digits only
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// hunspellCorrectWords returns which of words the Hunspell dictionary dict
+// accepts
+func hunspellCorrectWords(ctx context.Context, dict string, words []string) (map[string]bool, error) {
+	if dict == "" {
+		return nil, exec.ErrNotFound
+	}
+	cmd := exec.CommandContext(ctx, "hunspell", "-d", dict, "-i", "UTF-8", "-G")
+	cmd.Stdin = strings.NewReader(strings.Join(words, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	correct := make(map[string]bool)
+	for _, word := range strings.Fields(string(output)) {
+		correct[word] = true
+	}
+	return correct, nil
+}
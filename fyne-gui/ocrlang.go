@@ -0,0 +1,131 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ocrLanguageOptions are the languages offered for reading image-based
+// tracks, as "Name (code)"
+var ocrLanguageOptions = []string{
+	"English (en)",
+	"French (fr)",
+	"German (de)",
+	"Spanish (es)",
+	"Italian (it)",
+	"Portuguese (pt)",
+	"Dutch (nl)",
+	"Russian (ru)",
+	"Japanese (ja)",
+	"Chinese (zh)",
+	"Korean (ko)",
+	"Czech (cs)",
+	"Polish (pl)",
+	"Swedish (sv)",
+	"Danish (da)",
+	"Finnish (fi)",
+	"Norwegian (no)",
+	"Hungarian (hu)",
+	"Greek (el)",
+	"Turkish (tr)",
+	"Arabic (ar)",
+	"Hebrew (he)",
+	"Thai (th)",
+}
+
+// multipleOCRLanguagesOption opens a dialog to read a track in several
+// languages, for tracks that mix scripts
+const multipleOCRLanguagesOption = "Multiple Languages..."
+
+// ocrLanguageCode returns the code of an OCR language option, such as en or
+// en+ja, or an empty string for the Auto option
+func ocrLanguageCode(option string) string {
+	if strings.HasPrefix(option, "Auto") {
+		return ""
+	}
+	start := strings.LastIndex(option, "(")
+	end := strings.LastIndex(option, ")")
+	if start == -1 || end <= start {
+		return ""
+	}
+	return option[start+1 : end]
+}
+
+// ocrLanguage returns the Tesseract language to read a track with: the one
+// selected for it, or its own language. Several languages are joined with +.
+func ocrLanguage(t *TrackItem) string {
+	code := t.Lang
+	if t.LangSelect != nil {
+		if selected := ocrLanguageCode(t.LangSelect.Selected); selected != "" {
+			code = selected
+		}
+	}
+	var langs []string
+	for _, lang := range strings.Split(code, "+") {
+		if mapped, ok := subtitleLanguageCodes[strings.ToLower(strings.TrimSpace(lang))]; ok {
+			lang = mapped
+		}
+		langs = append(langs, tesseractLanguage(lang))
+	}
+	return strings.Join(langs, "+")
+}
+
+// selectOCRLanguage selects an OCR language option, adding it first when it
+// is a combination of languages chosen earlier
+func selectOCRLanguage(sel *widget.Select, option string) {
+	if option == "" || option == multipleOCRLanguagesOption {
+		return
+	}
+	if strings.Contains(ocrLanguageCode(option), "+") && !slices.Contains(sel.Options, option) {
+		// Combinations go before the Multiple Languages option
+		n := len(sel.Options) - 1
+		sel.Options = append(sel.Options[:n:n], option, sel.Options[n])
+	}
+	sel.SetSelected(option)
+}
+
+// showOCRLanguagesDialog lets the user pick several languages to read a track
+// in and selects their combination, then calls onChosen
+func showOCRLanguagesDialog(w fyne.Window, t *TrackItem, onChosen func()) {
+	var checks []*widget.Check
+	var objects []fyne.CanvasObject
+	current := strings.Split(ocrLanguageCode(t.LangSelect.Selected), "+")
+	for _, option := range ocrLanguageOptions {
+		check := widget.NewCheck(option, nil)
+		check.SetChecked(slices.Contains(current, ocrLanguageCode(option)))
+		checks = append(checks, check)
+		objects = append(objects, check)
+	}
+	grid := container.NewGridWithColumns(3, objects...)
+	dialog.ShowCustomConfirm(tr("OCR Languages"), tr("OK"), tr("Cancel"), container.NewVBox(
+		widget.NewLabel(tr("Read the track in all the languages checked, for tracks that mix scripts:")),
+		grid,
+	), func(ok bool) {
+		if !ok {
+			return
+		}
+		var chosen, names, codes []string
+		for i, check := range checks {
+			if check.Checked {
+				option := ocrLanguageOptions[i]
+				chosen = append(chosen, option)
+				names = append(names, strings.TrimSpace(option[:strings.LastIndex(option, "(")]))
+				codes = append(codes, ocrLanguageCode(option))
+			}
+		}
+		switch len(chosen) {
+		case 0:
+			return
+		case 1:
+			t.LangSelect.SetSelected(chosen[0])
+		default:
+			selectOCRLanguage(t.LangSelect, strings.Join(names, " + ")+" ("+strings.Join(codes, "+")+")")
+		}
+		onChosen()
+	}, w)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ocrSuspiciousChars are characters OCR engines tend to read from noise or
+// misread letters, which are rare in real subtitle text
+const ocrSuspiciousChars = `|\_~^@#*§¦[]{}<>`
+
+// ocrTagRegex matches the tags written into recognized text, which are not
+// counted as suspicious characters
+var ocrTagRegex = regexp.MustCompile(`</?i>|\{\\an8\}`)
+
+// ocrReport sums up the quality of the subtitles of a track read by OCR
+type ocrReport struct {
+	Cues           int
+	Empty          int // Subtitle bitmaps no text was read from
+	MeanConfidence float64
+	LowConfidence  []string // Report lines of the subtitles to proofread
+	Suspicious     map[rune]int
+}
+
+// add counts the nth subtitle written to the SRT file
+func (r *ocrReport) add(n int, timing, text string, confidence float64) {
+	r.Cues++
+	r.MeanConfidence += (confidence - r.MeanConfidence) / float64(r.Cues)
+	if confidence < lowConfidenceThreshold {
+		r.LowConfidence = append(r.LowConfidence, fmt.Sprintf("%d\t%s\t%.0f%%\t%s", n, timing, confidence, strings.ReplaceAll(text, "\n", " | ")))
+	}
+	for _, c := range ocrTagRegex.ReplaceAllString(text, "") {
+		if strings.ContainsRune(ocrSuspiciousChars, c) {
+			if r.Suspicious == nil {
+				r.Suspicious = make(map[rune]int)
+			}
+			r.Suspicious[c]++
+		}
+	}
+}
+
+// suspiciousCount returns how many suspicious characters were read in all
+func (r ocrReport) suspiciousCount() int {
+	total := 0
+	for _, n := range r.Suspicious {
+		total += n
+	}
+	return total
+}
+
+// String formats the report as written next to the SRT file srtName
+func (r ocrReport) String(srtName string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "OCR report of %s\n\n", srtName)
+	fmt.Fprintf(&b, "Subtitles:              %d\n", r.Cues)
+	fmt.Fprintf(&b, "Mean confidence:        %.1f%%\n", r.MeanConfidence)
+	fmt.Fprintf(&b, "Low confidence (<%d%%):  %d\n", lowConfidenceThreshold, len(r.LowConfidence))
+	fmt.Fprintf(&b, "Empty subtitles:        %d\n", r.Empty)
+	fmt.Fprintf(&b, "Suspicious characters:  %d", r.suspiciousCount())
+	if len(r.Suspicious) > 0 {
+		chars := make([]rune, 0, len(r.Suspicious))
+		for c := range r.Suspicious {
+			chars = append(chars, c)
+		}
+		// Most frequent first
+		sort.Slice(chars, func(i, j int) bool {
+			if r.Suspicious[chars[i]] != r.Suspicious[chars[j]] {
+				return r.Suspicious[chars[i]] > r.Suspicious[chars[j]]
+			}
+			return chars[i] < chars[j]
+		})
+		counts := make([]string, len(chars))
+		for i, c := range chars {
+			counts[i] = fmt.Sprintf("%c %d", c, r.Suspicious[c])
+		}
+		fmt.Fprintf(&b, " (%s)", strings.Join(counts, ", "))
+	}
+	b.WriteString("\n")
+
+	if len(r.LowConfidence) > 0 {
+		fmt.Fprintf(&b, "\nSubtitles recognized with less than %d%% confidence; proofread these lines.\n\n", lowConfidenceThreshold)
+		b.WriteString(strings.Join(r.LowConfidence, "\n") + "\n")
+	}
+	return b.String()
+}
+
+// ocrReportPath returns the accuracy report of an SRT file written by OCR
+func ocrReportPath(srtPath string) string {
+	return strings.TrimSuffix(srtPath, filepath.Ext(srtPath)) + ".ocr-report.txt"
+}
+
+// writeOCRReport writes the report next to the SRT file srtPath and returns
+// its path
+func writeOCRReport(srtPath string, r ocrReport) (string, error) {
+	reportPath := ocrReportPath(srtPath)
+	return reportPath, os.WriteFile(reportPath, []byte(r.String(filepath.Base(srtPath))), 0644)
+}
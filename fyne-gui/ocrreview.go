@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showOCRReview shows every recognized subtitle bitmap next to its text for
+// correcting OCR errors, then calls onDone with the corrected subtitles once
+// they are to be written. Subtitles corrected by hand count as fully
+// confident; those whose text is cleared are left out.
+func showOCRReview(w fyne.Window, title string, cues []OCRCue, onDone func([]OCRCue)) {
+	edited := make([]bool, len(cues))
+	lowConfidence := 0
+	for _, cue := range cues {
+		if cue.Confidence < lowConfidenceThreshold {
+			lowConfidence++
+		}
+	}
+
+	// Indexes of the cues shown, all of them or those with low confidence
+	var visible []int
+	filterCues := func(lowOnly bool) {
+		visible = visible[:0]
+		for i, cue := range cues {
+			if !lowOnly || cue.Confidence < lowConfidenceThreshold {
+				visible = append(visible, i)
+			}
+		}
+	}
+	filterCues(false)
+
+	list := widget.NewList(
+		func() int {
+			return len(visible)
+		},
+		func() fyne.CanvasObject {
+			img := canvas.NewImageFromImage(nil)
+			img.FillMode = canvas.ImageFillContain
+			img.SetMinSize(fyne.NewSize(360, 72))
+			info := widget.NewLabel("")
+			entry := widget.NewMultiLineEntry()
+			entry.SetMinRowsVisible(2)
+			return container.NewBorder(info, nil, img, nil, entry)
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if id >= len(visible) {
+				return
+			}
+			i := visible[id]
+			cue := cues[i]
+			row := o.(*fyne.Container)
+			entry := row.Objects[0].(*widget.Entry)
+			info := row.Objects[1].(*widget.Label)
+			img := row.Objects[2].(*canvas.Image)
+
+			img.Image = onDarkBackground(cue.Image)
+			img.Refresh()
+			text := fmt.Sprintf("%d  %s --> %s", i+1, formatSRTTime(cue.Start), formatSRTTime(cue.End))
+			switch {
+			case edited[i]:
+				text += "  " + tr("corrected")
+				info.Importance = widget.SuccessImportance
+			case cue.Confidence < lowConfidenceThreshold:
+				text += "  " + trf("low confidence (%.0f%%)", cue.Confidence)
+				info.Importance = widget.WarningImportance
+			default:
+				text += fmt.Sprintf("  %.0f%%", cue.Confidence)
+				info.Importance = widget.MediumImportance
+			}
+			info.SetText(text)
+
+			// Rows are reused, so the entry is bound to this cue only after its text is set
+			entry.OnChanged = nil
+			entry.SetText(cue.Text)
+			entry.OnChanged = func(s string) {
+				cues[i].Text = s
+				edited[i] = true
+			}
+		},
+	)
+
+	lowOnlyCheck := widget.NewCheck(tr("Only show low confidence subtitles"), func(checked bool) {
+		filterCues(checked)
+		list.UnselectAll()
+		list.Refresh()
+		list.ScrollToTop()
+	})
+	summary := widget.NewLabel(trf("%d subtitles recognized, %d with low confidence. Correct the text next to each bitmap; clear it to leave the subtitle out.", len(cues), lowConfidence))
+	summary.Wrapping = fyne.TextWrapWord
+
+	var d *dialog.CustomDialog
+	writeBtn := widget.NewButton(tr("Write SRT"), func() {
+		d.Hide()
+		for i := range cues {
+			if edited[i] {
+				cues[i].Confidence = 100
+			}
+		}
+		onDone(cues)
+	})
+	writeBtn.Importance = widget.HighImportance
+	d = dialog.NewCustomWithoutButtons(title, container.NewBorder(container.NewVBox(summary, lowOnlyCheck), nil, nil, nil, list), w)
+	d.SetButtons([]fyne.CanvasObject{writeBtn})
+	d.Resize(fyne.NewSize(900, 650))
+	d.Show()
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+)
+
+// OCRRule is an entry of the OCR replace list, applied to the text of every
+// subtitle read by OCR, e.g. "Tbe" -> "The"
+type OCRRule struct {
+	Find    string `json:"find"`
+	Replace string `json:"replace"`
+	Regex   bool   `json:"regex,omitempty"` // Find is a regular expression; otherwise it matches whole words
+	Lang    string `json:"lang,omitempty"`  // Tesseract language the rule applies to, all when empty
+}
+
+// String describes the rule for the replace list in the Settings tab
+func (r OCRRule) String() string {
+	text := r.Find + "  →  " + r.Replace
+	if r.Regex {
+		text += "  (regex)"
+	}
+	if r.Lang != "" {
+		text += "  [" + r.Lang + "]"
+	}
+	return text
+}
+
+// appliesTo reports whether the rule applies to text read in the Tesseract
+// languages lang, joined with +
+func (r OCRRule) appliesTo(lang string) bool {
+	return r.Lang == "" || slices.Contains(strings.Split(lang, "+"), r.Lang)
+}
+
+// apply returns text with the rule applied; a regex that does not compile
+// changes nothing
+func (r OCRRule) apply(text string) string {
+	if !r.Regex {
+		return replaceWord(text, r.Find, r.Replace)
+	}
+	re, err := regexp.Compile(r.Find)
+	if err != nil {
+		return text
+	}
+	return re.ReplaceAllString(text, r.Replace)
+}
+
+// isWordRune reports whether r is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// replaceWord replaces the occurrences of word in text that are not part of
+// a longer word, so "l" is replaced in "l am" but not in "hello"
+func replaceWord(text, word, replacement string) string {
+	if word == "" {
+		return text
+	}
+	first, _ := utf8.DecodeRuneInString(word)
+	last, _ := utf8.DecodeLastRuneInString(word)
+	var b strings.Builder
+	pos := 0
+	for {
+		i := strings.Index(text[pos:], word)
+		if i < 0 {
+			break
+		}
+		start, end := pos+i, pos+i+len(word)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (isWordRune(first) && isWordRune(before)) || (isWordRune(last) && isWordRune(after)) {
+			// Part of a longer word; search on from the next character
+			_, size := utf8.DecodeRuneInString(text[start:])
+			b.WriteString(text[pos : start+size])
+			pos = start + size
+			continue
+		}
+		b.WriteString(text[pos:start])
+		b.WriteString(replacement)
+		pos = end
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
+
+// applyOCRRules applies the rules of the replace list that apply to lang to
+// recognized subtitles and returns how many were changed
+func applyOCRRules(cues []OCRCue, rules []OCRRule, lang string) int {
+	changed := 0
+	for i := range cues {
+		text := cues[i].Text
+		for _, rule := range rules {
+			if rule.appliesTo(lang) {
+				text = rule.apply(text)
+			}
+		}
+		if text != cues[i].Text {
+			cues[i].Text = text
+			changed++
+		}
+	}
+	return changed
+}
+
+// loadOCRRules reads the OCR replace list
+func loadOCRRules(prefs fyne.Preferences) []OCRRule {
+	data := prefs.String("ocr_replace_rules")
+	if data == "" {
+		return nil
+	}
+	var rules []OCRRule
+	if err := json.Unmarshal([]byte(data), &rules); err != nil {
+		fyne.LogError("Error loading the OCR replace list", err)
+		return nil
+	}
+	return rules
+}
+
+// saveOCRRules stores the OCR replace list in the preferences
+func saveOCRRules(prefs fyne.Preferences, rules []OCRRule) {
+	data, err := json.Marshal(rules)
+	if err != nil {
+		fyne.LogError("Error saving the OCR replace list", err)
+		return
+	}
+	prefs.SetString("ocr_replace_rules", string(data))
+}
+
+// ocrRulesMu keeps tracks converted in parallel from losing each other's
+// learned rules
+var ocrRulesMu sync.Mutex
+
+// maxLearnedEdits is how many characters a correction may change to be
+// learned as a rule; larger corrections are rewrites, not OCR errors
+const maxLearnedEdits = 2
+
+// learnOCRRules adds the words corrected by hand in the review of subtitles
+// read in lang to the OCR replace list, so the next results have them fixed
+// already. original holds the text of each subtitle before the review. It
+// returns how many rules were added.
+func learnOCRRules(original []string, reviewed []OCRCue, lang string) int {
+	lang, _, _ = strings.Cut(lang, "+")
+	var learned []OCRRule
+	for i, cue := range reviewed {
+		if i >= len(original) || cue.Text == original[i] {
+			continue
+		}
+		before := strings.Fields(ocrTagRegex.ReplaceAllString(original[i], ""))
+		after := strings.Fields(ocrTagRegex.ReplaceAllString(cue.Text, ""))
+		// Only word-for-word corrections tell which word was misread
+		if len(before) != len(after) {
+			continue
+		}
+		for j := range before {
+			find := strings.TrimFunc(before[j], func(r rune) bool { return !isWordRune(r) })
+			replace := strings.TrimFunc(after[j], func(r rune) bool { return !isWordRune(r) })
+			if find == "" || replace == "" || find == replace || editDistance(find, replace) > maxLearnedEdits {
+				continue
+			}
+			rule := OCRRule{Find: find, Replace: replace, Lang: lang}
+			if !slices.Contains(learned, rule) {
+				learned = append(learned, rule)
+			}
+		}
+	}
+	if len(learned) == 0 {
+		return 0
+	}
+
+	ocrRulesMu.Lock()
+	defer ocrRulesMu.Unlock()
+	prefs := fyne.CurrentApp().Preferences()
+	rules := loadOCRRules(prefs)
+	added := 0
+	for _, rule := range learned {
+		if !slices.ContainsFunc(rules, func(r OCRRule) bool { return r.Find == rule.Find && !r.Regex && r.Lang == rule.Lang }) {
+			rules = append(rules, rule)
+			added++
+		}
+	}
+	saveOCRRules(prefs, rules)
+	return added
+}
+
+// editDistance returns the Levenshtein distance between a and b in runes
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
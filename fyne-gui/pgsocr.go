@@ -0,0 +1,609 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PGS segment types besides the palette and object segments
+const (
+	pgsCompositionSegment = 0x16
+	pgsEndSegment         = 0x80
+)
+
+// defaultCueDuration is how long a subtitle is shown when its stream does not
+// say when it ends, such as the last subtitle of a PGS stream
+const defaultCueDuration = 3 * time.Second
+
+// lowConfidenceThreshold is the OCR confidence, from 0 to 100, below which a
+// subtitle is listed in the report to proofread
+const lowConfidenceThreshold = 75
+
+// tesseractLanguageCodes maps the ISO 639-2/B codes used by Matroska to the
+// ISO 639-2/T codes Tesseract names its traineddata files after
+var tesseractLanguageCodes = map[string]string{
+	"fre": "fra", "ger": "deu", "dut": "nld", "cze": "ces", "gre": "ell",
+	"rum": "ron", "slo": "slk", "chi": "chi_sim", "per": "fas", "ice": "isl",
+	"mac": "mkd", "alb": "sqi", "arm": "hye", "baq": "eus", "bur": "mya",
+	"geo": "kat", "may": "msa", "wel": "cym",
+}
+
+// OCRResult sums up an OCR conversion: the subtitles written, how well they
+// were recognized, and the accuracy report listing those to proofread
+type OCRResult struct {
+	Cues           int
+	MeanConfidence float64
+	LowConfidence  int
+	Empty          int // Subtitle bitmaps no text was read from
+	Suspicious     int // Characters that are likely misread
+	ReportPath     string
+	Corrected      int // Subtitles changed by the correction pass
+	Replaced       int // Subtitles changed by the OCR replace list
+	Learned        int // Rules added to the OCR replace list from the review
+	Fallback       ocrFallback
+	PartialPath    string // File the subtitles of a cancelled conversion were saved to
+	Cached         bool   // Whether the result of an earlier conversion was reused
+	CacheError     error  // Why the result could not be cached
+}
+
+// String sums up the result for the log
+func (r OCRResult) String() string {
+	text := fmt.Sprintf("%d subtitle(s) recognized, mean confidence %.1f%%\n", r.Cues, r.MeanConfidence)
+	if r.Corrected > 0 {
+		text += fmt.Sprintf("%d subtitle(s) corrected by the spell check\n", r.Corrected)
+	}
+	if r.Fallback.Retried > 0 {
+		text += fmt.Sprintf("%d low confidence subtitle(s) retried on fallback engines, %d read better\n", r.Fallback.Retried, r.Fallback.Improved)
+	}
+	if r.Fallback.Err != nil {
+		text += fmt.Sprintf("Fallback OCR engine skipped: %v\n", r.Fallback.Err)
+	}
+	if r.Replaced > 0 {
+		text += fmt.Sprintf("%d subtitle(s) corrected by the OCR replace list\n", r.Replaced)
+	}
+	if r.Learned > 0 {
+		text += fmt.Sprintf("%d correction(s) of the review added to the OCR replace list\n", r.Learned)
+	}
+	if r.LowConfidence > 0 {
+		text += fmt.Sprintf("%d subtitle(s) recognized with low confidence\n", r.LowConfidence)
+	}
+	if r.Empty > 0 {
+		text += fmt.Sprintf("%d subtitle(s) without any text read\n", r.Empty)
+	}
+	if r.Suspicious > 0 {
+		text += fmt.Sprintf("%d suspicious character(s) read\n", r.Suspicious)
+	}
+	if r.ReportPath != "" {
+		text += fmt.Sprintf("Accuracy report: %s\n", r.ReportPath)
+	}
+	if r.Cached {
+		text += "Reused the cached OCR result of an earlier conversion of the same subtitles\n"
+	}
+	if r.CacheError != nil {
+		text += fmt.Sprintf("The OCR result could not be cached: %v\n", r.CacheError)
+	}
+	if r.PartialPath != "" {
+		text += fmt.Sprintf("Cancelled: the subtitles recognized so far were saved to %s\n", r.PartialPath)
+	}
+	return text
+}
+
+// OCROptions are the settings of an OCR conversion
+type OCROptions struct {
+	Engine  string // Name of the OCR engine, Tesseract when empty
+	Lang    string // Tesseract language; several are joined with +
+	Quality string // OCRQualityFast or OCRQualityBest, for Tesseract
+	Correct bool   // Whether common OCR errors are fixed with correctOCRCues
+	Italics bool   // Whether lines of slanted glyphs are wrapped in <i> tags
+	Top     bool   // Whether subtitles at the top of the screen get an {\an8} tag
+
+	// SavePartial keeps the subtitles recognized before a cancelled
+	// conversion was stopped
+	SavePartial bool
+
+	// Cache reuses and stores results in the OCR cache
+	Cache bool
+
+	// Preprocess prepares the subtitle bitmaps for OCR
+	Preprocess OCRPreprocess
+
+	// Charset limits the characters read, as set per language
+	Charset OCRCharset
+
+	// Fallback are the names of the engines subtitles read with less than
+	// FallbackThreshold confidence are retried on, in order
+	Fallback          []string
+	FallbackThreshold float64
+
+	// Rules is the OCR replace list applied to the text read; LearnRules
+	// adds the words corrected in the review to it
+	Rules      []OCRRule
+	LearnRules bool
+
+	// Review, when not nil, is given the recognized subtitles to correct
+	// before they are written
+	Review func([]OCRCue) []OCRCue
+}
+
+// BitmapCue is a subtitle of an image-based stream: the bitmap shown from
+// Start to End
+type BitmapCue struct {
+	Start time.Duration
+	End   time.Duration
+	Image image.Image
+	Top   bool // Shown in the upper half of the screen, like signs
+}
+
+// OCRCue is a subtitle bitmap with the text recognized in it and the
+// confidence of the recognition, from 0 to 100
+type OCRCue struct {
+	BitmapCue
+	Text       string
+	Confidence float64
+}
+
+// pgsObject is the run-length encoded bitmap of a PGS object, which may be
+// split over several segments
+type pgsObject struct {
+	Width, Height int
+	Data          []byte
+}
+
+// pgsPlacement is an object shown by a composition, at its position on screen
+type pgsPlacement struct {
+	ID   uint16
+	X, Y int
+}
+
+// parsePGSCues reads the display sets of a PGS (.sup) stream and returns a
+// cue for every composition that shows objects, ended by the composition
+// that follows it. The objects of a composition are drawn into one bitmap.
+func parsePGSCues(data []byte) ([]BitmapCue, error) {
+	var cues []BitmapCue
+	palettes := map[byte]*[256]color.NRGBA{}
+	objects := map[uint16]*pgsObject{}
+	var pts time.Duration
+	var paletteID byte
+	var placements []pgsPlacement
+	var screenHeight int
+
+	for pos := 0; pos+13 <= len(data); {
+		if data[pos] != 'P' || data[pos+1] != 'G' {
+			return cues, fmt.Errorf("Invalid PGS segment at offset %d", pos)
+		}
+		segPTS := time.Duration(binary.BigEndian.Uint32(data[pos+2:])) * time.Second / 90000
+		segType := data[pos+10]
+		size := int(binary.BigEndian.Uint16(data[pos+11:]))
+		if pos+13+size > len(data) {
+			break
+		}
+		seg := data[pos+13 : pos+13+size]
+		pos += 13 + size
+
+		switch segType {
+		case pgsCompositionSegment:
+			if len(seg) < 11 {
+				continue
+			}
+			// Any composition ends the cue shown before it
+			if n := len(cues); n > 0 && cues[n-1].End == 0 {
+				cues[n-1].End = segPTS
+			}
+			pts = segPTS
+			screenHeight = int(binary.BigEndian.Uint16(seg[2:]))
+			// An epoch start discards the objects and palettes of earlier epochs
+			if seg[7]&0x80 != 0 {
+				palettes = map[byte]*[256]color.NRGBA{}
+				objects = map[uint16]*pgsObject{}
+			}
+			paletteID = seg[9]
+			placements = nil
+			for i, n := 11, int(seg[10]); n > 0 && i+8 <= len(seg); n-- {
+				placements = append(placements, pgsPlacement{
+					ID: binary.BigEndian.Uint16(seg[i:]),
+					X:  int(binary.BigEndian.Uint16(seg[i+4:])),
+					Y:  int(binary.BigEndian.Uint16(seg[i+6:])),
+				})
+				// Cropped objects carry their cropping rectangle
+				if seg[i+3]&0x80 != 0 {
+					i += 16
+				} else {
+					i += 8
+				}
+			}
+		case pgsPaletteSegment:
+			if len(seg) < 2 {
+				continue
+			}
+			palette, ok := palettes[seg[0]]
+			if !ok {
+				palette = &[256]color.NRGBA{}
+				palettes[seg[0]] = palette
+			}
+			// Entries of ID, Y, Cr, Cb and alpha
+			for i := 2; i+5 <= len(seg); i += 5 {
+				r, g, b := color.YCbCrToRGB(seg[i+1], seg[i+3], seg[i+2])
+				palette[seg[i]] = color.NRGBA{R: r, G: g, B: b, A: seg[i+4]}
+			}
+		case pgsObjectSegment:
+			if len(seg) < 4 {
+				continue
+			}
+			id := binary.BigEndian.Uint16(seg)
+			body := seg[4:]
+			if seg[3]&0x80 != 0 {
+				if len(body) < 7 {
+					continue
+				}
+				objects[id] = &pgsObject{
+					Width:  int(binary.BigEndian.Uint16(body[3:])),
+					Height: int(binary.BigEndian.Uint16(body[5:])),
+					Data:   append([]byte(nil), body[7:]...),
+				}
+			} else if object, ok := objects[id]; ok {
+				object.Data = append(object.Data, body...)
+			}
+		case pgsEndSegment:
+			if len(placements) == 0 {
+				continue
+			}
+			palette, ok := palettes[paletteID]
+			if !ok {
+				continue
+			}
+			if img, area := composePGSObjects(placements, objects, palette); img != nil {
+				cues = append(cues, BitmapCue{Start: pts, Image: img, Top: area.Min.Y+area.Max.Y < screenHeight})
+			}
+			placements = nil
+		}
+	}
+
+	if n := len(cues); n > 0 && cues[n-1].End == 0 {
+		cues[n-1].End = cues[n-1].Start + defaultCueDuration
+	}
+	return cues, nil
+}
+
+// composePGSObjects draws the objects of a composition into one bitmap that
+// covers them all, with the screen area it covers, or returns nil when none
+// of them has been received
+func composePGSObjects(placements []pgsPlacement, objects map[uint16]*pgsObject, palette *[256]color.NRGBA) (image.Image, image.Rectangle) {
+	var bounds image.Rectangle
+	var decoded []image.Image
+	var origins []image.Point
+	for _, p := range placements {
+		object, ok := objects[p.ID]
+		if !ok || object.Width == 0 || object.Height == 0 {
+			continue
+		}
+		decoded = append(decoded, decodePGSRLE(object.Data, object.Width, object.Height, palette))
+		origin := image.Pt(p.X, p.Y)
+		origins = append(origins, origin)
+		bounds = bounds.Union(image.Rectangle{Min: origin, Max: origin.Add(image.Pt(object.Width, object.Height))})
+	}
+	if len(decoded) == 0 {
+		return nil, bounds
+	}
+	if len(decoded) == 1 {
+		return decoded[0], bounds
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for i, object := range decoded {
+		offset := origins[i].Sub(bounds.Min)
+		b := object.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if c := object.At(x, y).(color.NRGBA); c.A > 0 {
+					img.SetNRGBA(x+offset.X, y+offset.Y, c)
+				}
+			}
+		}
+	}
+	return img, bounds
+}
+
+// OCRPreprocess are the settings of turning a subtitle bitmap into the
+// image read by OCR
+type OCRPreprocess struct {
+	Scale     int  // Factor the bitmap is enlarged by, for small DVD subtitles
+	Threshold int  // Brightness from 0 to 255 from which an opaque pixel is text
+	Padding   int  // Width of the white border around the text, in pixels
+	Invert    bool // Text is darker than its outline: pixels below Threshold are text
+}
+
+// defaultOCRPreprocess suits the light text with a dark outline of most
+// Blu-ray and DVD subtitles
+var defaultOCRPreprocess = OCRPreprocess{Scale: 1, Threshold: 128, Padding: 10}
+
+// ocrImage turns a subtitle bitmap into the black text on a white background
+// that OCR reads best: the opaque fill of the letters, lighter than the
+// threshold or darker when inverted, becomes black and everything else,
+// including their outline, white
+func ocrImage(img image.Image, p OCRPreprocess) *image.Gray {
+	scale := max(p.Scale, 1)
+	padding := max(p.Padding, 0)
+	b := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, b.Dx()*scale+2*padding, b.Dy()*scale+2*padding))
+	for i := range gray.Pix {
+		gray.Pix[i] = 0xff
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			luma := (299*int(c.R) + 587*int(c.G) + 114*int(c.B)) / 1000
+			if c.A < 0x80 || (luma >= p.Threshold) == p.Invert {
+				continue
+			}
+			// Enlarged pixels keep the sharp edges the text was binarized to
+			for dy := range scale {
+				for dx := range scale {
+					gray.SetGray((x-b.Min.X)*scale+dx+padding, (y-b.Min.Y)*scale+dy+padding, color.Gray{})
+				}
+			}
+		}
+	}
+	return gray
+}
+
+// tesseractLanguage returns the Tesseract language for a Matroska language code
+func tesseractLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if code, ok := tesseractLanguageCodes[lang]; ok {
+		return code
+	}
+	if lang == "" || lang == "und" {
+		return "eng"
+	}
+	return lang
+}
+
+// recognizeImage reads the text of a subtitle bitmap with Tesseract, using
+// the traineddata in tessdata when it is not empty and reading only the
+// characters charset allows. The bitmap is prepared as set by pre and written
+// to path for Tesseract to read. It also returns the mean confidence of the
+// words read, from 0 to 100. Tesseract runs as a command rather than through
+// a CGo binding such as gosseract, so the app still cross-compiles without
+// the Tesseract headers and libraries of every target.
+func recognizeImage(ctx context.Context, img image.Image, pre OCRPreprocess, charset OCRCharset, lang, tessdata, path string) (string, float64, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, ocrImage(img, pre)); err != nil {
+		return "", 0, err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return "", 0, err
+	}
+	// Page segmentation mode 6 reads the bitmap as one block of text; the TSV
+	// output carries the confidence of every word
+	args := []string{path, "stdout", "-l", lang, "--psm", "6"}
+	if tessdata != "" {
+		args = append(args, "--tessdata-dir", tessdata)
+	}
+	args = append(args, tesseractCharsetArgs(charset)...)
+	args = append(args, "tsv")
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "tesseract", args...)
+	cmd.Stderr = &stderr
+	// Bitmaps are recognized in parallel, one per core, so Tesseract's own
+	// threads would only compete with each other
+	cmd.Env = append(os.Environ(), "OMP_THREAD_LIMIT=1")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", 0, fmt.Errorf("Error running tesseract: %v\n%s", err, stderr.String())
+	}
+	text, confidence := parseTesseractTSV(string(output))
+	return text, confidence, nil
+}
+
+// parseTesseractTSV joins the words of Tesseract's TSV output into lines and
+// returns them with the mean confidence of the words
+func parseTesseractTSV(output string) (string, float64) {
+	var lines []string
+	var lineKey string
+	var total float64
+	words := 0
+	for _, row := range strings.Split(output, "\n") {
+		// level, page, block, paragraph, line, word, left, top, width, height, conf, text
+		fields := strings.Split(strings.TrimRight(row, "\r"), "\t")
+		if len(fields) < 12 || fields[0] != "5" {
+			continue
+		}
+		word := strings.TrimSpace(fields[11])
+		confidence, err := strconv.ParseFloat(fields[10], 64)
+		if word == "" || err != nil {
+			continue
+		}
+		if key := strings.Join(fields[2:5], "."); key != lineKey || len(lines) == 0 {
+			lineKey = key
+			lines = append(lines, word)
+		} else {
+			lines[len(lines)-1] += " " + word
+		}
+		total += confidence
+		words++
+	}
+	if words == 0 {
+		return "", 0
+	}
+	return strings.Join(lines, "\n"), total / float64(words)
+}
+
+// convertPGSToSRT recognizes the text of every subtitle of a PGS (.sup) file
+// with the OCR engine of opts and writes it as SRT, or reuses the cached
+// result of the same file. onProgress is called after every subtitle with
+// the number done and the total.
+func convertPGSToSRT(ctx context.Context, supPath, srtPath string, opts OCROptions, onProgress func(done, total int)) (OCRResult, error) {
+	data, err := os.ReadFile(supPath)
+	if err != nil {
+		return OCRResult{}, err
+	}
+	cues, err := parsePGSCues(data)
+	if err != nil && len(cues) == 0 {
+		return OCRResult{}, err
+	}
+	if len(cues) == 0 {
+		return OCRResult{}, fmt.Errorf("No subtitles found in %s", filepath.Base(supPath))
+	}
+	var cacheKey string
+	if opts.Cache {
+		// Without a key the result is just not cached
+		cacheKey, _ = ocrCacheKey(opts, supPath)
+	}
+	return ocrCuesToSRT(ctx, cues, srtPath, cacheKey, opts, onProgress)
+}
+
+// ocrCuesToSRT recognizes the text of subtitle bitmaps and writes it as SRT,
+// fixing common OCR errors first when opts.Correct is set and letting
+// opts.Review correct it when it is not nil. When ctx is cancelled and
+// opts.SavePartial is set, the subtitles recognized so far are written to
+// partialSRTPath, unreviewed, and returned with the error. The subtitles
+// written are cached under cacheKey unless it is empty; a result cached
+// earlier is written right away, without another review.
+func ocrCuesToSRT(ctx context.Context, cues []BitmapCue, srtPath, cacheKey string, opts OCROptions, onProgress func(done, total int)) (OCRResult, error) {
+	if cacheKey != "" {
+		if cached, empty, ok := loadOCRCache(cacheKey); ok {
+			onProgress(len(cues), len(cues))
+			result, err := writeOCRCues(srtPath, cached, empty)
+			result.Cached = true
+			return result, err
+		}
+	}
+
+	recognized, empty, fallback, err := recognizeCues(ctx, cues, opts, onProgress)
+	partial := err != nil && ctx.Err() != nil && opts.SavePartial && len(recognized) > 0
+	if err != nil && !partial {
+		return OCRResult{}, err
+	}
+	corrected := 0
+	if opts.Correct {
+		// The spell check of a cancelled run still runs on what it has
+		corrected = correctOCRCues(context.WithoutCancel(ctx), recognized, opts.Lang)
+	}
+	replaced := applyOCRRules(recognized, opts.Rules, opts.Lang)
+	if partial {
+		result, writeErr := writeOCRCues(partialSRTPath(srtPath), recognized, empty)
+		if writeErr != nil {
+			return result, writeErr
+		}
+		result.Corrected = corrected
+		result.Replaced = replaced
+		result.Fallback = fallback
+		result.PartialPath = partialSRTPath(srtPath)
+		return result, err
+	}
+	learned := 0
+	if opts.Review != nil {
+		original := make([]string, len(recognized))
+		for i, cue := range recognized {
+			original[i] = cue.Text
+		}
+		recognized = opts.Review(recognized)
+		if opts.LearnRules {
+			learned = learnOCRRules(original, recognized, opts.Lang)
+		}
+	}
+	result, err := writeOCRCues(srtPath, recognized, empty)
+	result.Corrected = corrected
+	result.Replaced = replaced
+	result.Learned = learned
+	result.Fallback = fallback
+	if err == nil && cacheKey != "" {
+		if cacheErr := saveOCRCache(cacheKey, recognized, empty); cacheErr != nil {
+			result.CacheError = cacheErr
+		}
+	}
+	return result, err
+}
+
+// partialSRTPath returns the file the subtitles recognized before an OCR
+// conversion was cancelled are written to, next to the SRT file it was to
+// write
+func partialSRTPath(srtPath string) string {
+	return strings.TrimSuffix(srtPath, filepath.Ext(srtPath)) + ".partial" + filepath.Ext(srtPath)
+}
+
+// recognizeCues recognizes the text of subtitle bitmaps with the OCR engine
+// of opts and its fallback engines, calling onProgress after every cue. Cues
+// without text are left out and counted. Italic lines and subtitles at the
+// top of the screen are tagged as set by opts. When ctx is cancelled, the
+// subtitles recognized so far are returned with its error.
+func recognizeCues(ctx context.Context, cues []BitmapCue, opts OCROptions, onProgress func(done, total int)) ([]OCRCue, int, ocrFallback, error) {
+	texts, confidences, fallback, err := recognizeWithFallback(ctx, cues, opts, onProgress)
+	if err != nil && ctx.Err() == nil {
+		return nil, 0, fallback, err
+	}
+
+	var recognized []OCRCue
+	empty := 0
+	for i, cue := range cues[:len(texts)] {
+		text, confidence := texts[i], confidences[i]
+		if text == "" {
+			empty++
+			continue
+		}
+		if opts.Italics {
+			text = italicize(text, italicLines(ocrImage(cue.Image, opts.Preprocess)))
+		}
+		if opts.Top && cue.Top {
+			text = `{\an8}` + text
+		}
+		// Fades and other palette updates repeat a subtitle in back-to-back
+		// cues; those are written once
+		if n := len(recognized); n > 0 && recognized[n-1].Text == text && cue.Start <= recognized[n-1].End {
+			recognized[n-1].End = cue.End
+			recognized[n-1].Confidence = max(recognized[n-1].Confidence, confidence)
+			continue
+		}
+		recognized = append(recognized, OCRCue{BitmapCue: cue, Text: text, Confidence: confidence})
+	}
+	return recognized, empty, fallback, err
+}
+
+// writeOCRCues writes recognized subtitles as SRT, leaving out those whose
+// text was cleared, and their accuracy report next to the SRT file. empty is
+// how many subtitle bitmaps no text was read from.
+func writeOCRCues(srtPath string, cues []OCRCue, empty int) (OCRResult, error) {
+	var result OCRResult
+	var b strings.Builder
+	report := ocrReport{Empty: empty}
+	for _, cue := range cues {
+		text := strings.TrimSpace(cue.Text)
+		if text == "" {
+			continue
+		}
+		result.Cues++
+		timing := formatSRTTime(cue.Start) + " --> " + formatSRTTime(cue.End)
+		fmt.Fprintf(&b, "%d\n%s\n%s\n\n", result.Cues, timing, text)
+		report.add(result.Cues, timing, text, cue.Confidence)
+	}
+	if err := os.WriteFile(srtPath, []byte(b.String()), 0644); err != nil {
+		return result, err
+	}
+
+	result.MeanConfidence = report.MeanConfidence
+	result.LowConfidence = len(report.LowConfidence)
+	result.Empty = empty
+	result.Suspicious = report.suspiciousCount()
+	reportPath, err := writeOCRReport(srtPath, report)
+	if err != nil {
+		return result, err
+	}
+	result.ReportPath = reportPath
+	return result, nil
+}
+
+// formatSRTTime writes a timestamp as HH:MM:SS,mmm
+func formatSRTTime(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
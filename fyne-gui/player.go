@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// videoPreviewCommand builds an mpv or ffplay command that plays mkvPath with a
+// subtitle shown. An extracted file of the track is preferred, so OCR results
+// can be checked; otherwise the embedded track is selected by subIndex, its
+// position among the subtitle tracks of the file.
+func videoPreviewCommand(mkvPath string, t *TrackItem, subIndex int) (*exec.Cmd, error) {
+	subFile := ""
+	if t.OutputPath != "" {
+		if _, err := os.Stat(t.OutputPath); err == nil {
+			subFile = t.OutputPath
+		}
+	}
+
+	if _, err := exec.LookPath("mpv"); err == nil {
+		if subFile != "" {
+			return exec.Command("mpv", "--sub-file="+subFile, mkvPath), nil
+		}
+		return exec.Command("mpv", fmt.Sprintf("--sid=%d", subIndex+1), mkvPath), nil
+	}
+
+	if _, err := exec.LookPath("ffplay"); err == nil {
+		// The subtitles filter renders text subtitles only
+		ext := strings.ToLower(filepath.Ext(subFile))
+		if subFile != "" && ext != ".sup" && ext != ".idx" {
+			return exec.Command("ffplay", "-vf", "subtitles="+escapeFilterValue(subFile), mkvPath), nil
+		}
+		if subFile == "" && textSubtitleExt(t.Codec) != "" {
+			return exec.Command("ffplay", "-vf", fmt.Sprintf("subtitles=%s:si=%d", escapeFilterValue(mkvPath), subIndex), mkvPath), nil
+		}
+		return nil, errors.New(tr("ffplay can only show text subtitles. Install mpv to preview image-based subtitles."))
+	}
+
+	return nil, errors.New(tr("No video player found. Install mpv or ffplay (part of FFmpeg) to preview subtitles with video."))
+}
+
+// escapeFilterValue escapes a file path for use as an FFmpeg filter option,
+// first for the option value and then for the filtergraph
+func escapeFilterValue(value string) string {
+	for _, c := range []string{`\`, `'`, `:`} {
+		value = strings.ReplaceAll(value, c, `\`+c)
+	}
+	for _, c := range []string{`\`, `'`, `[`, `]`, `,`, `;`} {
+		value = strings.ReplaceAll(value, c, `\`+c)
+	}
+	return value
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// Conversion settings of a preset
+const (
+	PresetConvertDefault  = ""         // Use the default conversion of each codec
+	PresetConvertSRT      = "srt"      // Convert every convertible track to SRT
+	PresetConvertOriginal = "original" // Keep every track in its original format
+)
+
+// Preset pre-configures the track selection and conversion options of newly
+// loaded tracks, e.g. "English SRT only"
+type Preset struct {
+	Name       string   `json:"name"`
+	Languages  []string `json:"languages,omitempty"` // Languages of the tracks to select, all when empty
+	ForcedOnly bool     `json:"forced_only,omitempty"`
+	Conversion string   `json:"conversion,omitempty"`
+}
+
+// builtinPresets are offered until the user saves presets of their own
+var builtinPresets = []Preset{
+	{Name: "English SRT only", Languages: []string{"eng", "en"}, Conversion: PresetConvertSRT},
+	{Name: "All forced tracks", ForcedOnly: true},
+	{Name: "Anime: full subs + signs", Languages: []string{"eng", "en"}, Conversion: PresetConvertOriginal},
+}
+
+// loadPresets reads the saved presets, falling back to the built-in ones
+func loadPresets(prefs fyne.Preferences) []Preset {
+	data := prefs.String("extraction_presets")
+	if data == "" {
+		return append([]Preset(nil), builtinPresets...)
+	}
+	var presets []Preset
+	if err := json.Unmarshal([]byte(data), &presets); err != nil {
+		fyne.LogError("Error loading extraction presets", err)
+		return append([]Preset(nil), builtinPresets...)
+	}
+	return presets
+}
+
+// savePresets stores the presets in the preferences
+func savePresets(prefs fyne.Preferences, presets []Preset) {
+	data, err := json.Marshal(presets)
+	if err != nil {
+		fyne.LogError("Error saving extraction presets", err)
+		return
+	}
+	prefs.SetString("extraction_presets", string(data))
+}
+
+// findPreset returns the preset with the given name, or nil
+func findPreset(presets []Preset, name string) *Preset {
+	for i := range presets {
+		if presets[i].Name == name {
+			return &presets[i]
+		}
+	}
+	return nil
+}
+
+// activePreset returns the preset applied when tracks load, or nil for none
+func activePreset(prefs fyne.Preferences) *Preset {
+	name := prefs.String("active_preset")
+	if name == "" {
+		return nil
+	}
+	return findPreset(loadPresets(prefs), name)
+}
+
+// matches reports whether the preset selects a track
+func (p *Preset) matches(t *TrackItem) bool {
+	if p.ForcedOnly && !t.Forced {
+		return false
+	}
+	if len(p.Languages) == 0 {
+		return true
+	}
+	for _, lang := range p.Languages {
+		if strings.EqualFold(lang, t.Lang) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply checks the tracks the preset selects, unchecks the others and sets
+// their conversion options
+func (p *Preset) apply(items []*TrackItem) {
+	for _, t := range items {
+		t.Check.SetChecked(p.matches(t))
+		if t.ConvertOCR == nil {
+			continue
+		}
+		switch p.Conversion {
+		case PresetConvertSRT:
+			t.ConvertOCR.SetChecked(true)
+		case PresetConvertOriginal:
+			t.ConvertOCR.SetChecked(false)
+		}
+	}
+}
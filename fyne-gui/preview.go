@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// previewCueCount is the number of cues shown in the subtitle preview
+const previewCueCount = 20
+
+var assOverridePattern = regexp.MustCompile(`\{[^}]*\}`)
+
+// textSubtitleExt returns the file extension of a text subtitle codec, or an
+// empty string for image-based codecs that cannot be previewed
+func textSubtitleExt(codec string) string {
+	c := strings.ToLower(codec)
+	switch {
+	case strings.Contains(c, "subrip") || strings.Contains(c, "srt"):
+		return "srt"
+	case strings.Contains(c, "webvtt"):
+		return "vtt"
+	case c == "ass" || c == "ssa" || strings.Contains(c, "substation") || strings.Contains(c, "sub station"):
+		return "ass"
+	}
+	return ""
+}
+
+// trackPreview is what the preview pane shows for a track: the first cues of a
+// text track, or the first bitmaps of an image-based track
+type trackPreview struct {
+	Text   string
+	Images []image.Image
+}
+
+// loadTrackPreview extracts a subtitle track to a temporary file and returns
+// its first cues or bitmaps
+func loadTrackPreview(mkvPath string, t *TrackItem) (*trackPreview, error) {
+	ext := textSubtitleExt(t.Codec)
+	if ext == "" {
+		ext = imageSubtitleExt(t.Codec)
+	}
+	if ext == "" {
+		return nil, fmt.Errorf("Track %d (%s) cannot be previewed.", t.Num, t.Codec)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "subtitle-preview")
+	if err != nil {
+		return nil, fmt.Errorf("Error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpFile := filepath.Join(tmpDir, fmt.Sprintf("track%d.%s", t.Num, ext))
+	if ext == "ts" {
+		if output, err := extractDVBSubtitles(context.Background(), mkvPath, t.Num, tmpFile); err != nil {
+			return nil, fmt.Errorf("%v\n%s", err, output)
+		}
+	} else {
+		cmd := exec.Command("mkvextract", "tracks", mkvPath, fmt.Sprintf("%d:%s", t.Num, tmpFile))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("Error running mkvextract: %v\n%s", err, output)
+		}
+	}
+
+	var images []image.Image
+	switch ext {
+	case "sup":
+		data, err := os.ReadFile(tmpFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading extracted track: %v", err)
+		}
+		images, err = decodePGSImages(data, previewImageCount)
+		if err != nil && len(images) == 0 {
+			return nil, err
+		}
+	case "idx":
+		images, err = decodeVobSubImages(tmpFile, previewImageCount)
+		if err != nil {
+			return nil, err
+		}
+	case "ts":
+		images, err = decodeDVBImages(tmpFile, previewImageCount)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		data, err := os.ReadFile(tmpFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading extracted track: %v", err)
+		}
+		text := strings.ReplaceAll(strings.TrimPrefix(string(data), "\ufeff"), "\r\n", "\n")
+		if ext == "ass" {
+			return &trackPreview{Text: assPreviewCues(text, previewCueCount)}, nil
+		}
+		return &trackPreview{Text: textPreviewCues(text, previewCueCount)}, nil
+	}
+
+	if len(images) == 0 {
+		return &trackPreview{Text: fmt.Sprintf("No subtitle images found in track %d.", t.Num)}, nil
+	}
+	preview := &trackPreview{Text: fmt.Sprintf("First %d subtitle images of track %d:", len(images), t.Num)}
+	for _, img := range images {
+		preview.Images = append(preview.Images, onDarkBackground(img))
+	}
+	return preview, nil
+}
+
+// textPreviewCues returns the first cues of an SRT or WebVTT file, without cue numbers
+func textPreviewCues(text string, count int) string {
+	var cues []string
+	for _, block := range strings.Split(text, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		// Skip the cue number or identifier before the timing line
+		for len(lines) > 0 && !strings.Contains(lines[0], "-->") {
+			lines = lines[1:]
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		cues = append(cues, strings.Join(lines, "\n"))
+		if len(cues) == count {
+			break
+		}
+	}
+	return strings.Join(cues, "\n\n")
+}
+
+// assPreviewCues returns the first dialogue lines of an ASS/SSA file, without
+// style overrides
+func assPreviewCues(text string, count int) string {
+	var cues []string
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.HasPrefix(line, "Dialogue:") {
+			continue
+		}
+		// Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+		fields := strings.SplitN(strings.TrimPrefix(line, "Dialogue:"), ",", 10)
+		if len(fields) < 10 {
+			continue
+		}
+		cueText := assOverridePattern.ReplaceAllString(fields[9], "")
+		cueText = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(cueText)
+		cues = append(cues, fmt.Sprintf("%s --> %s\n%s", strings.TrimSpace(fields[1]), strings.TrimSpace(fields[2]), cueText))
+		if len(cues) == count {
+			break
+		}
+	}
+	return strings.Join(cues, "\n\n")
+}
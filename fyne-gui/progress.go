@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// mkvProgressPattern matches the progress that MKVToolNix tools print while working
+var mkvProgressPattern = regexp.MustCompile(`(?:Progress: |#GUI#progress )(\d+)%`)
+
+// runWithProgress runs an MKVToolNix command like CombinedOutput, calling
+// onProgress with each percentage the command prints
+func runWithProgress(cmd *exec.Cmd, onProgress func(percent int)) ([]byte, error) {
+	var output bytes.Buffer
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(reader)
+		scanner.Split(scanLinesOrReturns)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if matches := mkvProgressPattern.FindStringSubmatch(line); matches != nil {
+				percent, _ := strconv.Atoi(matches[1])
+				onProgress(percent)
+				continue
+			}
+			output.WriteString(line + "\n")
+		}
+		// Keep draining so the command never blocks on a line too long to scan
+		io.Copy(io.Discard, reader)
+	}()
+
+	err := cmd.Wait()
+	writer.Close()
+	<-done
+	return output.Bytes(), err
+}
+
+// scanLinesOrReturns splits output on newlines and on the carriage returns
+// that progress lines end with
+func scanLinesOrReturns(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// runEstimate estimates the time left in an extraction run from the
+// durations of the tracks completed so far
+type runEstimate struct {
+	mu          sync.Mutex
+	totalTracks int
+	doneTracks  int
+	elapsed     time.Duration
+}
+
+// Reset starts estimating a new run of tracks
+func (e *runEstimate) Reset(tracks int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.totalTracks = tracks
+	e.doneTracks = 0
+	e.elapsed = 0
+}
+
+// AddTracks adds tracks whose count became known while the run was going
+func (e *runEstimate) AddTracks(tracks int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.totalTracks += tracks
+}
+
+// TrackDone records the duration of a completed, failed or cancelled track
+func (e *runEstimate) TrackDone(d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.doneTracks++
+	e.elapsed += d
+}
+
+// Describe reports the overall position and remaining time, given how far
+// the current track has come
+func (e *runEstimate) Describe(currentFraction float64) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	total := max(e.totalTracks, e.doneTracks+1)
+	if e.doneTracks == 0 {
+		return fmt.Sprintf("Overall: track 1 of %d, estimating time remaining...", total)
+	}
+	average := e.elapsed / time.Duration(e.doneTracks)
+	remaining := time.Duration((float64(total-e.doneTracks) - currentFraction) * float64(average))
+	return fmt.Sprintf("Overall: track %d of %d, about %s remaining", e.doneTracks+1, total, max(remaining, 0).Round(time.Second))
+}
+
+// percentRemaining estimates the time left in a single command from how long
+// it has taken to reach percent
+func percentRemaining(start time.Time, percent int) string {
+	if percent <= 0 {
+		return "estimating time remaining..."
+	}
+	if percent >= 100 {
+		return "finishing..."
+	}
+	elapsed := time.Since(start)
+	remaining := time.Duration(float64(elapsed) * float64(100-percent) / float64(percent))
+	return fmt.Sprintf("about %s remaining", remaining.Round(time.Second))
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TrackProps are the track properties mkvpropedit can change in place
+type TrackProps struct {
+	Lang    string
+	Name    string
+	Default bool
+	Forced  bool
+}
+
+// EditTrack is a track in the Edit Tracks tab, with its properties as loaded
+// and as edited
+type EditTrack struct {
+	ID       int
+	Number   int // Track number in the Matroska file, which mkvpropedit addresses
+	Type     string
+	Codec    string
+	Props    TrackProps
+	Original TrackProps
+	Remove   bool // Drop the subtitle track when the file is stripped
+}
+
+// loadEditTracks reads every track of an MKV file with mkvmerge
+func loadEditTracks(mkvPath string) ([]*EditTrack, error) {
+	output, err := exec.Command("mkvmerge", "-J", mkvPath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("Error running mkvmerge: %v", err)
+	}
+	var info struct {
+		Tracks []struct {
+			ID         int    `json:"id"`
+			Type       string `json:"type"`
+			Codec      string `json:"codec"`
+			Properties struct {
+				Number   int    `json:"number"`
+				Language string `json:"language"`
+				Name     string `json:"track_name"`
+				Default  bool   `json:"default_track"`
+				Forced   bool   `json:"forced_track"`
+			} `json:"properties"`
+		} `json:"tracks"`
+	}
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, fmt.Errorf("Error parsing mkvmerge output: %v", err)
+	}
+
+	var tracks []*EditTrack
+	for _, t := range info.Tracks {
+		props := TrackProps{
+			Lang:    t.Properties.Language,
+			Name:    t.Properties.Name,
+			Default: t.Properties.Default,
+			Forced:  t.Properties.Forced,
+		}
+		tracks = append(tracks, &EditTrack{
+			ID:       t.ID,
+			Number:   t.Properties.Number,
+			Type:     t.Type,
+			Codec:    t.Codec,
+			Props:    props,
+			Original: props,
+		})
+	}
+	return tracks, nil
+}
+
+// changedTracks returns the tracks whose properties were edited
+func changedTracks(tracks []*EditTrack) []*EditTrack {
+	var changed []*EditTrack
+	for _, t := range tracks {
+		if t.Props != t.Original {
+			changed = append(changed, t)
+		}
+	}
+	return changed
+}
+
+// propEditArgs builds the mkvpropedit arguments that write the edited
+// properties of the tracks to mkvPath, or nil when nothing changed
+func propEditArgs(mkvPath string, tracks []*EditTrack) []string {
+	changed := changedTracks(tracks)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	flag := func(value bool) string {
+		if value {
+			return "1"
+		}
+		return "0"
+	}
+	args := []string{mkvPath}
+	for _, t := range changed {
+		args = append(args, "--edit", "track:@"+strconv.Itoa(t.Number))
+		if t.Props.Lang != t.Original.Lang {
+			args = append(args, "--set", "language="+t.Props.Lang)
+		}
+		if t.Props.Name != t.Original.Name {
+			if t.Props.Name == "" {
+				args = append(args, "--delete", "name")
+			} else {
+				args = append(args, "--set", "name="+t.Props.Name)
+			}
+		}
+		if t.Props.Default != t.Original.Default {
+			args = append(args, "--set", "flag-default="+flag(t.Props.Default))
+		}
+		if t.Props.Forced != t.Original.Forced {
+			args = append(args, "--set", "flag-forced="+flag(t.Props.Forced))
+		}
+	}
+	return args
+}
+
+// stripOutputPath returns the file a strip of mkvPath writes, like the
+// strip subcommand of the command line version
+func stripOutputPath(mkvPath string) string {
+	return strings.TrimSuffix(mkvPath, filepath.Ext(mkvPath)) + "_stripped.mkv"
+}
+
+// stripTracksArgs builds the mkvmerge arguments that remux mkvPath without
+// the subtitle tracks marked for removal, or nil when none are marked
+func stripTracksArgs(mkvPath, outputPath string, tracks []*EditTrack) []string {
+	var kept []string
+	removed := 0
+	for _, t := range tracks {
+		if t.Type != "subtitles" {
+			continue
+		}
+		if t.Remove {
+			removed++
+			continue
+		}
+		kept = append(kept, strconv.Itoa(t.ID))
+	}
+	if removed == 0 {
+		return nil
+	}
+
+	args := []string{"-o", outputPath}
+	if len(kept) == 0 {
+		args = append(args, "--no-subtitles")
+	} else {
+		args = append(args, "--subtitle-tracks", strings.Join(kept, ","))
+	}
+	return append(args, mkvPath)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// QueueItem is an MKV file in the extraction queue. Tracks stays nil until the
+// file's tracks are loaded, so every file keeps its own track selection.
+type QueueItem struct {
+	Path   string
+	Tracks []*TrackItem
+	State  string
+}
+
+// selectedCount returns how many of the item's tracks are checked
+func (item *QueueItem) selectedCount() int {
+	return selectedTrackCount(item.Tracks)
+}
+
+// doneCount returns how many of the item's checked tracks were extracted
+func (item *QueueItem) doneCount() int {
+	done := 0
+	for _, t := range item.Tracks {
+		if t.Check.Checked && t.State == "Done" {
+			done++
+		}
+	}
+	return done
+}
+
+// failedTracks returns the checked tracks that were not extracted, leaving
+// out tracks that were skipped on purpose
+func (item *QueueItem) failedTracks() []*TrackItem {
+	var failed []*TrackItem
+	for _, t := range item.Tracks {
+		if t.Check.Checked && t.State != "Done" && t.State != "Skipped" {
+			failed = append(failed, t)
+		}
+	}
+	return failed
+}
+
+// finishedState derives the queue state of a processed file from its tracks
+func (item *QueueItem) finishedState() string {
+	for _, t := range item.Tracks {
+		if t.Check.Checked && t.State != "Done" {
+			return "Error"
+		}
+	}
+	return "Done"
+}
+
+func findQueueItem(queue []*QueueItem, path string) *QueueItem {
+	for _, item := range queue {
+		if item.Path == path {
+			return item
+		}
+	}
+	return nil
+}
+
+func removeQueueItem(queue []*QueueItem, item *QueueItem) []*QueueItem {
+	for i, queued := range queue {
+		if queued == item {
+			return append(queue[:i], queue[i+1:]...)
+		}
+	}
+	return queue
+}
+
+// findMKVFiles scans dir recursively for MKV files, in lexical order.
+// Unreadable subdirectories are skipped.
+func findMKVFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && p != dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() && strings.ToLower(filepath.Ext(p)) == ".mkv" {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// pauseGate holds workers back between tracks and files while a run is paused
+type pauseGate struct {
+	mu     sync.Mutex
+	resume chan struct{}
+}
+
+func (g *pauseGate) Pause() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.resume == nil {
+		g.resume = make(chan struct{})
+	}
+}
+
+func (g *pauseGate) Resume() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.resume != nil {
+		close(g.resume)
+		g.resume = nil
+	}
+}
+
+func (g *pauseGate) Paused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.resume != nil
+}
+
+// Wait blocks while the gate is paused or until ctx is cancelled
+func (g *pauseGate) Wait(ctx context.Context) {
+	g.mu.Lock()
+	resume := g.resume
+	g.mu.Unlock()
+	if resume == nil {
+		return
+	}
+	select {
+	case <-resume:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// roundtripOutputPath returns the file a corrected track is muxed into
+func roundtripOutputPath(mkvPath string) string {
+	return strings.TrimSuffix(mkvPath, filepath.Ext(mkvPath)) + "_corrected.mkv"
+}
+
+// extractRoundtripTrack extracts a text subtitle track into dir for editing
+// and returns the path of the extracted file
+func extractRoundtripTrack(mkvPath string, t *EditTrack, dir string) (string, error) {
+	ext := textSubtitleExt(t.Codec)
+	if ext == "" {
+		return "", fmt.Errorf("Track %d (%s) is not a text subtitle track", t.ID, t.Codec)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("track%d.%s", t.ID, ext))
+	output, err := exec.Command("mkvextract", mkvPath, "tracks", fmt.Sprintf("%d:%s", t.ID, path)).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("Error running mkvextract: %v\n%s", err, output)
+	}
+	return path, nil
+}
+
+// roundtripPlan plans muxing the edited file of a track back into mkvPath in
+// place of the track, at its position and with its language, name and flags
+func roundtripPlan(mkvPath, outputPath string, t *EditTrack, editedPath string) (*InsertPlan, error) {
+	tracks, err := mkvTracks(mkvPath)
+	if err != nil {
+		return nil, err
+	}
+	sub := &InsertSubtitle{Path: editedPath, Lang: t.Props.Lang, Name: t.Props.Name, Default: t.Props.Default, Forced: t.Props.Forced}
+	found := false
+	for i, existing := range tracks {
+		if existing.ID == t.ID {
+			tracks[i] = MuxTrack{
+				File:    1,
+				Type:    "subtitles",
+				Codec:   existing.Codec,
+				Lang:    sub.Lang,
+				Name:    sub.Name,
+				Default: sub.Default,
+				Forced:  sub.Forced,
+				Source:  filepath.Base(editedPath),
+			}
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("Track %d is no longer in %s", t.ID, filepath.Base(mkvPath))
+	}
+	return &InsertPlan{
+		Tracks:   tracks,
+		Replaced: []int{t.ID},
+		Args:     insertSubtitlesArgs(mkvPath, outputPath, []*InsertSubtitle{sub}, InsertOptions{}, []int{t.ID}, trackOrderArg(tracks)),
+	}, nil
+}
+
+// showRoundtripWizard guides through correcting a text subtitle track: it is
+// extracted, edited in place or in an external editor, and muxed back in
+// place of the original track. onDone is called with the file written.
+func showRoundtripWizard(w fyne.Window, mkvPath string, t *EditTrack, onDone func(outputPath string)) {
+	dir, err := os.MkdirTemp("", "roundtrip_*")
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+
+	stepLabel := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	body := container.NewStack()
+	backBtn := widget.NewButton(tr("Back"), nil)
+	nextBtn := widget.NewButton(tr("Next"), nil)
+	nextBtn.Importance = widget.HighImportance
+	var d *dialog.CustomDialog
+	closeBtn := widget.NewButton(tr("Close"), func() {
+		d.Hide()
+	})
+	d = dialog.NewCustomWithoutButtons(trf("Correct Track %d", t.ID), container.NewBorder(stepLabel, nil, nil, nil, body), w)
+	d.SetButtons([]fyne.CanvasObject{closeBtn, backBtn, nextBtn})
+	d.SetOnClosed(func() {
+		os.RemoveAll(dir)
+	})
+	d.Resize(fyne.NewSize(800, 600))
+
+	setStep := func(step int, title string, content fyne.CanvasObject) {
+		stepLabel.SetText(trf("Step %d of 3: %s", step, title))
+		body.Objects = []fyne.CanvasObject{content}
+		body.Refresh()
+	}
+
+	// Step 1: extract the track
+	extractLabel := widget.NewLabel(trf("Extracting track %d (%s, %s)...", t.ID, t.Props.Lang, t.Codec))
+	extractLabel.Wrapping = fyne.TextWrapWord
+	setStep(1, tr("Extract"), container.NewVBox(extractLabel, widget.NewProgressBarInfinite()))
+	backBtn.Disable()
+	nextBtn.Disable()
+	d.Show()
+
+	var editedPath string
+	editor := widget.NewMultiLineEntry()
+	editor.TextStyle = fyne.TextStyle{Monospace: true}
+	editor.Wrapping = fyne.TextWrapOff
+	editorNote := widget.NewLabel("")
+	editorNote.Wrapping = fyne.TextWrapWord
+
+	loadEditor := func() {
+		data, err := os.ReadFile(editedPath)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		editor.SetText(string(data))
+		editor.Enable()
+		editorNote.SetText(tr("Correct the subtitles below, or open them in an external editor."))
+	}
+	externalBtn := widget.NewButton(tr("Open in External Editor"), func() {
+		u, err := url.Parse(storage.NewFileURI(editedPath).String())
+		if err == nil {
+			err = fyne.CurrentApp().OpenURL(u)
+		}
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		// The file is now edited elsewhere; the text here would overwrite it
+		editor.Disable()
+		editorNote.SetText(tr("Save your changes in the external editor, then click Reload."))
+	})
+	reloadBtn := widget.NewButton(tr("Reload"), loadEditor)
+	editStep := container.NewBorder(
+		container.NewVBox(editorNote, container.NewHBox(externalBtn, reloadBtn)),
+		nil, nil, nil,
+		editor,
+	)
+
+	// Step 3: mux the corrected file back
+	outputPath := roundtripOutputPath(mkvPath)
+	summaryLabel := widget.NewLabel("")
+	summaryLabel.Wrapping = fyne.TextWrapWord
+	replaceCheck := widget.NewCheck(tr("Replace the original file once the output is verified"), nil)
+	progress := widget.NewProgressBar()
+	progress.Hide()
+	resultLabel := widget.NewLabel("")
+	resultLabel.Wrapping = fyne.TextWrapWord
+	insertStep := container.NewVBox(summaryLabel, replaceCheck, progress, resultLabel)
+
+	var showEdit, showInsert func()
+	showEdit = func() {
+		setStep(2, tr("Edit"), editStep)
+		backBtn.Disable()
+		nextBtn.SetText(tr("Next"))
+		nextBtn.Enable()
+		nextBtn.OnTapped = func() {
+			// Text edited here is saved; a file edited externally is used as it is
+			if !editor.Disabled() {
+				if err := os.WriteFile(editedPath, []byte(editor.Text), 0644); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+			}
+			showInsert()
+		}
+	}
+	showInsert = func() {
+		summaryLabel.SetText(trf("Track %d (%s, %s) of %s will be replaced with the corrected subtitles, keeping its position, name and flags. The output is written to %s.",
+			t.ID, t.Props.Lang, t.Codec, filepath.Base(mkvPath), filepath.Base(outputPath)))
+		resultLabel.SetText("")
+		setStep(3, tr("Re-insert"), insertStep)
+		backBtn.Enable()
+		backBtn.OnTapped = showEdit
+		nextBtn.SetText(tr("Re-insert"))
+		nextBtn.OnTapped = func() {
+			backBtn.Disable()
+			nextBtn.Disable()
+			replaceCheck.Disable()
+			progress.SetValue(0)
+			progress.Show()
+			opts := InsertOptions{KeepAttributes: replaceCheck.Checked, ReplaceOriginal: replaceCheck.Checked}
+			go func() {
+				plan, err := roundtripPlan(mkvPath, outputPath, t, editedPath)
+				var output []byte
+				if err == nil {
+					start := time.Now()
+					output, err = runWithProgress(exec.Command("mkvmerge", plan.Args...), func(percent int) {
+						fyne.Do(func() {
+							progress.SetValue(float64(percent) / 100)
+							resultLabel.SetText(fmt.Sprintf("%d%%, %s", percent, percentRemaining(start, percent)))
+						})
+					})
+					err = mkvmergeError(err)
+				}
+				var mismatches []string
+				var verifyErr, finishErr error
+				finalPath := outputPath
+				if err == nil {
+					mismatches, verifyErr = verifyInsert(outputPath, plan)
+					finalPath, finishErr = finishInsert(mkvPath, outputPath, opts, verifyErr == nil && len(mismatches) == 0)
+				}
+				fyne.Do(func() {
+					progress.Hide()
+					if err != nil {
+						resultLabel.SetText(trf("Error: %v", err) + "\n" + string(output))
+						backBtn.Enable()
+						nextBtn.Enable()
+						replaceCheck.Enable()
+						return
+					}
+					text := trf("Corrected file written to %s", finalPath)
+					switch {
+					case verifyErr != nil:
+						text += "\n" + trf("Could not verify the output: %v", verifyErr)
+					case len(mismatches) > 0:
+						text += "\n" + tr("The output does not match the planned tracks:") + "\n- " + strings.Join(mismatches, "\n- ")
+					}
+					if finishErr != nil {
+						text += "\n" + finishErr.Error()
+					}
+					resultLabel.SetText(text)
+					onDone(finalPath)
+				})
+			}()
+		}
+	}
+
+	go func() {
+		path, err := extractRoundtripTrack(mkvPath, t, dir)
+		fyne.Do(func() {
+			if err != nil {
+				extractLabel.SetText(err.Error())
+				setStep(1, tr("Extract"), extractLabel)
+				return
+			}
+			editedPath = path
+			loadEditor()
+			showEdit()
+		})
+	}()
+}
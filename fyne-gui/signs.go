@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Contents of a subtitle track, shown in the Content column
+const (
+	trackContentDialogue = "Dialogue"
+	trackContentSigns    = "Signs & Songs"
+)
+
+// signsTrackNameRegex matches the names anime releases give their signs and
+// songs tracks, e.g. "Signs & Songs", "Signs/Songs" or "S&S"
+var signsTrackNameRegex = regexp.MustCompile(`(?i)\bsigns?\b|\bsongs?\b|\bs\s*&\s*s\b|\blyrics\b|\bkaraoke\b`)
+
+// dialogueTrackNameRegex matches the names of full dialogue tracks, e.g.
+// "Full Subtitles" or "Dialogue"
+var dialogueTrackNameRegex = regexp.MustCompile(`(?i)\bfull\b|\bdialog(ue)?s?\b`)
+
+// trackContentFromName returns the content a track's name tells, or ""
+func trackContentFromName(name string) string {
+	switch {
+	case signsTrackNameRegex.MatchString(name):
+		return trackContentSigns
+	case dialogueTrackNameRegex.MatchString(name):
+		return trackContentDialogue
+	}
+	return ""
+}
+
+// assPositionRegex matches the override tags that place an ASS line away
+// from the bottom of the screen: explicit positions and top or middle
+// alignments
+var assPositionRegex = regexp.MustCompile(`\\(pos|move)\(|\\an[4-9]|\\a([5-7]|9|1[01])\b`)
+
+// Thresholds of classifyTrackContent
+const (
+	signsPositionedShare = 0.4  // Share of positioned cues above which a track holds signs
+	signsCueShare        = 0.4  // Share of the cues of the fullest track of its language below which a track holds signs
+	signsCoverage        = 0.15 // Share of the subtitle span shown on screen below which a track holds signs
+	signsMinSpan         = 5 * time.Minute
+)
+
+// subtitleCueStats sums up the cues of a subtitle track for telling dialogue
+// from signs and songs
+type subtitleCueStats struct {
+	Cues       int
+	Positioned int           // Cues shown away from the bottom of the screen
+	Shown      time.Duration // Total time cues are shown
+	First      time.Duration // Start of the first cue
+	Last       time.Duration // End of the last cue
+}
+
+// add counts a cue
+func (s *subtitleCueStats) add(start, end time.Duration, positioned bool) {
+	s.Cues++
+	if positioned {
+		s.Positioned++
+	}
+	if end > start {
+		s.Shown += end - start
+	}
+	if s.Cues == 1 || start < s.First {
+		s.First = start
+	}
+	s.Last = max(s.Last, end)
+}
+
+// span returns the time from the first cue to the end of the last
+func (s subtitleCueStats) span() time.Duration {
+	return s.Last - s.First
+}
+
+// assCueStats reads the dialogue lines of an ASS/SSA file. Lines are
+// positioned by their override tags or by a style aligned to the top or
+// middle of the screen.
+func assCueStats(text string) subtitleCueStats {
+	var stats subtitleCueStats
+	alignColumn := -1
+	styleAligned := map[string]bool{} // Styles placed away from the bottom
+	section := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "["):
+			section = strings.ToLower(line)
+		case strings.HasPrefix(line, "Format:") && strings.Contains(section, "styles"):
+			for i, column := range strings.Split(strings.TrimPrefix(line, "Format:"), ",") {
+				if strings.EqualFold(strings.TrimSpace(column), "Alignment") {
+					alignColumn = i
+				}
+			}
+		case strings.HasPrefix(line, "Style:") && alignColumn >= 0:
+			columns := strings.Split(strings.TrimPrefix(line, "Style:"), ",")
+			if alignColumn < len(columns) {
+				// Alignments 1 to 3 are bottom left, center and right, in
+				// both the numpad layout of ASS and the legacy one of SSA
+				align, err := strconv.Atoi(strings.TrimSpace(columns[alignColumn]))
+				styleAligned[strings.TrimSpace(columns[0])] = err == nil && align > 3
+			}
+		case strings.HasPrefix(line, "Dialogue:"):
+			// Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+			fields := strings.SplitN(strings.TrimPrefix(line, "Dialogue:"), ",", 10)
+			if len(fields) < 10 {
+				continue
+			}
+			start, err := parseChapterTime(fields[1])
+			if err != nil {
+				continue
+			}
+			end, err := parseChapterTime(fields[2])
+			if err != nil {
+				continue
+			}
+			positioned := styleAligned[strings.TrimSpace(fields[3])] || assPositionRegex.MatchString(fields[9])
+			stats.add(start, end, positioned)
+		}
+	}
+	return stats
+}
+
+// bitmapCueStats sums up the cues of an image-based track; those shown at
+// the top of the screen are positioned
+func bitmapCueStats(cues []BitmapCue) subtitleCueStats {
+	var stats subtitleCueStats
+	for _, cue := range cues {
+		stats.add(cue.Start, cue.End, cue.Top)
+	}
+	return stats
+}
+
+// classifyTrackContent tells a signs and songs track from a full dialogue
+// track by its cues: most of them placed around the screen, far fewer cues
+// than the fullest track of the same language (maxCues), or cues shown only
+// now and then. It returns "" for a track without cues.
+func classifyTrackContent(stats subtitleCueStats, maxCues int) string {
+	if stats.Cues == 0 {
+		return ""
+	}
+	switch {
+	case float64(stats.Positioned) >= signsPositionedShare*float64(stats.Cues):
+		return trackContentSigns
+	case float64(stats.Cues) < signsCueShare*float64(maxCues):
+		return trackContentSigns
+	case stats.span() >= signsMinSpan && float64(stats.Shown) < signsCoverage*float64(stats.span()):
+		return trackContentSigns
+	}
+	return trackContentDialogue
+}
+
+// contentAnalysisTracks returns the PGS and ASS/SSA tracks whose content is
+// worth analyzing: those sharing their language with another such track,
+// where it matters which one is read
+func contentAnalysisTracks(items []*TrackItem) []*TrackItem {
+	perLanguage := map[string]int{}
+	for _, t := range items {
+		if analyzableTrack(t) {
+			perLanguage[t.Lang]++
+		}
+	}
+	var tracks []*TrackItem
+	for _, t := range items {
+		if analyzableTrack(t) && perLanguage[t.Lang] > 1 {
+			tracks = append(tracks, t)
+		}
+	}
+	return tracks
+}
+
+// analyzableTrack reports whether the content of a track can be analyzed
+func analyzableTrack(t *TrackItem) bool {
+	return imageSubtitleExt(t.Codec) == "sup" || textSubtitleExt(t.Codec) == "ass"
+}
+
+// analyzeTrackContent extracts tracks with one mkvextract run and returns
+// the content of each, by track number. Tracks are compared with the others
+// of their language, so tracks of one file are analyzed together.
+func analyzeTrackContent(ctx context.Context, mkvPath string, tracks []*TrackItem) (map[int]string, error) {
+	tmpDir, err := os.MkdirTemp("", "subtitle-content")
+	if err != nil {
+		return nil, fmt.Errorf("Error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	args := []string{"tracks", mkvPath}
+	paths := map[int]string{}
+	for _, t := range tracks {
+		ext := imageSubtitleExt(t.Codec)
+		if ext == "" {
+			ext = textSubtitleExt(t.Codec)
+		}
+		paths[t.Num] = filepath.Join(tmpDir, fmt.Sprintf("track%d.%s", t.Num, ext))
+		args = append(args, fmt.Sprintf("%d:%s", t.Num, paths[t.Num]))
+	}
+	if output, err := exec.CommandContext(ctx, "mkvextract", args...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("Error running mkvextract: %v\n%s", err, output)
+	}
+
+	stats := map[int]subtitleCueStats{}
+	maxCues := map[string]int{}
+	for _, t := range tracks {
+		data, err := os.ReadFile(paths[t.Num])
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(paths[t.Num], ".sup") {
+			cues, err := parsePGSCues(data)
+			if err != nil {
+				return nil, fmt.Errorf("Error reading track %d: %v", t.Num, err)
+			}
+			stats[t.Num] = bitmapCueStats(cues)
+		} else {
+			stats[t.Num] = assCueStats(strings.ReplaceAll(strings.TrimPrefix(string(data), "\ufeff"), "\r\n", "\n"))
+		}
+		maxCues[t.Lang] = max(maxCues[t.Lang], stats[t.Num].Cues)
+	}
+
+	contents := map[int]string{}
+	for _, t := range tracks {
+		contents[t.Num] = classifyTrackContent(stats[t.Num], maxCues[t.Lang])
+	}
+	return contents, nil
+}
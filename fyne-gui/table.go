@@ -0,0 +1,376 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Columns of the track table
+const (
+	trackColumnExtract = iota
+	trackColumnStatus
+	trackColumnID
+	trackColumnLanguage
+	trackColumnCodec
+	trackColumnName
+	trackColumnContent
+	trackColumnForced
+	trackColumnDefault
+	trackColumnEntries
+	trackColumnSize
+	trackColumnConvert
+	trackColumnOCRLanguage
+	trackColumnOCREngine
+	trackColumnOutputName
+	trackColumnCount
+)
+
+var trackColumnTitles = []string{"Extract", "Status", "ID", "Language", "Codec", "Name", "Content", "Forced", "Default", "Entries", "Size", "Convert", "OCR Language", "OCR Engine", "Output Name"}
+
+var trackColumnWidths = []float32{70, 70, 50, 90, 150, 260, 110, 70, 70, 80, 90, 80, 170, 120, 280}
+
+// TrackTable shows the subtitle tracks of the current file in a table that
+// sorts by the column whose header is tapped. The widgets of each TrackItem
+// stay the source of truth for the selection and conversion options.
+type TrackTable struct {
+	Table      *keyTable
+	content    *fyne.Container
+	items      []*TrackItem
+	rows       []*TrackItem
+	selected   *TrackItem
+	sortCol    int
+	sortAsc    bool
+	OnChanged  func()             // Called when the user changes a track's selection
+	OnSelected func(t *TrackItem) // Called when the user selects a track's row
+
+	// DefaultName returns the output name of a track that was not edited
+	DefaultName func(t *TrackItem) string
+
+	// OnMultipleLanguages is called when the user picks the Multiple
+	// Languages option as a track's OCR language
+	OnMultipleLanguages func(t *TrackItem)
+}
+
+// NewTrackTable creates an empty track table
+func NewTrackTable() *TrackTable {
+	tt := &TrackTable{sortCol: trackColumnID, sortAsc: true}
+
+	tt.Table = &keyTable{tt: tt}
+	tt.Table.Length = func() (int, int) {
+		return len(tt.rows), trackColumnCount
+	}
+	tt.Table.CreateCell = func() fyne.CanvasObject {
+		label := widget.NewLabel("")
+		label.Truncation = fyne.TextTruncateEllipsis
+		return container.NewStack(label, widget.NewCheck("", nil), widget.NewSelect(nil, nil), widget.NewEntry())
+	}
+	tt.Table.UpdateCell = func(id widget.TableCellID, o fyne.CanvasObject) {
+		tt.updateCell(id, o.(*fyne.Container))
+	}
+	tt.Table.ExtendBaseWidget(tt.Table)
+	tt.Table.ShowHeaderRow = true
+	tt.Table.OnSelected = func(id widget.TableCellID) {
+		if id.Row < 0 || id.Row >= len(tt.rows) {
+			return
+		}
+		tt.selected = tt.rows[id.Row]
+		if tt.OnSelected != nil {
+			tt.OnSelected(tt.selected)
+		}
+	}
+	tt.Table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewButton("", nil)
+	}
+	tt.Table.UpdateHeader = func(id widget.TableCellID, o fyne.CanvasObject) {
+		button := o.(*widget.Button)
+		title := tr(trackColumnTitles[id.Col])
+		if id.Col == tt.sortCol {
+			if tt.sortAsc {
+				title += " ▲"
+			} else {
+				title += " ▼"
+			}
+		}
+		button.SetText(title)
+		col := id.Col
+		button.OnTapped = func() {
+			tt.sortBy(col)
+		}
+	}
+	for col, width := range trackColumnWidths {
+		tt.Table.SetColumnWidth(col, width)
+	}
+
+	// Keep the table readable even when the window is small
+	minSize := canvas.NewRectangle(color.Transparent)
+	minSize.SetMinSize(fyne.NewSize(550, 250))
+	tt.content = container.NewStack(minSize, tt.Table)
+
+	return tt
+}
+
+// Content returns the canvas object to place in the layout
+func (tt *TrackTable) Content() fyne.CanvasObject {
+	return tt.content
+}
+
+// SetItems shows a new set of tracks, keeping the current sort order
+func (tt *TrackTable) SetItems(items []*TrackItem) {
+	tt.items = items
+	tt.selected = nil
+	tt.Table.UnselectAll()
+	tt.sortRows()
+	tt.Table.Refresh()
+}
+
+// Refresh redraws the table after track states or selections changed
+func (tt *TrackTable) Refresh() {
+	tt.Table.Refresh()
+}
+
+// Focus gives the keyboard focus to the table, selecting the first track
+// when none is selected yet
+func (tt *TrackTable) Focus(c fyne.Canvas) {
+	if tt.selectedRow() < 0 && len(tt.rows) > 0 {
+		tt.Table.Select(widget.TableCellID{Row: 0, Col: trackColumnExtract})
+	}
+	c.Focus(tt.Table)
+}
+
+// selectedRow returns the row of the selected track, or -1 when none is selected
+func (tt *TrackTable) selectedRow() int {
+	for row, t := range tt.rows {
+		if t == tt.selected {
+			return row
+		}
+	}
+	return -1
+}
+
+func (tt *TrackTable) sortBy(col int) {
+	if col == tt.sortCol {
+		tt.sortAsc = !tt.sortAsc
+	} else {
+		tt.sortCol = col
+		tt.sortAsc = true
+	}
+	tt.sortRows()
+	tt.Table.Refresh()
+}
+
+func (tt *TrackTable) sortRows() {
+	tt.rows = append([]*TrackItem(nil), tt.items...)
+	sort.SliceStable(tt.rows, func(i, j int) bool {
+		a, b := tt.rows[i], tt.rows[j]
+		if tt.sortAsc {
+			return trackLess(a, b, tt.sortCol)
+		}
+		return trackLess(b, a, tt.sortCol)
+	})
+}
+
+// trackLess orders two tracks by a table column
+func trackLess(a, b *TrackItem, col int) bool {
+	switch col {
+	case trackColumnExtract:
+		return !a.Check.Checked && b.Check.Checked
+	case trackColumnID:
+		return a.Num < b.Num
+	case trackColumnForced:
+		return !a.Forced && b.Forced
+	case trackColumnDefault:
+		return !a.Default && b.Default
+	case trackColumnEntries:
+		return a.Entries < b.Entries
+	case trackColumnSize:
+		sizeA, _ := trackSize(a)
+		sizeB, _ := trackSize(b)
+		return sizeA < sizeB
+	case trackColumnConvert:
+		return a.ConvertOCR == nil && b.ConvertOCR != nil ||
+			a.ConvertOCR != nil && b.ConvertOCR != nil && !a.ConvertOCR.Checked && b.ConvertOCR.Checked
+	}
+	return strings.ToLower(trackCellText(a, col)) < strings.ToLower(trackCellText(b, col))
+}
+
+// trackCellText returns the text shown for a track in a label column
+func trackCellText(t *TrackItem, col int) string {
+	switch col {
+	case trackColumnStatus:
+		return t.State
+	case trackColumnID:
+		return fmt.Sprintf("%d", t.Num)
+	case trackColumnLanguage:
+		return t.Lang
+	case trackColumnCodec:
+		return t.Codec
+	case trackColumnName:
+		return t.Name
+	case trackColumnContent:
+		return tr(t.Content)
+	case trackColumnForced:
+		return yesNo(t.Forced)
+	case trackColumnDefault:
+		return yesNo(t.Default)
+	case trackColumnEntries:
+		if t.Entries == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d", t.Entries)
+	case trackColumnSize:
+		// Estimated sizes are marked with a tilde
+		size, exact := trackSize(t)
+		if size == 0 {
+			return ""
+		}
+		if !exact {
+			return "~" + formatSize(size)
+		}
+		return formatSize(size)
+	case trackColumnOCRLanguage:
+		if t.LangSelect != nil {
+			return t.LangSelect.Selected
+		}
+	case trackColumnOCREngine:
+		return ocrEngine(t)
+	case trackColumnOutputName:
+		return t.OutputName
+	}
+	return ""
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return ""
+}
+
+// updateCell shows the label, check or select of a cell, bound to the
+// matching widget of the track
+func (tt *TrackTable) updateCell(id widget.TableCellID, cell *fyne.Container) {
+	label := cell.Objects[0].(*widget.Label)
+	check := cell.Objects[1].(*widget.Check)
+	sel := cell.Objects[2].(*widget.Select)
+	entry := cell.Objects[3].(*widget.Entry)
+	label.Hide()
+	check.Hide()
+	sel.Hide()
+	entry.Hide()
+	if id.Row >= len(tt.rows) {
+		return
+	}
+	t := tt.rows[id.Row]
+
+	switch {
+	case id.Col == trackColumnExtract:
+		bindCheck(check, t.Check, tt.OnChanged)
+	case id.Col == trackColumnConvert && t.ConvertOCR != nil:
+		bindCheck(check, t.ConvertOCR, nil)
+	case id.Col == trackColumnOCRLanguage && t.LangSelect != nil:
+		sel.OnChanged = nil
+		sel.Options = t.LangSelect.Options
+		sel.SetSelected(t.LangSelect.Selected)
+		sel.OnChanged = func(s string) {
+			if s == multipleOCRLanguagesOption {
+				// The option opens a dialog instead of being selected
+				sel.SetSelected(t.LangSelect.Selected)
+				if tt.OnMultipleLanguages != nil {
+					tt.OnMultipleLanguages(t)
+				}
+				return
+			}
+			t.LangSelect.SetSelected(s)
+		}
+		sel.Show()
+	case id.Col == trackColumnOCREngine && t.Engine != nil:
+		sel.OnChanged = nil
+		sel.Options = t.Engine.Options
+		sel.SetSelected(t.Engine.Selected)
+		sel.OnChanged = t.Engine.SetSelected
+		sel.Show()
+	case id.Col == trackColumnOutputName:
+		// An empty entry keeps the default name, shown as placeholder
+		entry.OnChanged = nil
+		if tt.DefaultName != nil {
+			entry.SetPlaceHolder(tt.DefaultName(t))
+		}
+		entry.SetText(t.OutputName)
+		entry.OnChanged = func(s string) {
+			t.OutputName = cleanOutputName(s)
+		}
+		entry.Show()
+	default:
+		label.SetText(trackCellText(t, id.Col))
+		label.Show()
+	}
+}
+
+// bindCheck makes a recycled table check mirror and update a track's check
+func bindCheck(check *widget.Check, source *widget.Check, onChanged func()) {
+	check.OnChanged = nil
+	check.SetChecked(source.Checked)
+	check.OnChanged = func(b bool) {
+		source.SetChecked(b)
+		if onChanged != nil {
+			onChanged()
+		}
+	}
+	check.Show()
+}
+
+// keyTable is the table of a TrackTable with keys to work without a mouse:
+// Up and Down select the previous or next track, Space toggles its
+// extraction and C its conversion to SRT
+type keyTable struct {
+	widget.Table
+	tt *TrackTable
+}
+
+// TypedKey handles the track keys and leaves the other keys to the table
+func (kt *keyTable) TypedKey(ev *fyne.KeyEvent) {
+	tt := kt.tt
+	row := tt.selectedRow()
+	switch ev.Name {
+	case fyne.KeyUp, fyne.KeyDown:
+		if ev.Name == fyne.KeyUp {
+			row--
+		} else {
+			row++
+		}
+		if row >= 0 && row < len(tt.rows) {
+			kt.Select(widget.TableCellID{Row: row, Col: trackColumnExtract})
+		}
+	case fyne.KeySpace:
+		if row < 0 {
+			return
+		}
+		t := tt.rows[row]
+		t.Check.SetChecked(!t.Check.Checked)
+		kt.Refresh()
+		if tt.OnChanged != nil {
+			tt.OnChanged()
+		}
+	default:
+		kt.Table.TypedKey(ev)
+	}
+}
+
+// TypedRune toggles the conversion of the selected track
+func (kt *keyTable) TypedRune(r rune) {
+	row := kt.tt.selectedRow()
+	if row < 0 || (r != 'c' && r != 'C') {
+		return
+	}
+	if t := kt.tt.rows[row]; t.ConvertOCR != nil {
+		t.ConvertOCR.SetChecked(!t.ConvertOCR.Checked)
+		kt.Refresh()
+	}
+}
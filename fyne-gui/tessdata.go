@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// OCR qualities, each with its own traineddata: fast models are quicker,
+// best models more accurate
+const (
+	OCRQualityFast = "fast"
+	OCRQualityBest = "best"
+)
+
+// tessdataURL is where the traineddata of a quality and language is
+// downloaded from
+const tessdataURL = "https://github.com/tesseract-ocr/tessdata_%s/raw/main/%s.traineddata"
+
+// tesseractLanguageRegex matches the names of Tesseract languages, such as
+// eng or chi_sim
+var tesseractLanguageRegex = regexp.MustCompile(`^[a-z]{3}(_[a-z]+)?$`)
+
+// customTessdataDir returns the tessdata folder chosen in the settings, for
+// own trained models or non-standard installs, or an empty string
+func customTessdataDir() string {
+	return fyne.CurrentApp().Preferences().String("tessdata_dir")
+}
+
+// tessdataDir returns the folder traineddata files of a quality are
+// downloaded into and read from besides the one of Tesseract: the one chosen
+// in the settings, or a folder in the app data dir. Best models are kept in
+// a best subfolder.
+func tessdataDir(quality string) string {
+	dir := customTessdataDir()
+	if dir == "" {
+		dir = filepath.Join(fyne.CurrentApp().Storage().RootURI().Path(), "tessdata")
+	}
+	if quality == OCRQualityBest {
+		dir = filepath.Join(dir, "best")
+	}
+	return dir
+}
+
+// systemTesseractLanguages returns the languages of the traineddata installed
+// with Tesseract, or an error when Tesseract is missing
+func systemTesseractLanguages(ctx context.Context) ([]string, error) {
+	output, err := exec.CommandContext(ctx, "tesseract", "--list-langs").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("Tesseract is not installed or cannot be run: %v", err)
+	}
+	var langs []string
+	for _, line := range strings.Split(string(output), "\n") {
+		// The first line is a header naming the tessdata folder
+		if line = strings.TrimSpace(line); tesseractLanguageRegex.MatchString(line) {
+			langs = append(langs, line)
+		}
+	}
+	return langs, nil
+}
+
+// downloadedTesseractLanguages returns the languages of the traineddata of a
+// quality downloaded into tessdataDir
+func downloadedTesseractLanguages(quality string) []string {
+	paths, _ := filepath.Glob(filepath.Join(tessdataDir(quality), "*.traineddata"))
+	var langs []string
+	for _, path := range paths {
+		langs = append(langs, strings.TrimSuffix(filepath.Base(path), ".traineddata"))
+	}
+	return langs
+}
+
+// downloadTessdata downloads the traineddata of a quality and lang into
+// tessdataDir. onProgress is called with the bytes received and the total,
+// which is 0 when the server does not send it.
+func downloadTessdata(ctx context.Context, lang, quality string, onProgress func(done, total int64)) error {
+	if !tesseractLanguageRegex.MatchString(lang) {
+		return fmt.Errorf("Invalid Tesseract language %q", lang)
+	}
+	dir := tessdataDir(quality)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(tessdataURL, quality, lang), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Error downloading traineddata for %s: %v", lang, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Error downloading traineddata for %s: %s", lang, resp.Status)
+	}
+
+	// Download next to the final file, so an interrupted download is never used
+	file, err := os.CreateTemp(dir, lang+".*.part")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = io.Copy(file, io.TeeReader(resp.Body, &downloadProgress{total: max(resp.ContentLength, 0), onProgress: onProgress}))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("Error downloading traineddata for %s: %v", lang, err)
+	}
+	return os.Rename(file.Name(), filepath.Join(dir, lang+".traineddata"))
+}
+
+// downloadProgress counts the bytes written to it and reports them
+type downloadProgress struct {
+	done, total int64
+	onProgress  func(done, total int64)
+}
+
+func (p *downloadProgress) Write(b []byte) (int, error) {
+	p.done += int64(len(b))
+	p.onProgress(p.done, p.total)
+	return len(b), nil
+}
+
+// tesseractDataDir makes sure Tesseract can read lang, which may combine
+// languages as in eng+jpn, downloading traineddata that neither Tesseract nor
+// tessdataDir has. A folder chosen in the settings is always used, and best
+// models always come from tessdataDir as Tesseract is installed with others.
+// It returns the folder to pass as --tessdata-dir, or an empty string for the
+// one Tesseract was installed with.
+func tesseractDataDir(ctx context.Context, lang, quality string) (string, error) {
+	langs, err := systemTesseractLanguages(ctx)
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(lang, "+")
+	if customTessdataDir() == "" && quality != OCRQualityBest && !slices.ContainsFunc(parts, func(part string) bool { return !slices.Contains(langs, part) }) {
+		return "", nil
+	}
+	// --tessdata-dir replaces the folder of Tesseract, so all languages of a
+	// combination have to be downloaded once one of them is
+	downloaded := downloadedTesseractLanguages(quality)
+	for _, part := range parts {
+		if slices.Contains(downloaded, part) {
+			continue
+		}
+		if err := downloadTessdata(ctx, part, quality, func(done, total int64) {}); err != nil {
+			return "", err
+		}
+	}
+	return tessdataDir(quality), nil
+}
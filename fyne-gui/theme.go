@@ -0,0 +1,52 @@
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// Theme variants selectable in the Settings tab
+const (
+	ThemeSystem = "System"
+	ThemeLight  = "Light"
+	ThemeDark   = "Dark"
+)
+
+// uiScales are the UI scale factors selectable in the Settings tab
+var uiScales = []string{"75%", "90%", "100%", "110%", "125%", "150%", "175%", "200%"}
+
+// appTheme is the default theme with the variant and scale chosen in the Settings tab
+type appTheme struct {
+	fyne.Theme
+	variant string
+	scale   float32
+}
+
+func (t *appTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	switch t.variant {
+	case ThemeLight:
+		variant = theme.VariantLight
+	case ThemeDark:
+		variant = theme.VariantDark
+	}
+	return t.Theme.Color(name, variant)
+}
+
+func (t *appTheme) Size(name fyne.ThemeSizeName) float32 {
+	return t.Theme.Size(name) * t.scale
+}
+
+// applyTheme sets the app theme from the theme and scale preferences
+func applyTheme(a fyne.App) {
+	scale := float32(a.Preferences().FloatWithFallback("ui_scale", 1))
+	if scale <= 0 {
+		scale = 1
+	}
+	a.Settings().SetTheme(&appTheme{
+		Theme:   theme.DefaultTheme(),
+		variant: a.Preferences().StringWithFallback("theme_variant", ThemeSystem),
+		scale:   scale,
+	})
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// loadSubtitleTracks runs mkvmerge on an MKV file and creates a TrackItem,
+// with its widgets, for every subtitle track
+func loadSubtitleTracks(mkvPath string) ([]*TrackItem, error) {
+	// Run mkvmerge to get track info
+	cmd := exec.Command("mkvmerge", "-J", mkvPath)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("Error running mkvmerge: %v", err)
+	}
+
+	// Parse JSON output
+	var mkvInfo map[string]interface{}
+	err = json.Unmarshal(output, &mkvInfo)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing mkvmerge output: %v", err)
+	}
+
+	// Extract tracks
+	tracks, ok := mkvInfo["tracks"].([]interface{})
+	if !ok {
+		return nil, errors.New(tr("No tracks found in MKV file."))
+	}
+
+	// Decode the properties again without converting numbers to float64,
+	// which would round 64-bit UIDs, for the track details panel
+	var rawInfo struct {
+		Tracks []struct {
+			ID         int                        `json:"id"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"tracks"`
+	}
+	json.Unmarshal(output, &rawInfo)
+	rawProperties := map[int]map[string]json.RawMessage{}
+	for _, track := range rawInfo.Tracks {
+		rawProperties[track.ID] = track.Properties
+	}
+
+	// Process subtitle tracks
+	items := []*TrackItem{}
+	for _, track := range tracks {
+		trackMap, ok := track.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// Check if this is a subtitle track
+		trackType, ok := trackMap["type"].(string)
+		if !ok || trackType != "subtitles" {
+			continue
+		}
+
+		// Get track properties
+		properties, ok := trackMap["properties"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		trackID := int(trackMap["id"].(float64))
+
+		// Get language with nil check
+		var trackLang string
+		if properties != nil {
+			if lang, ok := properties["language"].(string); ok {
+				trackLang = lang
+			} else {
+				trackLang = "und" // undefined language code
+			}
+		} else {
+			trackLang = "und" // undefined language code
+		}
+
+		trackCodec := trackMap["codec"].(string)
+
+		// Get track name if available
+		var trackName string
+		if name, ok := properties["track_name"].(string); ok {
+			trackName = name
+		} else {
+			trackName = ""
+		}
+
+		// Get flags and entry count; mkvmerge omits them for some files
+		trackForced, _ := properties["forced_track"].(bool)
+		trackDefault, _ := properties["default_track"].(bool)
+		trackEntries, _ := properties["num_index_entries"].(float64)
+
+		// Create UI elements for this track
+		check := widget.NewCheck("", nil)
+		check.SetChecked(true)
+		status := widget.NewLabel("[ ]")
+
+		// Create track item
+		t := &TrackItem{
+			Num:     trackID,
+			Lang:    trackLang,
+			Codec:   trackCodec,
+			Name:    trackName,
+			Forced:  trackForced,
+			Default: trackDefault,
+			Entries: int(trackEntries),
+			Content: trackContentFromName(trackName),
+			State:   "Pending",
+			Check:   check,
+			Status:  status,
+
+			Properties: map[string]string{},
+		}
+		for key, value := range rawProperties[trackID] {
+			t.Properties[key] = propertyText(value)
+		}
+
+		// Add OCR option for PGS subtitles, ASS/SSA subtitles, VobSub and DVB subtitles
+		if t.Codec == "hdmv_pgs_subtitle" || t.Codec == "HDMV PGS" ||
+			strings.Contains(strings.ToLower(t.Codec), "ass") || strings.Contains(strings.ToLower(t.Codec), "ssa") ||
+			strings.Contains(strings.ToLower(t.Codec), "substation") || strings.Contains(strings.ToLower(t.Codec), "sub station") ||
+			t.Codec == "vobsub" || t.Codec == "VobSub" || isDVBSubtitle(t.Codec) {
+			t.ConvertOCR = widget.NewCheck("", nil)
+			t.ConvertOCR.SetChecked(fyne.CurrentApp().Preferences().BoolWithFallback(conversionPreference(t.Codec), true))
+
+			// Add language selection for OCR conversion
+			if imageSubtitleExt(t.Codec) != "" {
+				// Create language options
+				langOptions := append([]string{"Auto (" + t.Lang + ")"}, ocrLanguageOptions...)
+				langOptions = append(langOptions, multipleOCRLanguagesOption)
+
+				// Create language dropdown
+				t.LangSelect = widget.NewSelect(langOptions, nil)
+				t.LangSelect.SetSelected("Auto (" + t.Lang + ")")
+
+				// Restore the OCR language last used for this track language
+				prefKey := "ocr_language_" + t.Lang
+				prefs := fyne.CurrentApp().Preferences()
+				selectOCRLanguage(t.LangSelect, prefs.String(prefKey))
+				t.LangSelect.OnChanged = func(selected string) {
+					prefs.SetString(prefKey, selected)
+				}
+
+				// The OCR engine is remembered per track language too, as
+				// some engines read some scripts better
+				engineKey := "ocr_engine_" + t.Lang
+				t.Engine = widget.NewSelect(ocrEngineNames(), nil)
+				t.Engine.SetSelected(ocrEngineByName(prefs.String(engineKey)).Name())
+				t.Engine.OnChanged = func(selected string) {
+					prefs.SetString(engineKey, selected)
+				}
+			} else {
+				t.LangSelect = nil
+				t.Engine = nil
+			}
+		} else {
+			t.ConvertOCR = nil
+			t.LangSelect = nil
+			t.Engine = nil
+		}
+
+		items = append(items, t)
+	}
+
+	if preset := activePreset(fyne.CurrentApp().Preferences()); preset != nil {
+		preset.apply(items)
+	}
+
+	return items, nil
+}
+
+// propertyText formats a raw mkvmerge property value for display
+func propertyText(value json.RawMessage) string {
+	var text string
+	if json.Unmarshal(value, &text) == nil {
+		return text
+	}
+	var flag bool
+	if json.Unmarshal(value, &flag) == nil {
+		if flag {
+			return tr("Yes")
+		}
+		return tr("No")
+	}
+	return string(value)
+}
+
+// trackDetailKeys are the mkvmerge properties shown first in the track
+// details panel, with their labels. Other properties follow by name.
+var trackDetailKeys = []struct {
+	Key   string
+	Label string
+}{
+	{"uid", "UID"},
+	{"number", "Track number"},
+	{"codec_id", "Codec ID"},
+	{"codec_private_length", "Codec private data (bytes)"},
+	{"content_encoding_algorithms", "Content encoding"},
+	{"encoding", "Character encoding"},
+	{"language", "Language"},
+	{"language_ietf", "Language (IETF)"},
+	{"track_name", "Name"},
+	{"tag_DURATION", "Duration"},
+	{"num_index_entries", "Index entries"},
+	{"tag_NUMBER_OF_FRAMES", "Subtitle events"},
+	{"tag_NUMBER_OF_BYTES", "Size (bytes)"},
+	{"default_track", "Default"},
+	{"forced_track", "Forced"},
+	{"enabled_track", "Enabled"},
+	{"flag_hearing_impaired", "Hearing impaired"},
+	{"flag_visual_impaired", "Visually impaired"},
+	{"flag_text_descriptions", "Text descriptions"},
+	{"flag_original", "Original language"},
+	{"flag_commentary", "Commentary"},
+}
+
+// trackDetails returns the label and value of every property of a track
+func trackDetails(t *TrackItem) [][2]string {
+	details := [][2]string{
+		{tr("Track ID"), fmt.Sprint(t.Num)},
+		{tr("Codec"), t.Codec},
+	}
+	shown := map[string]bool{}
+	for _, d := range trackDetailKeys {
+		if value, ok := t.Properties[d.Key]; ok {
+			details = append(details, [2]string{tr(d.Label), value})
+			shown[d.Key] = true
+		}
+	}
+
+	var others []string
+	for key := range t.Properties {
+		if !shown[key] {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	for _, key := range others {
+		details = append(details, [2]string{key, t.Properties[key]})
+	}
+	return details
+}
+
+// trackSize returns the size of the extracted track in bytes. mkvmerge's
+// statistics tags give the exact size; without them the size is estimated
+// from the number of entries and a typical entry size for the codec.
+func trackSize(t *TrackItem) (size int64, exact bool) {
+	if bytes, err := strconv.ParseInt(t.Properties["tag_NUMBER_OF_BYTES"], 10, 64); err == nil {
+		return bytes, true
+	}
+
+	var entryBytes int64
+	switch {
+	case imageSubtitleExt(t.Codec) == "sup":
+		entryBytes = 15000
+	case imageSubtitleExt(t.Codec) == "idx", imageSubtitleExt(t.Codec) == "ts":
+		entryBytes = 6000
+	case textSubtitleExt(t.Codec) == "ass":
+		entryBytes = 150
+	default:
+		entryBytes = 60
+	}
+	return int64(t.Entries) * entryBytes, false
+}
+
+// formatSize formats a size in bytes for display, e.g. "1.5 MB"
+func formatSize(size int64) string {
+	switch {
+	case size >= 1000*1000*1000:
+		return fmt.Sprintf("%.1f GB", float64(size)/1e9)
+	case size >= 1000*1000:
+		return fmt.Sprintf("%.1f MB", float64(size)/1e6)
+	case size >= 1000:
+		return fmt.Sprintf("%.0f KB", float64(size)/1e3)
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+// conversionPreference returns the preference key that sets whether tracks of a
+// codec are converted to SRT by default
+func conversionPreference(codec string) string {
+	switch {
+	case codec == "hdmv_pgs_subtitle" || codec == "HDMV PGS":
+		return "convert_pgs"
+	case codec == "vobsub" || codec == "VobSub":
+		return "convert_vobsub"
+	case isDVBSubtitle(codec):
+		return "convert_dvbsub"
+	}
+	return "convert_ass"
+}
+
+// selectedTrackCount returns how many of the tracks are checked
+func selectedTrackCount(items []*TrackItem) int {
+	count := 0
+	for _, t := range items {
+		if t.Check.Checked {
+			count++
+		}
+	}
+	return count
+}
+
+// trackLanguages returns the distinct languages of the tracks, sorted
+func trackLanguages(items []*TrackItem) []string {
+	seen := map[string]bool{}
+	var langs []string
+	for _, t := range items {
+		if !seen[t.Lang] {
+			seen[t.Lang] = true
+			langs = append(langs, t.Lang)
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// subtitleExtensions are stripped from edited output names, as the extension
+// follows from the codec and conversion
+var subtitleExtensions = []string{".srt", ".ass", ".ssa", ".sup", ".idx", ".sub", ".vtt"}
+
+// defaultFilenameTemplate reproduces the output names used before templates
+const defaultFilenameTemplate = "{basename}.track{track}_{lang}"
+
+// filenameTemplateTokens documents the tokens of output filename templates
+const filenameTemplateTokens = "{basename}, {lang}, {track}, {forced}, {name}"
+
+var unsafeFilenameChars = regexp.MustCompile(`[/\\:*?"<>|]`)
+
+// repeatedSeparators matches the separators left around tokens that expanded to nothing
+var repeatedSeparators = regexp.MustCompile(`([._])[._]+`)
+
+// defaultOutputName returns the output file name, without extension, of a track
+// whose name was not edited, following the filename template
+func defaultOutputName(template string, mkvPath string, t *TrackItem) string {
+	mkvBaseName := strings.TrimSuffix(filepath.Base(mkvPath), filepath.Ext(mkvPath))
+	forced := ""
+	if t.Forced {
+		forced = "forced"
+	}
+	name := strings.NewReplacer(
+		"{basename}", mkvBaseName,
+		"{lang}", t.Lang,
+		"{track}", fmt.Sprintf("%d", t.Num),
+		"{forced}", forced,
+		"{name}", unsafeFilenameChars.ReplaceAllString(t.Name, "_"),
+	).Replace(template)
+	name = repeatedSeparators.ReplaceAllString(name, "$1")
+	name = strings.Trim(unsafeFilenameChars.ReplaceAllString(name, "_"), "._ -")
+	if name == "" {
+		return defaultOutputName(defaultFilenameTemplate, mkvPath, t)
+	}
+	return name
+}
+
+// cleanOutputName trims an edited output name and strips a subtitle extension
+func cleanOutputName(name string) string {
+	name = strings.TrimSpace(name)
+	for _, ext := range subtitleExtensions {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
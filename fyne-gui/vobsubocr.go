@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// vobSubTick is the unit of the display dates in VobSub control sequences
+const vobSubTick = time.Second * 1024 / 90000
+
+// vobSubIndexEntry is a subtitle listed in a VobSub .idx file: when it starts
+// and where its packet is in the .sub file
+type vobSubIndexEntry struct {
+	Start   time.Duration
+	FilePos int64
+}
+
+// readVobSubIndex reads the timestamps and file positions of the first
+// subtitle stream of a VobSub .idx file
+func readVobSubIndex(idxPath string) ([]vobSubIndexEntry, error) {
+	file, err := os.Open(idxPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []vobSubIndexEntry
+	var delay time.Duration
+	streams := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "id:"):
+			streams++
+		case streams > 1:
+			// Only the first stream is converted
+		case strings.HasPrefix(line, "delay:"):
+			d, err := parseVobSubTime(strings.TrimPrefix(line, "delay:"))
+			if err != nil {
+				return nil, fmt.Errorf("Invalid delay %q in %s", line, idxPath)
+			}
+			delay += d
+		case strings.HasPrefix(line, "timestamp:"):
+			// timestamp: 00:00:01:000, filepos: 000000000
+			stamp, pos, ok := strings.Cut(strings.TrimPrefix(line, "timestamp:"), ",")
+			start, err := parseVobSubTime(stamp)
+			if !ok || err != nil {
+				return nil, fmt.Errorf("Invalid timestamp %q in %s", line, idxPath)
+			}
+			filePos, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(pos), "filepos:")), 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid file position %q in %s", line, idxPath)
+			}
+			entries = append(entries, vobSubIndexEntry{Start: start + delay, FilePos: filePos})
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// parseVobSubTime parses a VobSub timestamp of the form HH:MM:SS:mmm, which
+// may be negative in delay lines
+func parseVobSubTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	sign := time.Duration(1)
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		sign, s = -1, rest
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 {
+		return 0, fmt.Errorf("Invalid VobSub timestamp %q", s)
+	}
+	units := []time.Duration{time.Hour, time.Minute, time.Second, time.Millisecond}
+	var d time.Duration
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid VobSub timestamp %q", s)
+		}
+		d += time.Duration(n) * units[i]
+	}
+	return sign * d, nil
+}
+
+// readVobSubScreenHeight reads the height of the video a VobSub .idx file
+// belongs to, or that of PAL DVDs when it does not tell
+func readVobSubScreenHeight(idxPath string) (int, error) {
+	file, err := os.Open(idxPath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// size: 720x576
+		line := strings.TrimSpace(scanner.Text())
+		if size, ok := strings.CutPrefix(line, "size:"); ok {
+			_, height, _ := strings.Cut(size, "x")
+			if h, err := strconv.Atoi(strings.TrimSpace(height)); err == nil && h > 0 {
+				return h, nil
+			}
+		}
+	}
+	return 576, scanner.Err()
+}
+
+// vobSubDisplay returns how long a subtitle packet is shown, from the dates
+// of its start and stop display commands, or 0 when it has no stop, and the
+// area of the screen it is shown in
+func vobSubDisplay(spu []byte) (time.Duration, image.Rectangle) {
+	var area image.Rectangle
+	if len(spu) < 4 {
+		return 0, area
+	}
+	// Arguments taken by the commands of a control sequence
+	argSizes := map[byte]int{0x00: 0, 0x01: 0, 0x02: 0, 0x03: 2, 0x04: 2, 0x05: 6, 0x06: 4}
+	var start, stop time.Duration
+	for ctrl := int(binary.BigEndian.Uint16(spu[2:])); ctrl+4 <= len(spu); {
+		date := time.Duration(binary.BigEndian.Uint16(spu[ctrl:])) * vobSubTick
+		next := int(binary.BigEndian.Uint16(spu[ctrl+2:]))
+		for i := ctrl + 4; i < len(spu); {
+			cmd := spu[i]
+			size, ok := argSizes[cmd]
+			if !ok {
+				break
+			}
+			if i+1+size > len(spu) {
+				break
+			}
+			switch cmd {
+			case 0x01:
+				start = date
+			case 0x02:
+				stop = date
+			case 0x05:
+				a := spu[i+1:]
+				area = image.Rect(int(a[0])<<4|int(a[1])>>4, int(a[3])<<4|int(a[4])>>4,
+					int(a[1]&0x0f)<<8|int(a[2])+1, int(a[4]&0x0f)<<8|int(a[5])+1)
+			}
+			i += 1 + size
+		}
+		if next <= ctrl {
+			break
+		}
+		ctrl = next
+	}
+	if stop <= start {
+		return 0, area
+	}
+	return stop - start, area
+}
+
+// parseVobSubCues decodes the subtitles listed in a VobSub .idx file from its
+// .sub file. A subtitle without a stop command is shown until the next one
+// starts, for at most defaultCueDuration.
+func parseVobSubCues(idxPath string) ([]BitmapCue, error) {
+	entries, err := readVobSubIndex(idxPath)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading VobSub index: %v", err)
+	}
+	palette, err := readVobSubPalette(idxPath)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading VobSub palette: %v", err)
+	}
+	screenHeight, err := readVobSubScreenHeight(idxPath)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading VobSub index: %v", err)
+	}
+	data, err := os.ReadFile(strings.TrimSuffix(idxPath, ".idx") + ".sub")
+	if err != nil {
+		return nil, fmt.Errorf("Error reading VobSub data: %v", err)
+	}
+
+	var cues []BitmapCue
+	for i, entry := range entries {
+		if entry.FilePos < 0 || entry.FilePos >= int64(len(data)) {
+			continue
+		}
+		packets := vobSubPackets(data[entry.FilePos:], 1)
+		if len(packets) == 0 {
+			continue
+		}
+		img, err := decodeVobSubSPU(packets[0], &palette)
+		if err != nil {
+			continue
+		}
+		duration, area := vobSubDisplay(packets[0])
+		end := entry.Start + duration
+		if end == entry.Start {
+			end = entry.Start + defaultCueDuration
+		}
+		if i+1 < len(entries) && entries[i+1].Start > entry.Start && end > entries[i+1].Start {
+			end = entries[i+1].Start
+		}
+		cues = append(cues, BitmapCue{Start: entry.Start, End: end, Image: img, Top: !area.Empty() && area.Min.Y+area.Max.Y < screenHeight})
+	}
+	return cues, nil
+}
+
+// convertVobSubToSRT recognizes the text of every subtitle of a VobSub
+// .idx/.sub pair with the OCR engine of opts and writes it as SRT, or reuses
+// the cached result of the same pair. onProgress is called after every
+// subtitle with the number done and the total.
+func convertVobSubToSRT(ctx context.Context, idxPath, srtPath string, opts OCROptions, onProgress func(done, total int)) (OCRResult, error) {
+	cues, err := parseVobSubCues(idxPath)
+	if err != nil {
+		return OCRResult{}, err
+	}
+	if len(cues) == 0 {
+		return OCRResult{}, fmt.Errorf("No subtitles found in %s", filepath.Base(idxPath))
+	}
+	var cacheKey string
+	if opts.Cache {
+		// Without a key the result is just not cached
+		cacheKey, _ = ocrCacheKey(opts, idxPath, strings.TrimSuffix(idxPath, ".idx")+".sub")
+	}
+	return ocrCuesToSRT(ctx, cues, srtPath, cacheKey, opts, onProgress)
+}
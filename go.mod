@@ -5,6 +5,7 @@ go 1.22
 require (
 	github.com/devfacet/gocmd/v3 v3.1.3
 	github.com/sirupsen/logrus v1.9.3
+	golang.org/x/text v0.22.0
 )
 
 require golang.org/x/sys v0.0.0-20220715151400-c0bba94af5f8 // indirect
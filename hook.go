@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// execTokens are the --exec tokens holding text and the environment
+// variables their values are passed to the hook in, so file names are never
+// parsed by the shell
+var execTokens = []struct {
+	Token string
+	Env   string
+}{
+	{"{file}", "GMMMKV_FILE"},
+	{"{input}", "GMMMKV_INPUT"},
+	{"{lang}", "GMMMKV_LANG"},
+	{"{codec}", "GMMMKV_CODEC"},
+}
+
+// expandExecTemplate replaces the --exec tokens {file}, {input}, {lang},
+// {track}, {id} and {codec}. Those holding text become quoted references to
+// the environment variables returned with the command.
+func expandExecTemplate(template string, fileName string, inputFileName string, track MKVTrack) (string, []string) {
+	values := []string{fileName, inputFileName, track.Properties.Language, track.Properties.CodecId}
+	pairs := []string{
+		"{track}", strconv.Itoa(track.Properties.Number),
+		"{id}", strconv.Itoa(track.Id),
+	}
+	env := []string{}
+	for i, token := range execTokens {
+		pairs = append(pairs, token.Token, execVariable(token.Env, values[i]))
+		env = append(env, token.Env+"="+values[i])
+	}
+	return strings.NewReplacer(pairs...).Replace(template), env
+}
+
+// runExecHook runs the user's --exec command for a produced subtitle file
+func runExecHook(template string, fileName string, inputFileName string, track MKVTrack) error {
+	command, env := expandExecTemplate(template, fileName, inputFileName, track)
+	cmd := execHookCommand(command)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	logrus.
+		WithField("command", command).
+		Debug("Running exec hook")
+	if cmdErr := cmd.Run(); cmdErr != nil {
+		logrus.
+			WithField("command", command).
+			WithField("fileName", fileName).
+			WithError(cmdErr).
+			Error("Error executing exec hook")
+		return fmt.Errorf("exec hook failed for %s: %w", fileName, cmdErr)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package main
+
+import "os/exec"
+
+// execVariable returns how sh reads an environment variable, in double
+// quotes so the value is not split or globbed
+func execVariable(name string, value string) string {
+	return `"$` + name + `"`
+}
+
+// execHookCommand runs the hook with sh
+func execHookCommand(command string) *exec.Cmd {
+	return exec.Command("sh", "-c", command)
+}
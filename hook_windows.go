@@ -0,0 +1,27 @@
+//go:build windows
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// execVariable returns how cmd.exe reads an environment variable holding
+// value, in double quotes. An empty variable is not defined for cmd.exe, so
+// an empty value is written as "" instead.
+func execVariable(name string, value string) string {
+	if value == "" {
+		return `""`
+	}
+	return `"%` + name + `%"`
+}
+
+// execHookCommand runs the hook with cmd.exe. The command line is handed
+// over as is, since cmd.exe does not undo the quoting Go applies to
+// arguments; /S only strips the outer quotes.
+func execHookCommand(command string) *exec.Cmd {
+	cmd := exec.Command("cmd")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd /S /C "` + command + `"`}
+	return cmd
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Layout selects where extracted subtitle files are written
+type Layout string
+
+const (
+	// LayoutNextToSource writes files next to their MKV file
+	LayoutNextToSource Layout = ""
+	// LayoutFlat writes every file into one folder, renaming clashing names
+	LayoutFlat Layout = "flat"
+	// LayoutSubfolder writes the files of each MKV file into a folder named after it
+	LayoutSubfolder Layout = "subfolder"
+	// LayoutMirror recreates the input directory tree below the output directory
+	LayoutMirror Layout = "mirror"
+)
+
+func parseLayout(name string) (Layout, error) {
+	switch layout := Layout(strings.ToLower(strings.TrimSpace(name))); layout {
+	case LayoutNextToSource, LayoutFlat, LayoutSubfolder, LayoutMirror:
+		return layout, nil
+	case "next":
+		return LayoutNextToSource, nil
+	}
+	return LayoutNextToSource, fmt.Errorf("unknown layout %q (expected flat, subfolder or mirror)", name)
+}
+
+// OutputLayout moves the file names built by the naming scheme into the chosen layout
+type OutputLayout struct {
+	Layout    Layout
+	InputRoot string
+	OutputDir string
+	// flatOwners remembers which input file produced each flat name
+	flatOwners map[string]string
+}
+
+func newOutputLayout(layout Layout, input string, outputDir string) (*OutputLayout, error) {
+	inputRoot := input
+	if ifs, statErr := os.Stat(input); statErr == nil && !ifs.IsDir() {
+		inputRoot = path.Dir(input)
+	}
+	if outputDir == "" {
+		switch layout {
+		case LayoutMirror:
+			return nil, errors.New("--layout mirror requires --output-dir")
+		case LayoutFlat:
+			outputDir = inputRoot
+		}
+	}
+	return &OutputLayout{Layout: layout, InputRoot: inputRoot, OutputDir: outputDir, flatOwners: map[string]string{}}, nil
+}
+
+// Place returns where outFileName, built next to inputFileName, goes in the layout
+func (layout *OutputLayout) Place(inputFileName string, outFileName string) (string, error) {
+	if layout == nil {
+		return outFileName, nil
+	}
+	fileName := path.Base(outFileName)
+	switch layout.Layout {
+	case LayoutSubfolder:
+		folder := strings.TrimSuffix(path.Base(inputFileName), path.Ext(inputFileName))
+		parent := path.Dir(inputFileName)
+		if layout.OutputDir != "" {
+			parent = layout.OutputDir
+		}
+		return path.Join(parent, folder, fileName), nil
+	case LayoutMirror:
+		relativeDir, relErr := filepath.Rel(layout.InputRoot, path.Dir(inputFileName))
+		if relErr != nil {
+			return "", relErr
+		}
+		return path.Join(layout.OutputDir, filepath.ToSlash(relativeDir), fileName), nil
+	case LayoutFlat:
+		return layout.placeFlat(inputFileName, fileName), nil
+	}
+	return outFileName, nil
+}
+
+// placeFlat keeps names from different input files apart by prefixing the
+// name of the input's folder, then a counter
+func (layout *OutputLayout) placeFlat(inputFileName string, fileName string) string {
+	candidate := path.Join(layout.OutputDir, fileName)
+	if owner, ok := layout.flatOwners[candidate]; ok && owner != inputFileName {
+		prefix := path.Base(path.Dir(inputFileName))
+		candidate = path.Join(layout.OutputDir, prefix+"."+fileName)
+		for n := 2; layout.flatOwners[candidate] != "" && layout.flatOwners[candidate] != inputFileName; n++ {
+			candidate = path.Join(layout.OutputDir, prefix+"."+strconv.Itoa(n)+"."+fileName)
+		}
+	}
+	layout.flatOwners[candidate] = inputFileName
+	return candidate
+}
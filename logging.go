@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// writerHook sends log entries of the given levels to a writer, so the
+// console and the log file can each get their own verbosity
+type writerHook struct {
+	writer    io.Writer
+	formatter logrus.Formatter
+	levels    []logrus.Level
+}
+
+func (hook *writerHook) Levels() []logrus.Level {
+	return hook.levels
+}
+
+func (hook *writerHook) Fire(entry *logrus.Entry) error {
+	line, formatErr := hook.formatter.Format(entry)
+	if formatErr != nil {
+		return formatErr
+	}
+	_, writeErr := hook.writer.Write(line)
+	return writeErr
+}
+
+func levelsUpTo(maxLevel logrus.Level) []logrus.Level {
+	levels := []logrus.Level{}
+	for _, level := range logrus.AllLevels {
+		if level <= maxLevel {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// setupLogging configures the console verbosity and the optional log file,
+// which always receives every message including debug output
+func setupLogging(quiet bool, verbose bool, logFileName string) (*os.File, error) {
+	if quiet && verbose {
+		return nil, errors.New("--quiet and --verbose are mutually exclusive")
+	}
+	consoleLevel := logrus.InfoLevel
+	if quiet {
+		consoleLevel = logrus.ErrorLevel
+	}
+	if verbose {
+		consoleLevel = logrus.DebugLevel
+	}
+	logrus.SetOutput(io.Discard)
+	logrus.SetLevel(logrus.DebugLevel)
+	logrus.AddHook(&writerHook{
+		writer:    os.Stderr,
+		formatter: &logrus.TextFormatter{},
+		levels:    levelsUpTo(consoleLevel),
+	})
+	var logFile *os.File
+	if logFileName != "" {
+		var openErr error
+		logFile, openErr = os.OpenFile(logFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if openErr != nil {
+			return nil, openErr
+		}
+		logrus.AddHook(&writerHook{
+			writer:    logFile,
+			formatter: &logrus.TextFormatter{DisableColors: true, FullTimestamp: true},
+			levels:    logrus.AllLevels,
+		})
+	}
+	logrus.Info("gmmmkvsubsextract - GMM MKV Subtitles Extract")
+	return logFile, nil
+}
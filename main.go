@@ -10,6 +10,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/devfacet/gocmd/v3"
 	"github.com/sirupsen/logrus"
@@ -32,6 +33,8 @@ type MKVTrackProperties struct {
 	TextSubtitles        bool    `json:"text_subtitles"`
 	NumberOfIndexEntries int     `json:"num_index_entries"`
 	Duration             string  `json:"tag_duration"`
+	Commentary           bool    `json:"flag_commentary"`
+	HearingImpaired      bool    `json:"flag_hearing_impaired"`
 	UId                  big.Int `json:"uid"`
 }
 
@@ -46,19 +49,44 @@ type MKVContainer struct {
 	Type string `json:"type"`
 }
 
+type MKVAttachment struct {
+	Id          int    `json:"id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+type MKVChapters struct {
+	NumberOfEntries int `json:"num_entries"`
+}
+
 type MKVInfo struct {
-	Tracks    []MKVTrack   `json:"tracks"`
-	Container MKVContainer `json:"container"`
+	Tracks      []MKVTrack      `json:"tracks"`
+	Container   MKVContainer    `json:"container"`
+	Attachments []MKVAttachment `json:"attachments"`
+	Chapters    []MKVChapters   `json:"chapters"`
 }
 
+// ConflictPolicy decides what happens when an output file already exists
+type ConflictPolicy int
+
+const (
+	ConflictPolicyFail ConflictPolicy = iota
+	ConflictPolicySkip
+	ConflictPolicyOverwrite
+	ConflictPolicyRename
+)
+
 var subtitleExtensionByCodec = map[string]string{
 	"S_TEXT/UTF8": "srt",
 	"S_TEXT/ASS":  "ass",
+	"S_TEXT/SSA":  "ssa",
 	"S_HDMV/PGS":  "sup",
+	"S_VOBSUB":    "idx",
 }
 
 func isMKVFile(inputFileName string) bool {
-	return strings.ToLower(inputFileName[len(inputFileName)-4:]) == ".mkv"
+	return strings.ToLower(path.Ext(inputFileName)) == ".mkv"
 }
 
 func buildSubtitlesFileName(inputFileName string, track MKVTrack) string {
@@ -79,6 +107,51 @@ func buildSubtitlesFileName(inputFileName string, track MKVTrack) string {
 	return outFileName
 }
 
+func conflictPolicyFromFlags(skipExisting bool, overwrite bool, renameOnConflict bool) (ConflictPolicy, error) {
+	count := 0
+	policy := ConflictPolicyFail
+	if skipExisting {
+		count++
+		policy = ConflictPolicySkip
+	}
+	if overwrite {
+		count++
+		policy = ConflictPolicyOverwrite
+	}
+	if renameOnConflict {
+		count++
+		policy = ConflictPolicyRename
+	}
+	if count > 1 {
+		return policy, errors.New("--skip-existing, --overwrite and --rename-on-conflict are mutually exclusive")
+	}
+	return policy, nil
+}
+
+// resolveOutputFileName applies the conflict policy to outFileName and
+// returns the name to write to, or skip=true when the track should be left alone
+func resolveOutputFileName(outFileName string, policy ConflictPolicy) (string, bool, error) {
+	if _, statErr := os.Stat(outFileName); os.IsNotExist(statErr) {
+		return outFileName, false, nil
+	}
+	switch policy {
+	case ConflictPolicySkip:
+		return outFileName, true, nil
+	case ConflictPolicyOverwrite:
+		return outFileName, false, nil
+	case ConflictPolicyRename:
+		extension := path.Ext(outFileName)
+		baseName := strings.TrimSuffix(outFileName, extension)
+		for i := 1; ; i++ {
+			candidate := fmt.Sprintf("%s.%d%s", baseName, i, extension)
+			if _, statErr := os.Stat(candidate); os.IsNotExist(statErr) {
+				return candidate, false, nil
+			}
+		}
+	}
+	return outFileName, false, fmt.Errorf("output file already exists: %s (use --skip-existing, --overwrite or --rename-on-conflict)", outFileName)
+}
+
 func extractSubtitles(inputFileName string, track MKVTrack, outFileName string) error {
 	cmd := exec.Command(
 		"mkvextract",
@@ -86,6 +159,9 @@ func extractSubtitles(inputFileName string, track MKVTrack, outFileName string)
 		"tracks",
 		fmt.Sprintf("%d:%v", track.Id, outFileName),
 	)
+	logrus.
+		WithField("cmd", cmd.String()).
+		Debug("Running mkvextract")
 	output, cmdErr := cmd.Output()
 	if cmdErr != nil {
 		logrus.
@@ -104,62 +180,462 @@ func extractSubtitles(inputFileName string, track MKVTrack, outFileName string)
 	return nil
 }
 
+// ExtractOptions holds the settings that apply to every extracted track
+type ExtractOptions struct {
+	ConflictPolicy ConflictPolicy
+	OCR            bool
+	OCRLang        string
+	TessdataDir    string
+	ToSRT          bool
+	Filter         TrackFilter
+	NameStyle      NameStyle
+	Exec           string
+	Retries        int
+	Verify         bool
+	Cache          *IdentifyCache
+	Picker         *trackPicker
+	Layout         *OutputLayout
+}
+
+func identifyFile(inputFileName string, retries int, cache *IdentifyCache) (MKVInfo, error) {
+	var mkvInfo MKVInfo
+	out, cached := cache.Get(inputFileName)
+	if !cached {
+		cmdErr := withRetries(retries, "Identifying "+inputFileName, func() error {
+			var err error
+			out, err = exec.Command("mkvmerge", "-J", inputFileName).Output()
+			return err
+		})
+		if cmdErr != nil {
+			logrus.
+				WithError(cmdErr).
+				Error("Error executing command")
+			return mkvInfo, cmdErr
+		}
+	}
+	jsonErr := json.Unmarshal(out, &mkvInfo)
+	if jsonErr != nil {
+		logrus.
+			WithError(jsonErr).
+			Error("Error parsing JSON")
+		return mkvInfo, jsonErr
+	}
+	if !(strings.ToLower(strings.TrimSpace(mkvInfo.Container.Type)) == "matroska") {
+		logrus.
+			WithField("containerType", mkvInfo.Container.Type).
+			Error("File is not a Matroska container")
+		return mkvInfo, errors.New("file is not a Matroska container")
+	}
+	if !cached {
+		cache.Put(inputFileName, out)
+	}
+	logrus.
+		WithField("inputFileName", inputFileName).
+		WithField("tracks", len(mkvInfo.Tracks)).
+		Debug("File identified")
+	return mkvInfo, nil
+}
+
+// extractTrack extracts and optionally converts one track, returning the files it produced
+func extractTrack(inputFileName string, track MKVTrack, outFileName string, options ExtractOptions) ([]string, error) {
+	logrus.
+		WithField("trackId", track.Id).
+		WithField("trackNumber", track.Properties.Number).
+		WithField("trackLanguage", track.Properties.Language).
+		WithField("trackCodec", track.Codec).
+		Infof("Extracting subtitles from track %d", track.Id)
+	outFileName, skip, conflictErr := resolveOutputFileName(outFileName, options.ConflictPolicy)
+	if conflictErr != nil {
+		logrus.WithError(conflictErr).Error("Output file already exists")
+		return nil, conflictErr
+	}
+	if skip {
+		logrus.
+			WithField("outFileName", outFileName).
+			Info("Output file already exists, skipping")
+		return nil, nil
+	}
+	extractSubsErr := withRetries(options.Retries, "Extracting track "+strconv.Itoa(track.Id), func() error {
+		return extractSubtitles(inputFileName, track, outFileName)
+	})
+	if extractSubsErr != nil {
+		logrus.WithError(extractSubsErr).Error("Error extracting subtitles")
+		return nil, extractSubsErr
+	}
+	producedFileNames := []string{outFileName}
+	ocr := options.OCR && isImageSubtitleCodec(track.Properties.CodecId)
+	toSRT := options.ToSRT && isASSSubtitleCodec(track.Properties.CodecId)
+	if ocr || toSRT {
+		srtFileName, skipSrt, srtConflictErr := resolveOutputFileName(replaceExtension(outFileName, "srt"), options.ConflictPolicy)
+		if srtConflictErr != nil {
+			logrus.WithError(srtConflictErr).Error("Output file already exists")
+			return producedFileNames, srtConflictErr
+		}
+		if skipSrt {
+			logrus.
+				WithField("srtFileName", srtFileName).
+				Info("SRT file already exists, skipping conversion")
+			return producedFileNames, nil
+		}
+		var convertErr error
+		if ocr {
+			convertErr = convertImageSubtitles(outFileName, track, srtFileName, options.OCRLang, options.TessdataDir)
+		} else {
+			convertErr = convertASSToSRT(outFileName, srtFileName)
+		}
+		if convertErr != nil {
+			logrus.WithError(convertErr).Error("Error converting subtitles to SRT")
+			return producedFileNames, convertErr
+		}
+		producedFileNames = append(producedFileNames, srtFileName)
+	}
+	return producedFileNames, nil
+}
+
+// processFile extracts the selected subtitle tracks of one MKV file
+func processFile(inputFileName string, options ExtractOptions, state *RunState, report *FileReport) error {
+	if state != nil && state.IsFileCompleted(inputFileName) {
+		logrus.
+			WithField("inputFileName", inputFileName).
+			Info("File already completed in a previous run, skipping")
+		report.Status = FileStatusSkipped
+		return nil
+	}
+	mkvInfo, identifyErr := identifyFile(inputFileName, options.Retries, options.Cache)
+	if identifyErr != nil {
+		return identifyErr
+	}
+	tracks := []MKVTrack{}
+	for _, track := range mkvInfo.Tracks {
+		if track.Type != "subtitles" {
+			continue
+		}
+		if skipReason := options.Filter.SkipReason(track); skipReason != "" {
+			logrus.
+				WithField("trackId", track.Id).
+				WithField("reason", skipReason).
+				Infof("Skipping track %d", track.Id)
+			continue
+		}
+		if state != nil && state.IsTrackCompleted(inputFileName, track.Id) {
+			logrus.
+				WithField("trackId", track.Id).
+				Infof("Track %d already completed in a previous run, skipping", track.Id)
+			continue
+		}
+		tracks = append(tracks, track)
+	}
+	if options.Picker != nil && len(tracks) > 0 {
+		var pickErr error
+		tracks, pickErr = options.Picker.Pick(inputFileName, tracks)
+		if pickErr != nil {
+			return pickErr
+		}
+	}
+	usedFileNames := map[string]bool{}
+	for _, track := range tracks {
+		outFileName, placeErr := options.Layout.Place(inputFileName, buildOutputFileName(inputFileName, track, options.NameStyle, usedFileNames))
+		if placeErr != nil {
+			return placeErr
+		}
+		if mkdirErr := os.MkdirAll(path.Dir(outFileName), 0755); mkdirErr != nil {
+			return mkdirErr
+		}
+		producedFileNames, extractTrackErr := extractTrack(inputFileName, track, outFileName, options)
+		report.addTrack(track, producedFileNames)
+		if extractTrackErr != nil {
+			return extractTrackErr
+		}
+		if options.Verify {
+			if verifyErr := verifyOutputs(producedFileNames); verifyErr != nil {
+				return verifyErr
+			}
+		}
+		if options.Exec != "" {
+			for _, producedFileName := range producedFileNames {
+				if hookErr := runExecHook(options.Exec, producedFileName, inputFileName, track); hookErr != nil {
+					return hookErr
+				}
+			}
+		}
+		if state != nil {
+			state.MarkTrackCompleted(inputFileName, track.Id)
+		}
+	}
+	if state != nil {
+		state.MarkFileCompleted(inputFileName)
+	}
+	return nil
+}
+
 func main() {
-	logrus.Println("gmmmkvsubsextract - GMM MKV Subtitles Extract")
 	flags := struct {
-		Extract string `short:"x" long:"extract" description:"Extract subtitles from MKV file" required:"true"`
+		Help             bool   `short:"h" long:"help" description:"Display usage" global:"true"`
+		Extract          string `short:"x" long:"extract" description:"Extract subtitles from an MKV file, or from every MKV file below a directory"`
+		SkipExisting     bool   `long:"skip-existing" description:"Skip tracks whose output file already exists"`
+		Overwrite        bool   `long:"overwrite" description:"Overwrite existing output files"`
+		RenameOnConflict bool   `long:"rename-on-conflict" description:"Write to a numbered file name when the output file already exists"`
+		OCR              bool   `long:"ocr" description:"Convert image-based subtitles (PGS, VobSub) to SRT using OCR"`
+		OCRLang          string `long:"ocr-lang" description:"OCR language as a 3-letter code (defaults to the track language)"`
+		TessdataDir      string `long:"tessdata-dir" description:"Directory of the Tesseract traineddata used for OCR, for own trained models or non-standard installs"`
+		ToSRT            bool   `long:"to-srt" description:"Convert extracted ASS/SSA subtitles to SRT using ffmpeg"`
+		ForcedOnly       bool   `long:"forced-only" description:"Only extract tracks flagged as forced"`
+		DefaultOnly      bool   `long:"default-only" description:"Only extract tracks flagged as default"`
+		SkipCommentary   bool   `long:"skip-commentary" description:"Skip commentary tracks (by flag or track name)"`
+		SkipSDH          bool   `long:"skip-sdh" description:"Skip SDH / hearing impaired tracks (by flag or track name)"`
+		MinEntries       int    `long:"min-entries" default:"0" description:"Skip tracks with fewer subtitle entries than this (from mkvmerge's num_index_entries)"`
+		NameMatch        string `long:"name-match" description:"Only extract tracks whose name matches this regular expression"`
+		NameExclude      string `long:"name-exclude" description:"Skip tracks whose name matches this regular expression, e.g. \"(?i)signs\""`
+		Retries          int    `long:"retries" default:"0" description:"Retry failed mkvmerge/mkvextract runs this many times with backoff"`
+		Verify           bool   `long:"verify" description:"Verify produced files (size, format header, cue count) and delete broken ones"`
+		State            string `long:"state" description:"Record completed files and tracks in this JSON state file"`
+		Resume           string `long:"resume" description:"Resume a run from a state file, skipping completed files and tracks"`
+		NoCache          bool   `long:"no-cache" description:"Always run mkvmerge -J instead of using the identify cache"`
+		CacheDir         string `long:"cache-dir" description:"Directory of the identify cache (defaults to the user cache directory)"`
+		Exec             string `long:"exec" description:"Run a command for every produced file; tokens: {file} {input} {lang} {track} {id} {codec}"`
+		NameStyle        string `long:"name-style" description:"Name files so media servers detect them: plex, jellyfin or kodi"`
+		Layout           string `long:"layout" description:"Where files go: next to the MKV file (default), flat, subfolder or mirror"`
+		OutputDir        string `long:"output-dir" description:"Output directory for --layout flat, subfolder or mirror"`
+		Interactive      bool   `short:"i" long:"interactive" description:"List the subtitle tracks of each file and choose which ones to extract"`
+		Report           string `long:"report" description:"Write a summary of the run to this file, as Markdown (.md) or CSV"`
+		Profile          string `long:"profile" description:"Load a named bundle of options (built-in: anime, plex) from the config file"`
+		Config           string `long:"config" env:"GMMMKVSUBSEXTRACT_CONFIG" description:"Path to the JSON config file" global:"true"`
+		Quiet            bool   `short:"q" long:"quiet" description:"Only print errors to the console" global:"true"`
+		Verbose          bool   `long:"verbose" description:"Print debug messages to the console" global:"true"`
+		LogFile          string `long:"log-file" description:"Append a full diagnostic log (including debug messages) to this file" global:"true"`
+		Mux              struct {
+			Settings       bool   `settings:"true" allow-unknown-arg:"true"`
+			Lang           string `long:"lang" default:"eng" description:"Language code of the subtitle track"`
+			Name           string `long:"name" description:"Track name (defaults to the language code)"`
+			Default        bool   `long:"default" description:"Set as default subtitle track"`
+			Forced         bool   `long:"forced" description:"Mark as forced subtitle track"`
+			RemoveExisting bool   `long:"remove-existing" description:"Remove all other subtitle tracks"`
+			Output         string `short:"o" long:"output" description:"Output file (defaults to <name>_with_subtitles.mkv)"`
+			Overwrite      bool   `long:"overwrite" description:"Overwrite the output file if it exists"`
+		} `command:"mux" description:"Insert a subtitle file into an MKV file: mux movie.mkv subs.srt [options...]"`
+		Chapters struct {
+			Settings  bool   `settings:"true" allow-unknown-arg:"true"`
+			Simple    bool   `long:"simple" description:"Write simple OGM-style chapters instead of XML"`
+			Output    string `short:"o" long:"output" description:"Output file (defaults to <name>_chapters.txt)"`
+			Overwrite bool   `long:"overwrite" description:"Overwrite the output file if it exists"`
+		} `command:"chapters" description:"Extract chapters from an MKV file: chapters movie.mkv [options...]"`
+		Attachments struct {
+			Settings  bool   `settings:"true" allow-unknown-arg:"true"`
+			Output    string `short:"o" long:"output" description:"Output directory (defaults to <name>_attachments)"`
+			Overwrite bool   `long:"overwrite" description:"Overwrite existing files in the output directory"`
+		} `command:"attachments" description:"Extract attachments (fonts, cover art) from an MKV file: attachments movie.mkv [options...]"`
+		Propedit struct {
+			Settings bool   `settings:"true" allow-unknown-arg:"true"`
+			Track    string `long:"track" description:"Track to edit: a track id (3) or the nth track of a type (s1, a1, v1)"`
+			Default  string `long:"default" description:"Set (yes) or clear (no) the default flag"`
+			Forced   string `long:"forced" description:"Set (yes) or clear (no) the forced flag"`
+			Lang     string `long:"lang" description:"Language code, e.g. eng or pt-BR"`
+			Name     string `long:"name" description:"Track name"`
+		} `command:"propedit" description:"Edit track flags, language and name in place: propedit movie.mkv --track s1 [options...]"`
+		Strip struct {
+			Settings  bool   `settings:"true" allow-unknown-arg:"true"`
+			Tracks    string `long:"tracks" description:"Comma separated ids of the subtitle tracks to drop, e.g. 3,4"`
+			Lang      string `long:"lang" description:"Comma separated languages whose subtitle tracks are dropped, e.g. ger,dut"`
+			Output    string `short:"o" long:"output" description:"Output file (defaults to <name>_stripped.mkv)"`
+			Overwrite bool   `long:"overwrite" description:"Overwrite the output file if it exists"`
+		} `command:"strip" description:"Remux an MKV file without selected subtitle tracks: strip movie.mkv --tracks 3,4 [options...]"`
+		Stats struct {
+			Settings bool   `settings:"true" allow-unknown-arg:"true"`
+			Lang     string `long:"lang" description:"Comma separated language codes to report missing subtitles for, e.g. eng,dut"`
+			NoCache  bool   `long:"no-cache" description:"Always run mkvmerge -J instead of using the identify cache"`
+		} `command:"stats" description:"Report subtitle languages and codecs of every MKV file below a directory: stats dir [options...]"`
+		Srt struct {
+			Shift struct {
+				Settings bool    `settings:"true" allow-unknown-arg:"true"`
+				Offset   float64 `long:"offset" description:"Seconds to add to every cue, negative to move cues earlier"`
+				Output   string  `short:"o" long:"output" description:"Output file (defaults to changing the file in place)"`
+			} `command:"shift" description:"Shift the timing of an SRT file: srt shift file.srt --offset -2.3"`
+			FixEncoding struct {
+				Settings bool   `settings:"true" allow-unknown-arg:"true"`
+				From     string `long:"from" description:"Source charset, e.g. windows-1250 (detected when omitted)"`
+				NoBackup bool   `long:"no-backup" description:"Do not keep the original file as <name>.bak"`
+			} `command:"fix-encoding" description:"Convert a subtitle file to UTF-8 in place: srt fix-encoding file.srt [options...]"`
+		} `command:"srt" description:"SRT file tools" nonempty:"true"`
+		Completion struct {
+			Settings bool `settings:"true" allow-unknown-arg:"true"`
+		} `command:"completion" description:"Print a shell completion script: completion bash|zsh|fish"`
 	}{}
 	_, extractHandleFlagErr := gocmd.HandleFlag("Extract", func(cmd *gocmd.Cmd, args []string) error {
-		var inputFileName = flags.Extract
-		if ifs, statErr := os.Stat(inputFileName); os.IsNotExist(statErr) || ifs.IsDir() {
-			logrus.
-				WithError(statErr).
-				WithField("inputFileName", inputFileName).
-				Errorf("File does not exist or is a directory: %s", inputFileName)
-			return statErr
+		logFile, loggingErr := setupLogging(flags.Quiet, flags.Verbose, flags.LogFile)
+		if loggingErr != nil {
+			return loggingErr
 		}
-		if !isMKVFile(inputFileName) {
-			logrus.
-				WithField("inputFileName", inputFileName).
-				Error("File is not an MKV file")
-			return errors.New("file is not an MKV file")
+		if logFile != nil {
+			defer logFile.Close()
 		}
-		out, cmdErr := exec.Command("mkvmerge", "-J", inputFileName).Output()
-		if cmdErr != nil {
+		conflictPolicy, policyErr := conflictPolicyFromFlags(flags.SkipExisting, flags.Overwrite, flags.RenameOnConflict)
+		if policyErr != nil {
 			logrus.
-				WithError(cmdErr).
-				Error("Error executing command")
-			return cmdErr
+				WithError(policyErr).
+				Error("Invalid conflict policy")
+			return policyErr
 		}
-		var mkvInfo MKVInfo
-		jsonErr := json.Unmarshal(out, &mkvInfo)
-		if jsonErr != nil {
+		nameStyle, nameStyleErr := parseNameStyle(flags.NameStyle)
+		if nameStyleErr != nil {
 			logrus.
-				WithError(jsonErr).
-				Error("Error parsing JSON")
-			return jsonErr
+				WithError(nameStyleErr).
+				Error("Invalid name style")
+			return nameStyleErr
+		}
+		if flags.Retries < 0 {
+			return errors.New("--retries must not be negative")
 		}
-		if !(strings.ToLower(strings.TrimSpace(mkvInfo.Container.Type)) == "matroska") {
+		if flags.MinEntries < 0 {
+			return errors.New("--min-entries must not be negative")
+		}
+		nameMatch, nameMatchErr := compileNamePattern("--name-match", flags.NameMatch)
+		if nameMatchErr != nil {
+			return nameMatchErr
+		}
+		nameExclude, nameExcludeErr := compileNamePattern("--name-exclude", flags.NameExclude)
+		if nameExcludeErr != nil {
+			return nameExcludeErr
+		}
+		options := ExtractOptions{
+			ConflictPolicy: conflictPolicy,
+			OCR:            flags.OCR,
+			OCRLang:        flags.OCRLang,
+			TessdataDir:    flags.TessdataDir,
+			ToSRT:          flags.ToSRT,
+			NameStyle:      nameStyle,
+			Exec:           flags.Exec,
+			Retries:        flags.Retries,
+			Verify:         flags.Verify,
+			Filter: TrackFilter{
+				ForcedOnly:     flags.ForcedOnly,
+				DefaultOnly:    flags.DefaultOnly,
+				SkipCommentary: flags.SkipCommentary,
+				SkipSDH:        flags.SkipSDH,
+				MinEntries:     flags.MinEntries,
+				NameMatch:      nameMatch,
+				NameExclude:    nameExclude,
+			},
+		}
+		config, configErr := loadConfig(flags.Config)
+		if configErr != nil {
 			logrus.
-				WithField("containerType", mkvInfo.Container.Type).
-				Error("File is not a Matroska container")
-			return errors.New("file is not a Matroska container")
+				WithError(configErr).
+				Error("Error loading config file")
+			return configErr
 		}
-		for _, track := range mkvInfo.Tracks {
-			if track.Type == "subtitles" {
+		options.Filter.IgnoreLanguages = config.IgnoreLanguages
+		if flags.Profile != "" {
+			profile, profileErr := config.Profile(flags.Profile)
+			if profileErr != nil {
 				logrus.
-					WithField("trackId", track.Id).
-					WithField("trackNumber", track.Properties.Number).
-					WithField("trackLanguage", track.Properties.Language).
-					WithField("trackCodec", track.Codec).
-					Infof("Extracting subtitles from track %d", track.Id)
-				outFileName := buildSubtitlesFileName(inputFileName, track)
-				extractSubsErr := extractSubtitles(inputFileName, track, outFileName)
-				if extractSubsErr != nil {
-					logrus.WithError(extractSubsErr).Error("Error extracting subtitles")
-					return extractSubsErr
-				}
+					WithError(profileErr).
+					Error("Error loading profile")
+				return profileErr
+			}
+			var applyErr error
+			options, applyErr = profile.Apply(options)
+			if applyErr != nil {
+				logrus.
+					WithError(applyErr).
+					WithField("profile", flags.Profile).
+					Error("Error applying profile")
+				return applyErr
+			}
+		}
+		if options.OCR {
+			// Without an OCR backend every file with an image-based track
+			// would fail, so the image-based tracks are only extracted
+			if _, lookErr := exec.LookPath("tesseract"); lookErr != nil {
+				logrus.
+					WithError(lookErr).
+					Warn("tesseract not found, image-based subtitles will not be converted to SRT")
+				options.OCR = false
+			}
+		}
+		if flags.Interactive {
+			options.Picker = newTrackPicker()
+		}
+		if !flags.NoCache {
+			cache, cacheErr := newIdentifyCache(flags.CacheDir)
+			if cacheErr != nil {
+				logrus.
+					WithError(cacheErr).
+					Warn("Identify cache disabled")
+			}
+			options.Cache = cache
+		}
+		inputFileNames, collectErr := collectInputFiles(flags.Extract)
+		if collectErr != nil {
+			return collectErr
+		}
+		layout, layoutErr := parseLayout(flags.Layout)
+		if layoutErr != nil {
+			return layoutErr
+		}
+		if layout == LayoutNextToSource && flags.OutputDir != "" {
+			return errors.New("--output-dir requires --layout flat, subfolder or mirror")
+		}
+		if layout != LayoutNextToSource {
+			outputLayout, outputLayoutErr := newOutputLayout(layout, flags.Extract, flags.OutputDir)
+			if outputLayoutErr != nil {
+				return outputLayoutErr
 			}
+			options.Layout = outputLayout
+		}
+		var state *RunState
+		if flags.Resume != "" {
+			var stateErr error
+			state, stateErr = loadRunState(flags.Resume)
+			if stateErr != nil {
+				logrus.
+					WithError(stateErr).
+					WithField("stateFileName", flags.Resume).
+					Error("Error loading state file")
+				return stateErr
+			}
+			if flags.State != "" {
+				state.fileName = flags.State
+			}
+		} else if flags.State != "" {
+			state = newRunState(flags.State)
+		}
+		failedFileNames := []string{}
+		reports := []FileReport{}
+		for _, inputFileName := range inputFileNames {
+			report := FileReport{InputFileName: inputFileName, Status: FileStatusCompleted}
+			startTime := time.Now()
+			processErr := processFile(inputFileName, options, state, &report)
+			if processErr != nil {
+				failedFileNames = append(failedFileNames, inputFileName)
+				report.Status = FileStatusFailed
+				report.Error = processErr.Error()
+			}
+			report.Duration = time.Since(startTime)
+			reports = append(reports, report)
+			if errors.Is(processErr, errPickerInterrupted) {
+				// Ctrl-C in the checklist stops the run, as it does elsewhere
+				break
+			}
+		}
+		if flags.Report != "" {
+			if reportErr := writeReport(flags.Report, reports); reportErr != nil {
+				logrus.
+					WithError(reportErr).
+					WithField("reportFileName", flags.Report).
+					Error("Error writing report")
+			} else {
+				logrus.
+					WithField("reportFileName", flags.Report).
+					Info("Report written")
+			}
+		}
+		if len(failedFileNames) > 0 {
+			for _, failedFileName := range failedFileNames {
+				logrus.
+					WithField("inputFileName", failedFileName).
+					Error("File failed")
+			}
+			return fmt.Errorf("%d of %d files failed", len(failedFileNames), len(inputFileNames))
 		}
 		return nil
 	})
@@ -169,6 +645,260 @@ func main() {
 			Errorf("Error handling flag")
 		os.Exit(ErrCodeFailure)
 	}
+	_, muxHandleFlagErr := gocmd.HandleFlag("Mux", func(cmd *gocmd.Cmd, args []string) error {
+		logFile, loggingErr := setupLogging(flags.Quiet, flags.Verbose, flags.LogFile)
+		if loggingErr != nil {
+			return loggingErr
+		}
+		if logFile != nil {
+			defer logFile.Close()
+		}
+		positional := commandArgs(cmd, "Mux")
+		if len(positional) != 2 {
+			return errors.New("usage: mux movie.mkv subs.srt [options...]")
+		}
+		return muxSubtitles(MuxOptions{
+			InputFileName:     positional[0],
+			SubtitlesFileName: positional[1],
+			OutputFileName:    flags.Mux.Output,
+			Language:          flags.Mux.Lang,
+			TrackName:         flags.Mux.Name,
+			Default:           flags.Mux.Default,
+			Forced:            flags.Mux.Forced,
+			RemoveExisting:    flags.Mux.RemoveExisting,
+			Overwrite:         flags.Mux.Overwrite,
+		})
+	})
+	if muxHandleFlagErr != nil {
+		logrus.
+			WithError(muxHandleFlagErr).
+			Errorf("Error handling flag")
+		os.Exit(ErrCodeFailure)
+	}
+	_, chaptersHandleFlagErr := gocmd.HandleFlag("Chapters", func(cmd *gocmd.Cmd, args []string) error {
+		logFile, loggingErr := setupLogging(flags.Quiet, flags.Verbose, flags.LogFile)
+		if loggingErr != nil {
+			return loggingErr
+		}
+		if logFile != nil {
+			defer logFile.Close()
+		}
+		positional := commandArgs(cmd, "Chapters")
+		if len(positional) != 1 {
+			return errors.New("usage: chapters movie.mkv [options...]")
+		}
+		return extractChapters(ChaptersOptions{
+			InputFileName:  positional[0],
+			OutputFileName: flags.Chapters.Output,
+			Simple:         flags.Chapters.Simple,
+			Overwrite:      flags.Chapters.Overwrite,
+		})
+	})
+	if chaptersHandleFlagErr != nil {
+		logrus.
+			WithError(chaptersHandleFlagErr).
+			Errorf("Error handling flag")
+		os.Exit(ErrCodeFailure)
+	}
+	_, attachmentsHandleFlagErr := gocmd.HandleFlag("Attachments", func(cmd *gocmd.Cmd, args []string) error {
+		logFile, loggingErr := setupLogging(flags.Quiet, flags.Verbose, flags.LogFile)
+		if loggingErr != nil {
+			return loggingErr
+		}
+		if logFile != nil {
+			defer logFile.Close()
+		}
+		positional := commandArgs(cmd, "Attachments")
+		if len(positional) != 1 {
+			return errors.New("usage: attachments movie.mkv [options...]")
+		}
+		return extractAttachments(AttachmentsOptions{
+			InputFileName: positional[0],
+			OutputDir:     flags.Attachments.Output,
+			Overwrite:     flags.Attachments.Overwrite,
+		})
+	})
+	if attachmentsHandleFlagErr != nil {
+		logrus.
+			WithError(attachmentsHandleFlagErr).
+			Errorf("Error handling flag")
+		os.Exit(ErrCodeFailure)
+	}
+
+	_, propeditHandleFlagErr := gocmd.HandleFlag("Propedit", func(cmd *gocmd.Cmd, args []string) error {
+		logFile, loggingErr := setupLogging(flags.Quiet, flags.Verbose, flags.LogFile)
+		if loggingErr != nil {
+			return loggingErr
+		}
+		if logFile != nil {
+			defer logFile.Close()
+		}
+		positional := commandArgs(cmd, "Propedit")
+		if len(positional) != 1 || flags.Propedit.Track == "" {
+			return errors.New("usage: propedit movie.mkv --track s1 [options...]")
+		}
+		return editTrackProperties(PropEditOptions{
+			InputFileName: positional[0],
+			Track:         flags.Propedit.Track,
+			Default:       flags.Propedit.Default,
+			Forced:        flags.Propedit.Forced,
+			Language:      flags.Propedit.Lang,
+			TrackName:     flags.Propedit.Name,
+		})
+	})
+	if propeditHandleFlagErr != nil {
+		logrus.
+			WithError(propeditHandleFlagErr).
+			Errorf("Error handling flag")
+		os.Exit(ErrCodeFailure)
+	}
+
+	_, stripHandleFlagErr := gocmd.HandleFlag("Strip", func(cmd *gocmd.Cmd, args []string) error {
+		logFile, loggingErr := setupLogging(flags.Quiet, flags.Verbose, flags.LogFile)
+		if loggingErr != nil {
+			return loggingErr
+		}
+		if logFile != nil {
+			defer logFile.Close()
+		}
+		positional := commandArgs(cmd, "Strip")
+		if len(positional) != 1 {
+			return errors.New("usage: strip movie.mkv --tracks 3,4 --lang ger [options...]")
+		}
+		trackIds, trackIdsErr := parseTrackIds(flags.Strip.Tracks)
+		if trackIdsErr != nil {
+			return trackIdsErr
+		}
+		options := StripOptions{
+			InputFileName:  positional[0],
+			OutputFileName: flags.Strip.Output,
+			TrackIds:       trackIds,
+			Overwrite:      flags.Strip.Overwrite,
+		}
+		for _, lang := range strings.Split(flags.Strip.Lang, ",") {
+			if lang = strings.TrimSpace(lang); lang != "" {
+				options.Languages = append(options.Languages, lang)
+			}
+		}
+		return stripSubtitles(options)
+	})
+	if stripHandleFlagErr != nil {
+		logrus.
+			WithError(stripHandleFlagErr).
+			Errorf("Error handling flag")
+		os.Exit(ErrCodeFailure)
+	}
+
+	_, statsHandleFlagErr := gocmd.HandleFlag("Stats", func(cmd *gocmd.Cmd, args []string) error {
+		logFile, loggingErr := setupLogging(flags.Quiet, flags.Verbose, flags.LogFile)
+		if loggingErr != nil {
+			return loggingErr
+		}
+		if logFile != nil {
+			defer logFile.Close()
+		}
+		positional := commandArgs(cmd, "Stats")
+		if len(positional) != 1 {
+			return errors.New("usage: stats dir [options...]")
+		}
+		options := StatsOptions{Input: positional[0]}
+		for _, lang := range strings.Split(flags.Stats.Lang, ",") {
+			if lang = strings.TrimSpace(lang); lang != "" {
+				options.Languages = append(options.Languages, lang)
+			}
+		}
+		if !flags.Stats.NoCache {
+			cache, cacheErr := newIdentifyCache("")
+			if cacheErr != nil {
+				logrus.
+					WithError(cacheErr).
+					Warn("Identify cache disabled")
+			}
+			options.Cache = cache
+		}
+		stats, statsErr := collectLibraryStats(options)
+		if statsErr != nil {
+			return statsErr
+		}
+		stats.Write(os.Stdout, options.Languages)
+		return nil
+	})
+	if statsHandleFlagErr != nil {
+		logrus.
+			WithError(statsHandleFlagErr).
+			Errorf("Error handling flag")
+		os.Exit(ErrCodeFailure)
+	}
+
+	_, srtShiftHandleFlagErr := gocmd.HandleFlag("Srt.Shift", func(cmd *gocmd.Cmd, args []string) error {
+		logFile, loggingErr := setupLogging(flags.Quiet, flags.Verbose, flags.LogFile)
+		if loggingErr != nil {
+			return loggingErr
+		}
+		if logFile != nil {
+			defer logFile.Close()
+		}
+		positional := commandArgs(cmd, "Srt.Shift")
+		if len(positional) != 1 {
+			return errors.New("usage: srt shift file.srt --offset seconds [options...]")
+		}
+		return shiftSRTFile(SRTShiftOptions{
+			InputFileName:  positional[0],
+			OutputFileName: flags.Srt.Shift.Output,
+			OffsetSeconds:  flags.Srt.Shift.Offset,
+		})
+	})
+	if srtShiftHandleFlagErr != nil {
+		logrus.
+			WithError(srtShiftHandleFlagErr).
+			Errorf("Error handling flag")
+		os.Exit(ErrCodeFailure)
+	}
+
+	_, srtFixEncodingHandleFlagErr := gocmd.HandleFlag("Srt.FixEncoding", func(cmd *gocmd.Cmd, args []string) error {
+		logFile, loggingErr := setupLogging(flags.Quiet, flags.Verbose, flags.LogFile)
+		if loggingErr != nil {
+			return loggingErr
+		}
+		if logFile != nil {
+			defer logFile.Close()
+		}
+		positional := commandArgs(cmd, "Srt.FixEncoding")
+		if len(positional) != 1 {
+			return errors.New("usage: srt fix-encoding file.srt [options...]")
+		}
+		return fixSRTEncoding(SRTFixEncodingOptions{
+			InputFileName: positional[0],
+			From:          flags.Srt.FixEncoding.From,
+			NoBackup:      flags.Srt.FixEncoding.NoBackup,
+		})
+	})
+	if srtFixEncodingHandleFlagErr != nil {
+		logrus.
+			WithError(srtFixEncodingHandleFlagErr).
+			Errorf("Error handling flag")
+		os.Exit(ErrCodeFailure)
+	}
+
+	_, completionHandleFlagErr := gocmd.HandleFlag("Completion", func(cmd *gocmd.Cmd, args []string) error {
+		positional := commandArgs(cmd, "Completion")
+		if len(positional) != 1 {
+			return errors.New("usage: completion bash|zsh|fish")
+		}
+		script, scriptErr := completionScript(positional[0], "gmmmkvsubsextract", &flags)
+		if scriptErr != nil {
+			return scriptErr
+		}
+		fmt.Print(script)
+		return nil
+	})
+	if completionHandleFlagErr != nil {
+		logrus.
+			WithError(completionHandleFlagErr).
+			Errorf("Error handling flag")
+		os.Exit(ErrCodeFailure)
+	}
+	os.Args = joinNegativeOptionValues(os.Args, "--offset")
 	_, cmdErr := gocmd.New(gocmd.Options{
 		Name:        "gmmmkvsubsextract",
 		Description: "GMM MKV Subtitles Extract",
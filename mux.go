@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/devfacet/gocmd/v3"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	mkvmergeExitCodeWarnings = 1
+)
+
+// MuxOptions describes a subtitle file to be muxed into an MKV file, mirroring the GUI's Insert tab
+type MuxOptions struct {
+	InputFileName     string
+	SubtitlesFileName string
+	OutputFileName    string
+	Language          string
+	TrackName         string
+	Default           bool
+	Forced            bool
+	RemoveExisting    bool
+	Overwrite         bool
+}
+
+// commandArgs returns the positional arguments given to a subcommand
+func commandArgs(cmd *gocmd.Cmd, name string) []string {
+	positional := []string{}
+	flagArgs := cmd.FlagArgs(name)
+	if len(flagArgs) < 2 {
+		return positional
+	}
+	for _, arg := range flagArgs[1:] {
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+		}
+	}
+	return positional
+}
+
+func buildMuxOutputFileName(inputFileName string) string {
+	extension := path.Ext(inputFileName)
+	return strings.TrimSuffix(inputFileName, extension) + "_with_subtitles.mkv"
+}
+
+func buildMuxArgs(options MuxOptions) []string {
+	args := []string{"-o", options.OutputFileName}
+	if options.RemoveExisting {
+		args = append(args, "--no-subtitles")
+	}
+	args = append(args,
+		options.InputFileName,
+		"--language", "0:"+options.Language,
+		"--track-name", "0:"+options.TrackName,
+	)
+	if options.Default {
+		args = append(args, "--default-track", "0:yes")
+	}
+	if options.Forced {
+		args = append(args, "--forced-track", "0:yes")
+	}
+	return append(args, options.SubtitlesFileName)
+}
+
+// runMkvmerge runs mkvmerge, treating its "finished with warnings" exit code as success
+func runMkvmerge(args []string) error {
+	cmd := exec.Command("mkvmerge", args...)
+	logrus.
+		WithField("cmd", cmd.String()).
+		Debug("Running mkvmerge")
+	output, cmdErr := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(cmdErr, &exitErr) && exitErr.ExitCode() == mkvmergeExitCodeWarnings {
+		logrus.
+			WithField("output", string(output)).
+			Warn("mkvmerge finished with warnings")
+		return nil
+	}
+	if cmdErr != nil {
+		logrus.
+			WithField("cmd", cmd).
+			WithError(cmdErr).
+			Error("Error executing mkvmerge")
+		fmt.Println(string(output))
+		return cmdErr
+	}
+	return nil
+}
+
+func muxSubtitles(options MuxOptions) error {
+	if ifs, statErr := os.Stat(options.InputFileName); os.IsNotExist(statErr) || ifs.IsDir() || !isMKVFile(options.InputFileName) {
+		logrus.
+			WithField("inputFileName", options.InputFileName).
+			Error("Input is not an existing MKV file")
+		return fmt.Errorf("not an existing MKV file: %s", options.InputFileName)
+	}
+	if ifs, statErr := os.Stat(options.SubtitlesFileName); os.IsNotExist(statErr) || ifs.IsDir() {
+		logrus.
+			WithField("subtitlesFileName", options.SubtitlesFileName).
+			Error("Subtitles file does not exist")
+		return fmt.Errorf("subtitles file does not exist: %s", options.SubtitlesFileName)
+	}
+	if options.OutputFileName == "" {
+		options.OutputFileName = buildMuxOutputFileName(options.InputFileName)
+	} else if !isMKVFile(options.OutputFileName) {
+		options.OutputFileName += ".mkv"
+	}
+	if options.OutputFileName == options.InputFileName {
+		return errors.New("output file must differ from the input file")
+	}
+	policy := ConflictPolicyFail
+	if options.Overwrite {
+		policy = ConflictPolicyOverwrite
+	}
+	if _, _, conflictErr := resolveOutputFileName(options.OutputFileName, policy); conflictErr != nil {
+		logrus.WithError(conflictErr).Error("Output file already exists")
+		return conflictErr
+	}
+	if options.TrackName == "" {
+		options.TrackName = options.Language
+	}
+	logrus.
+		WithField("inputFileName", options.InputFileName).
+		WithField("subtitlesFileName", options.SubtitlesFileName).
+		WithField("language", options.Language).
+		Info("Adding subtitles to MKV file")
+	if muxErr := runMkvmerge(buildMuxArgs(options)); muxErr != nil {
+		return muxErr
+	}
+	logrus.
+		WithField("outFileName", options.OutputFileName).
+		Info("Subtitles added")
+	return nil
+}
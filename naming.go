@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// NameStyle selects how extracted subtitle files are named
+type NameStyle string
+
+const (
+	NameStyleDefault  NameStyle = ""
+	NameStylePlex     NameStyle = "plex"
+	NameStyleJellyfin NameStyle = "jellyfin"
+	NameStyleKodi     NameStyle = "kodi"
+)
+
+func parseNameStyle(name string) (NameStyle, error) {
+	switch style := NameStyle(strings.ToLower(strings.TrimSpace(name))); style {
+	case NameStyleDefault, NameStylePlex, NameStyleJellyfin, NameStyleKodi:
+		return style, nil
+	}
+	return NameStyleDefault, fmt.Errorf("unknown name style %q (expected plex, jellyfin or kodi)", name)
+}
+
+// twoLetterLanguageCodes maps 3-letter language codes to the 2-letter codes media servers expect
+var twoLetterLanguageCodes = map[string]string{
+	"eng": "en",
+	"fre": "fr",
+	"fra": "fr",
+	"ger": "de",
+	"deu": "de",
+	"ita": "it",
+	"spa": "es",
+	"por": "pt",
+	"dut": "nl",
+	"nld": "nl",
+	"swe": "sv",
+	"nor": "no",
+	"dan": "da",
+	"fin": "fi",
+	"jpn": "ja",
+	"kor": "ko",
+	"chi": "zh",
+	"zho": "zh",
+	"rus": "ru",
+	"pol": "pl",
+	"cze": "cs",
+	"ces": "cs",
+	"hun": "hu",
+	"gre": "el",
+	"ell": "el",
+	"tur": "tr",
+	"ara": "ar",
+	"heb": "he",
+	"tha": "th",
+}
+
+// sidecarLanguage returns the 2-letter code media servers match most
+// reliably, falling back to the track's own code
+func sidecarLanguage(track MKVTrack) string {
+	lang := strings.ToLower(track.Properties.Language)
+	if code, ok := twoLetterLanguageCodes[lang]; ok {
+		return code
+	}
+	if lang == "" {
+		return "und"
+	}
+	return lang
+}
+
+// buildSidecarFileName names the file the way media servers auto-detect
+// external subtitles, e.g. "Movie (2020).en.forced.srt"
+func buildSidecarFileName(inputFileName string, track MKVTrack, style NameStyle) string {
+	baseName := strings.TrimSuffix(inputFileName, path.Ext(inputFileName))
+	parts := []string{baseName, sidecarLanguage(track)}
+	switch style {
+	case NameStylePlex:
+		if track.Properties.Forced {
+			parts = append(parts, "forced")
+		}
+		if isSDHTrack(track) {
+			parts = append(parts, "sdh")
+		}
+	case NameStyleJellyfin:
+		if track.Properties.Default {
+			parts = append(parts, "default")
+		}
+		if track.Properties.Forced {
+			parts = append(parts, "forced")
+		}
+		if isSDHTrack(track) {
+			parts = append(parts, "sdh")
+		}
+	case NameStyleKodi:
+		if track.Properties.Forced {
+			parts = append(parts, "forced")
+		}
+		if isSDHTrack(track) {
+			parts = append(parts, "hi")
+		}
+	}
+	parts = append(parts, subtitleExtensionByCodec[track.Properties.CodecId])
+	return strings.Join(parts, ".")
+}
+
+// buildOutputFileName picks the output name for a track and keeps it unique
+// within one input file, since several tracks can share a sidecar name
+func buildOutputFileName(inputFileName string, track MKVTrack, style NameStyle, usedFileNames map[string]bool) string {
+	if style == NameStyleDefault {
+		return buildSubtitlesFileName(inputFileName, track)
+	}
+	outFileName := buildSidecarFileName(inputFileName, track, style)
+	if usedFileNames[outFileName] {
+		// The track number goes before the language so servers still detect it
+		baseName := strings.TrimSuffix(inputFileName, path.Ext(inputFileName))
+		outFileName = fmt.Sprintf("%s.track%d%s", baseName, track.Properties.Number, strings.TrimPrefix(outFileName, baseName))
+	}
+	usedFileNames[outFileName] = true
+	return outFileName
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	CodecIdPGS    = "S_HDMV/PGS"
+	CodecIdVobSub = "S_VOBSUB"
+)
+
+// tesseractLanguageCodes maps the ISO 639-2/B codes used by Matroska to the
+// ISO 639-2/T codes Tesseract names its traineddata files after
+var tesseractLanguageCodes = map[string]string{
+	"fre": "fra",
+	"ger": "deu",
+	"dut": "nld",
+	"cze": "ces",
+	"gre": "ell",
+	"rum": "ron",
+	"slo": "slk",
+	"chi": "chi_sim",
+	"per": "fas",
+	"ice": "isl",
+	"mac": "mkd",
+	"alb": "sqi",
+	"arm": "hye",
+	"baq": "eus",
+	"bur": "mya",
+	"geo": "kat",
+	"may": "msa",
+	"wel": "cym",
+}
+
+func isImageSubtitleCodec(codecId string) bool {
+	return codecId == CodecIdPGS || codecId == CodecIdVobSub
+}
+
+// ocrLanguage returns the 3-letter OCR language for a track, preferring the
+// language given on the command line over the one stored in the file
+func ocrLanguage(track MKVTrack, ocrLang string) string {
+	lang := strings.ToLower(strings.TrimSpace(ocrLang))
+	if lang == "" {
+		lang = strings.ToLower(track.Properties.Language)
+	}
+	if lang == "" || lang == "und" {
+		lang = "eng"
+	}
+	return lang
+}
+
+// convertImageSubtitles converts an image-based track to SRT. A non-empty
+// tessdataDir replaces the directory the traineddata is read from.
+func convertImageSubtitles(subsFileName string, track MKVTrack, srtFileName string, ocrLang string, tessdataDir string) error {
+	lang := ocrLanguage(track, ocrLang)
+	switch track.Properties.CodecId {
+	case CodecIdPGS:
+		return convertPGSToSRT(subsFileName, srtFileName, lang, tessdataDir)
+	case CodecIdVobSub:
+		return convertVobSubToSRT(subsFileName, srtFileName, lang, tessdataDir)
+	}
+	return fmt.Errorf("codec %s cannot be converted with OCR", track.Properties.CodecId)
+}
+
+// convertPGSToSRT reads the subtitle bitmaps of a PGS (.sup) file with the
+// SUP parser of pgs.go and writes the text Tesseract recognizes in them as SRT
+func convertPGSToSRT(supFileName string, srtFileName string, lang string, tessdataDir string) error {
+	data, readErr := os.ReadFile(supFileName)
+	if readErr != nil {
+		return readErr
+	}
+	cues, parseErr := parsePGSCues(data)
+	if parseErr != nil && len(cues) == 0 {
+		return parseErr
+	}
+	if len(cues) == 0 {
+		return fmt.Errorf("no subtitles found in %s", supFileName)
+	}
+	if ocrErr := ocrCuesToSRT(cues, srtFileName, lang, tessdataDir); ocrErr != nil {
+		logrus.
+			WithField("supFileName", supFileName).
+			WithError(ocrErr).
+			Error("Error executing PGS to SRT conversion")
+		return ocrErr
+	}
+	logrus.
+		WithField("srtFileName", srtFileName).
+		WithField("ocrLang", lang).
+		WithField("subtitles", len(cues)).
+		Info("PGS subtitles converted to SRT")
+	return nil
+}
+
+// ocrCuesToSRT recognizes the text of subtitle bitmaps and writes it as SRT.
+// Bitmaps without any text read are left out, and a subtitle repeated in
+// back-to-back cues, as fades do, is written once.
+func ocrCuesToSRT(cues []BitmapCue, srtFileName string, lang string, tessdataDir string) error {
+	if code, ok := tesseractLanguageCodes[lang]; ok {
+		lang = code
+	}
+	texts, recognizeErr := recognizeCues(cues, lang, tessdataDir)
+	if recognizeErr != nil {
+		return recognizeErr
+	}
+	var srt strings.Builder
+	written := 0
+	for i := 0; i < len(cues); i++ {
+		if texts[i] == "" {
+			continue
+		}
+		start, end := cues[i].Start, cues[i].End
+		for i+1 < len(cues) && texts[i+1] == texts[i] && cues[i+1].Start <= end {
+			i++
+			end = cues[i].End
+		}
+		written++
+		fmt.Fprintf(&srt, "%d\n%s --> %s\n%s\n\n", written,
+			formatSRTTimestamp(int(start.Milliseconds()), ","), formatSRTTimestamp(int(end.Milliseconds()), ","), texts[i])
+	}
+	return os.WriteFile(srtFileName, []byte(srt.String()), 0644)
+}
+
+// recognizeCues reads the text of every subtitle bitmap with the tesseract
+// command, one bitmap per CPU core at a time. Tesseract runs as a command
+// rather than through a CGo binding such as gosseract, so the CLI still
+// builds without a C toolchain and the Tesseract headers.
+func recognizeCues(cues []BitmapCue, lang string, tessdataDir string) ([]string, error) {
+	if _, lookErr := exec.LookPath("tesseract"); lookErr != nil {
+		return nil, fmt.Errorf("tesseract not found: %w", lookErr)
+	}
+	workDir, tempErr := os.MkdirTemp("", "gmmmkvsubsextract-ocr-*")
+	if tempErr != nil {
+		return nil, tempErr
+	}
+	defer os.RemoveAll(workDir)
+
+	texts := make([]string, len(cues))
+	errs := make([]error, len(cues))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), len(cues)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				imageFileName := path.Join(workDir, fmt.Sprintf("cue%d.png", i))
+				texts[i], errs[i] = recognizeBitmap(cues[i].Image, lang, tessdataDir, imageFileName)
+				os.Remove(imageFileName)
+			}
+		}()
+	}
+	for i := range cues {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("subtitle %d: %w", i+1, err)
+		}
+	}
+	return texts, nil
+}
+
+// recognizeBitmap writes a subtitle bitmap as the black text on a white
+// background Tesseract reads best to imageFileName and returns the text read
+func recognizeBitmap(img image.Image, lang string, tessdataDir string, imageFileName string) (string, error) {
+	imageFile, createErr := os.Create(imageFileName)
+	if createErr != nil {
+		return "", createErr
+	}
+	encodeErr := png.Encode(imageFile, ocrImage(img))
+	imageFile.Close()
+	if encodeErr != nil {
+		return "", encodeErr
+	}
+	// Page segmentation mode 6 reads the bitmap as one block of text
+	args := []string{imageFileName, "stdout", "-l", lang, "--psm", "6"}
+	if tessdataDir != "" {
+		args = append(args, "--tessdata-dir", tessdataDir)
+	}
+	var stderr strings.Builder
+	cmd := exec.Command("tesseract", args...)
+	cmd.Stderr = &stderr
+	// Bitmaps are recognized in parallel, so Tesseract's own threads would
+	// only compete with each other
+	cmd.Env = append(os.Environ(), "OMP_THREAD_LIMIT=1")
+	output, cmdErr := cmd.Output()
+	if cmdErr != nil {
+		return "", fmt.Errorf("tesseract: %w: %s", cmdErr, strings.TrimSpace(stderr.String()))
+	}
+	lines := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ocrImage turns a subtitle bitmap into black text on a white background: the
+// opaque, light fill of the letters becomes black and everything else,
+// including their dark outline, white. A border keeps the text off the edges.
+func ocrImage(img image.Image) *image.Gray {
+	const padding = 10
+	b := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, b.Dx()+2*padding, b.Dy()+2*padding))
+	for i := range gray.Pix {
+		gray.Pix[i] = 0xff
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			luma := (299*int(c.R) + 587*int(c.G) + 114*int(c.B)) / 1000
+			if c.A >= 0x80 && luma >= 128 {
+				gray.SetGray(x-b.Min.X+padding, y-b.Min.Y+padding, color.Gray{})
+			}
+		}
+	}
+	return gray
+}
+
+// convertVobSubToSRT decodes the subtitle bitmaps of a VobSub .idx/.sub pair
+// with the decoder of vobsub.go and writes the text Tesseract recognizes in
+// them as SRT, the same way as PGS subtitles
+func convertVobSubToSRT(idxFileName string, srtFileName string, lang string, tessdataDir string) error {
+	cues, parseErr := parseVobSubCues(idxFileName)
+	if parseErr != nil {
+		return parseErr
+	}
+	if len(cues) == 0 {
+		return fmt.Errorf("no subtitles found in %s", idxFileName)
+	}
+	if ocrErr := ocrCuesToSRT(cues, srtFileName, lang, tessdataDir); ocrErr != nil {
+		logrus.
+			WithField("idxFileName", idxFileName).
+			WithError(ocrErr).
+			Error("Error executing VobSub to SRT conversion")
+		return ocrErr
+	}
+	logrus.
+		WithField("srtFileName", srtFileName).
+		WithField("ocrLang", lang).
+		WithField("subtitles", len(cues)).
+		Info("VobSub subtitles converted to SRT")
+	return nil
+}
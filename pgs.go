@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+)
+
+// PGS segment types
+const (
+	pgsPaletteSegment     = 0x14
+	pgsObjectSegment      = 0x15
+	pgsCompositionSegment = 0x16
+	pgsEndSegment         = 0x80
+)
+
+// defaultCueDuration is how long a subtitle is shown when its stream does not
+// say when it ends, such as the last subtitle of a PGS stream
+const defaultCueDuration = 3 * time.Second
+
+// BitmapCue is a subtitle of an image-based stream: the bitmap shown from
+// Start to End
+type BitmapCue struct {
+	Start time.Duration
+	End   time.Duration
+	Image image.Image
+}
+
+// pgsObject is the run-length encoded bitmap of a PGS object, which may be
+// split over several segments
+type pgsObject struct {
+	Width, Height int
+	Data          []byte
+}
+
+// pgsPlacement is an object shown by a composition, at its position on screen
+type pgsPlacement struct {
+	ID   uint16
+	X, Y int
+}
+
+// parsePGSCues reads the display sets of a PGS (.sup) stream and returns a
+// cue for every composition that shows objects, ended by the composition
+// that follows it. The objects of a composition are drawn into one bitmap.
+func parsePGSCues(data []byte) ([]BitmapCue, error) {
+	var cues []BitmapCue
+	palettes := map[byte]*[256]color.NRGBA{}
+	objects := map[uint16]*pgsObject{}
+	var pts time.Duration
+	var paletteID byte
+	var placements []pgsPlacement
+
+	for pos := 0; pos+13 <= len(data); {
+		if data[pos] != 'P' || data[pos+1] != 'G' {
+			return cues, fmt.Errorf("invalid PGS segment at offset %d", pos)
+		}
+		segPTS := time.Duration(binary.BigEndian.Uint32(data[pos+2:])) * time.Second / 90000
+		segType := data[pos+10]
+		size := int(binary.BigEndian.Uint16(data[pos+11:]))
+		if pos+13+size > len(data) {
+			break
+		}
+		seg := data[pos+13 : pos+13+size]
+		pos += 13 + size
+
+		switch segType {
+		case pgsCompositionSegment:
+			if len(seg) < 11 {
+				continue
+			}
+			// Any composition ends the cue shown before it
+			if n := len(cues); n > 0 && cues[n-1].End == 0 {
+				cues[n-1].End = segPTS
+			}
+			pts = segPTS
+			// An epoch start discards the objects and palettes of earlier epochs
+			if seg[7]&0x80 != 0 {
+				palettes = map[byte]*[256]color.NRGBA{}
+				objects = map[uint16]*pgsObject{}
+			}
+			paletteID = seg[9]
+			placements = nil
+			for i, n := 11, int(seg[10]); n > 0 && i+8 <= len(seg); n-- {
+				placements = append(placements, pgsPlacement{
+					ID: binary.BigEndian.Uint16(seg[i:]),
+					X:  int(binary.BigEndian.Uint16(seg[i+4:])),
+					Y:  int(binary.BigEndian.Uint16(seg[i+6:])),
+				})
+				// Cropped objects carry their cropping rectangle
+				if seg[i+3]&0x80 != 0 {
+					i += 16
+				} else {
+					i += 8
+				}
+			}
+		case pgsPaletteSegment:
+			if len(seg) < 2 {
+				continue
+			}
+			palette, ok := palettes[seg[0]]
+			if !ok {
+				palette = &[256]color.NRGBA{}
+				palettes[seg[0]] = palette
+			}
+			// Entries of ID, Y, Cr, Cb and alpha
+			for i := 2; i+5 <= len(seg); i += 5 {
+				r, g, b := color.YCbCrToRGB(seg[i+1], seg[i+3], seg[i+2])
+				palette[seg[i]] = color.NRGBA{R: r, G: g, B: b, A: seg[i+4]}
+			}
+		case pgsObjectSegment:
+			if len(seg) < 4 {
+				continue
+			}
+			id := binary.BigEndian.Uint16(seg)
+			body := seg[4:]
+			if seg[3]&0x80 != 0 {
+				if len(body) < 7 {
+					continue
+				}
+				objects[id] = &pgsObject{
+					Width:  int(binary.BigEndian.Uint16(body[3:])),
+					Height: int(binary.BigEndian.Uint16(body[5:])),
+					Data:   append([]byte(nil), body[7:]...),
+				}
+			} else if object, ok := objects[id]; ok {
+				object.Data = append(object.Data, body...)
+			}
+		case pgsEndSegment:
+			if len(placements) == 0 {
+				continue
+			}
+			palette, ok := palettes[paletteID]
+			if !ok {
+				continue
+			}
+			if img := composePGSObjects(placements, objects, palette); img != nil {
+				cues = append(cues, BitmapCue{Start: pts, Image: img})
+			}
+			placements = nil
+		}
+	}
+
+	if n := len(cues); n > 0 && cues[n-1].End == 0 {
+		cues[n-1].End = cues[n-1].Start + defaultCueDuration
+	}
+	return cues, nil
+}
+
+// composePGSObjects draws the objects of a composition into one bitmap that
+// covers them all, or returns nil when none of them has been received
+func composePGSObjects(placements []pgsPlacement, objects map[uint16]*pgsObject, palette *[256]color.NRGBA) image.Image {
+	var bounds image.Rectangle
+	var decoded []image.Image
+	var origins []image.Point
+	for _, p := range placements {
+		object, ok := objects[p.ID]
+		if !ok || object.Width == 0 || object.Height == 0 {
+			continue
+		}
+		decoded = append(decoded, decodePGSRLE(object.Data, object.Width, object.Height, palette))
+		origin := image.Pt(p.X, p.Y)
+		origins = append(origins, origin)
+		bounds = bounds.Union(image.Rectangle{Min: origin, Max: origin.Add(image.Pt(object.Width, object.Height))})
+	}
+	if len(decoded) == 0 {
+		return nil
+	}
+	if len(decoded) == 1 {
+		return decoded[0]
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for i, object := range decoded {
+		offset := origins[i].Sub(bounds.Min)
+		b := object.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if c := object.At(x, y).(color.NRGBA); c.A > 0 {
+					img.SetNRGBA(x+offset.X, y+offset.Y, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// decodePGSRLE decodes the run-length encoded pixels of a PGS object
+func decodePGSRLE(data []byte, width, height int, palette *[256]color.NRGBA) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	x, y := 0, 0
+	fill := func(run int, index byte) {
+		for ; run > 0 && x < width; run-- {
+			img.SetNRGBA(x, y, palette[index])
+			x++
+		}
+	}
+
+	for i := 0; i < len(data) && y < height; {
+		b := data[i]
+		i++
+		if b != 0 {
+			fill(1, b)
+			continue
+		}
+		if i >= len(data) {
+			break
+		}
+		flags := data[i]
+		i++
+		if flags == 0 {
+			// End of line
+			x = 0
+			y++
+			continue
+		}
+
+		run := int(flags & 0x3f)
+		if flags&0x40 != 0 {
+			if i >= len(data) {
+				break
+			}
+			run = run<<8 | int(data[i])
+			i++
+		}
+		var index byte
+		if flags&0x80 != 0 {
+			if i >= len(data) {
+				break
+			}
+			index = data[i]
+			i++
+		}
+		fill(run, index)
+	}
+	return img
+}
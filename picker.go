@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// trackPicker asks on the terminal which of the detected subtitle tracks to
+// extract. On a terminal, over SSH too, it shows a checklist navigated with
+// the arrow keys; without one it reads whole lines.
+type trackPicker struct {
+	input    *bufio.Reader
+	output   io.Writer
+	terminal *os.File // Switched to single key presses while picking
+}
+
+// errPickerInterrupted is returned when Ctrl-C is pressed in the checklist,
+// which reads it as a key instead of letting it stop the program
+var errPickerInterrupted = errors.New("track selection interrupted")
+
+func newTrackPicker() *trackPicker {
+	return &trackPicker{input: bufio.NewReader(os.Stdin), output: os.Stderr, terminal: os.Stdin}
+}
+
+func describeTrack(track MKVTrack) string {
+	flags := []string{}
+	if track.Properties.Default {
+		flags = append(flags, "default")
+	}
+	if track.Properties.Forced {
+		flags = append(flags, "forced")
+	}
+	if isSDHTrack(track) {
+		flags = append(flags, "sdh")
+	}
+	if isCommentaryTrack(track) {
+		flags = append(flags, "commentary")
+	}
+	description := fmt.Sprintf("track %d  %-3s  %s", track.Id, track.Properties.Language, track.Codec)
+	if track.Properties.TrackName != "" {
+		description += fmt.Sprintf("  %q", track.Properties.TrackName)
+	}
+	if len(flags) > 0 {
+		description += "  [" + strings.Join(flags, ", ") + "]"
+	}
+	return description
+}
+
+// Pick lists the tracks as a checklist and returns the ones the user
+// selects. The whole list is selected until the user changes it.
+func (picker *trackPicker) Pick(inputFileName string, tracks []MKVTrack) ([]MKVTrack, error) {
+	selected := make([]bool, len(tracks))
+	for i := range selected {
+		selected[i] = true
+	}
+	if picker.terminal != nil {
+		if restore, rawErr := makeRawTerminal(picker.terminal); rawErr == nil {
+			defer restore()
+			return picker.pickWithCursor(inputFileName, tracks, selected)
+		}
+	}
+	return picker.pickByNumber(inputFileName, tracks, selected)
+}
+
+// pickWithCursor draws the checklist in place after every key press: the
+// arrow keys or j and k move the cursor, Space toggles the track under it
+func (picker *trackPicker) pickWithCursor(inputFileName string, tracks []MKVTrack, selected []bool) ([]MKVTrack, error) {
+	cursor := 0
+	fmt.Fprintf(picker.output, "\n%s\n", inputFileName)
+	for drawn := false; ; drawn = true {
+		if drawn {
+			// Back up to the first track to draw over the list
+			fmt.Fprintf(picker.output, "\x1b[%dA", len(tracks)+1)
+		}
+		for i, track := range tracks {
+			pointer, mark := " ", " "
+			if i == cursor {
+				pointer = ">"
+			}
+			if selected[i] {
+				mark = "x"
+			}
+			fmt.Fprintf(picker.output, "\r\x1b[K%s [%s] %s\n", pointer, mark, describeTrack(track))
+		}
+		fmt.Fprint(picker.output, "\r\x1b[KUp/Down = move, Space = toggle, a = all, n = none, Enter = extract, q = skip file\n")
+		key, readErr := picker.readKey()
+		if readErr != nil {
+			return nil, fmt.Errorf("reading track selection: %w", readErr)
+		}
+		switch key {
+		case "up", "k":
+			cursor = (cursor + len(tracks) - 1) % len(tracks)
+		case "down", "j":
+			cursor = (cursor + 1) % len(tracks)
+		case " ", "x":
+			selected[cursor] = !selected[cursor]
+		case "a", "n":
+			for i := range selected {
+				selected[i] = key == "a"
+			}
+		case "\r", "\n":
+			return pickedTracks(tracks, selected), nil
+		case "q", "\x1b":
+			return nil, nil
+		case "\x03":
+			return nil, errPickerInterrupted
+		}
+	}
+}
+
+// readKey reads a key press from the terminal: a character, or "up" or
+// "down" for the escape sequences of the arrow keys, which arrive at once
+func (picker *trackPicker) readKey() (string, error) {
+	first, readErr := picker.input.ReadByte()
+	if readErr != nil {
+		return "", readErr
+	}
+	if first != 0x1b || picker.input.Buffered() == 0 {
+		return string(first), nil
+	}
+	sequence := []byte{}
+	for picker.input.Buffered() > 0 {
+		next, _ := picker.input.ReadByte()
+		sequence = append(sequence, next)
+		if len(sequence) > 1 && (next >= 'A' && next <= 'Z' || next == '~') {
+			break
+		}
+	}
+	switch string(sequence) {
+	case "[A", "OA":
+		return "up", nil
+	case "[B", "OB":
+		return "down", nil
+	}
+	return "", nil
+}
+
+// pickByNumber lists the tracks as a numbered checklist and toggles the
+// numbers typed on each line
+func (picker *trackPicker) pickByNumber(inputFileName string, tracks []MKVTrack, selected []bool) ([]MKVTrack, error) {
+	for {
+		fmt.Fprintf(picker.output, "\n%s\n", inputFileName)
+		for i, track := range tracks {
+			mark := " "
+			if selected[i] {
+				mark = "x"
+			}
+			fmt.Fprintf(picker.output, "  [%s] %2d) %s\n", mark, i+1, describeTrack(track))
+		}
+		fmt.Fprint(picker.output, "Toggle numbers (e.g. 1 3-4), a = all, n = none, Enter = extract, q = skip file: ")
+		line, readErr := picker.input.ReadString('\n')
+		if readErr != nil && (readErr != io.EOF || line == "") {
+			return nil, fmt.Errorf("reading track selection: %w", readErr)
+		}
+		line = strings.TrimSpace(line)
+		switch strings.ToLower(line) {
+		case "":
+			return pickedTracks(tracks, selected), nil
+		case "q":
+			return nil, nil
+		case "a", "n":
+			for i := range selected {
+				selected[i] = strings.ToLower(line) == "a"
+			}
+			continue
+		}
+		indexes, parseErr := parseTrackSelection(line, len(tracks))
+		if parseErr != nil {
+			fmt.Fprintln(picker.output, parseErr)
+			continue
+		}
+		for _, index := range indexes {
+			selected[index] = !selected[index]
+		}
+	}
+}
+
+func pickedTracks(tracks []MKVTrack, selected []bool) []MKVTrack {
+	picked := []MKVTrack{}
+	for i, track := range tracks {
+		if selected[i] {
+			picked = append(picked, track)
+		}
+	}
+	return picked
+}
+
+// parseTrackSelection turns "1 3-4" or "1,3-4" into zero-based indexes
+func parseTrackSelection(selection string, count int) ([]int, error) {
+	indexes := []int{}
+	for _, field := range strings.FieldsFunc(selection, func(r rune) bool { return r == ',' || r == ' ' }) {
+		first, last, isRange := strings.Cut(field, "-")
+		if !isRange {
+			last = first
+		}
+		from, fromErr := strconv.Atoi(first)
+		to, toErr := strconv.Atoi(last)
+		if fromErr != nil || toErr != nil || from < 1 || to > count || from > to {
+			return nil, errors.New("invalid selection " + strconv.Quote(field))
+		}
+		for number := from; number <= to; number++ {
+			indexes = append(indexes, number-1)
+		}
+	}
+	return indexes, nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	typedTrackSelectorPattern = regexp.MustCompile(`^([avs])([1-9][0-9]*)$`)
+	languageCodePattern       = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]+)*$`)
+)
+
+// mkvTrackTypes maps selector prefixes to mkvmerge track types
+var mkvTrackTypes = map[string]string{"a": "audio", "v": "video", "s": "subtitles"}
+
+// PropEditOptions describes a track property change. Empty fields are left untouched.
+type PropEditOptions struct {
+	InputFileName string
+	Track         string
+	Default       string
+	Forced        string
+	Language      string
+	TrackName     string
+}
+
+// resolveTrackSelector finds the track a selector refers to: a track id as
+// printed by mkvmerge (3), or the nth track of a type (s1 = first subtitle track)
+func resolveTrackSelector(tracks []MKVTrack, selector string) (MKVTrack, error) {
+	if matches := typedTrackSelectorPattern.FindStringSubmatch(selector); matches != nil {
+		nth, _ := strconv.Atoi(matches[2])
+		for _, track := range tracks {
+			if track.Type == mkvTrackTypes[matches[1]] {
+				if nth--; nth == 0 {
+					return track, nil
+				}
+			}
+		}
+		return MKVTrack{}, fmt.Errorf("file has no track %s", selector)
+	}
+	id, atoiErr := strconv.Atoi(selector)
+	if atoiErr != nil {
+		return MKVTrack{}, fmt.Errorf("invalid track selector %q (expected a track id like 3, or s1, a1, v1)", selector)
+	}
+	for _, track := range tracks {
+		if track.Id == id {
+			return track, nil
+		}
+	}
+	return MKVTrack{}, fmt.Errorf("file has no track with id %d", id)
+}
+
+// parseFlagValue accepts yes/no style values for --default and --forced
+func parseFlagValue(option string, value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return "", nil
+	case "yes", "true", "1":
+		return "1", nil
+	case "no", "false", "0":
+		return "0", nil
+	}
+	return "", fmt.Errorf("invalid value %q for %s (expected yes or no)", value, option)
+}
+
+func buildPropEditArgs(options PropEditOptions, track MKVTrack) ([]string, error) {
+	args := []string{options.InputFileName, "--edit", "track:@" + strconv.Itoa(track.Properties.Number)}
+	defaultValue, defaultErr := parseFlagValue("--default", options.Default)
+	if defaultErr != nil {
+		return nil, defaultErr
+	}
+	if defaultValue != "" {
+		args = append(args, "--set", "flag-default="+defaultValue)
+	}
+	forcedValue, forcedErr := parseFlagValue("--forced", options.Forced)
+	if forcedErr != nil {
+		return nil, forcedErr
+	}
+	if forcedValue != "" {
+		args = append(args, "--set", "flag-forced="+forcedValue)
+	}
+	if options.Language != "" {
+		if !languageCodePattern.MatchString(options.Language) {
+			return nil, fmt.Errorf("invalid language code %q", options.Language)
+		}
+		args = append(args, "--set", "language="+options.Language)
+	}
+	if options.TrackName != "" {
+		args = append(args, "--set", "name="+options.TrackName)
+	}
+	if len(args) == 3 {
+		return nil, errors.New("nothing to change: use --default, --forced, --lang or --name")
+	}
+	return args, nil
+}
+
+func editTrackProperties(options PropEditOptions) error {
+	mkvInfo, identifyErr := identifyFile(options.InputFileName, 0, nil)
+	if identifyErr != nil {
+		return identifyErr
+	}
+	track, selectorErr := resolveTrackSelector(mkvInfo.Tracks, options.Track)
+	if selectorErr != nil {
+		logrus.
+			WithError(selectorErr).
+			WithField("inputFileName", options.InputFileName).
+			Error("Invalid track selector")
+		return selectorErr
+	}
+	args, argsErr := buildPropEditArgs(options, track)
+	if argsErr != nil {
+		return argsErr
+	}
+	cmd := exec.Command("mkvpropedit", args...)
+	logrus.
+		WithField("cmd", cmd.String()).
+		Debug("Running mkvpropedit")
+	output, cmdErr := cmd.CombinedOutput()
+	if cmdErr != nil {
+		logrus.
+			WithField("cmd", cmd).
+			WithError(cmdErr).
+			Error("Error executing mkvpropedit")
+		fmt.Println(string(output))
+		return cmdErr
+	}
+	logrus.
+		WithField("inputFileName", options.InputFileName).
+		WithField("trackId", track.Id).
+		Info("Track properties updated")
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FileStatusCompleted = "completed"
+	FileStatusSkipped   = "skipped"
+	FileStatusFailed    = "failed"
+)
+
+// FileReport is what a batch run did to one input file
+type FileReport struct {
+	InputFileName   string
+	Status          string
+	TracksExtracted int
+	OutputFileNames []string
+	Conversions     []string
+	Duration        time.Duration
+	Error           string
+}
+
+// addTrack records the files produced for one track; any file after the
+// extracted one is the result of a conversion
+func (report *FileReport) addTrack(track MKVTrack, producedFileNames []string) {
+	if report == nil || len(producedFileNames) == 0 {
+		return
+	}
+	report.TracksExtracted++
+	report.OutputFileNames = append(report.OutputFileNames, producedFileNames...)
+	for _, convertedFileName := range producedFileNames[1:] {
+		report.Conversions = append(report.Conversions,
+			fmt.Sprintf("track %d: %s -> %s", track.Id, track.Codec, strings.TrimPrefix(path.Ext(convertedFileName), ".")))
+	}
+}
+
+var reportHeader = []string{"file", "status", "tracks_extracted", "output_files", "conversions", "duration_seconds", "error"}
+
+func (report FileReport) row() []string {
+	return []string{
+		report.InputFileName,
+		report.Status,
+		strconv.Itoa(report.TracksExtracted),
+		strings.Join(report.OutputFileNames, "; "),
+		strings.Join(report.Conversions, "; "),
+		strconv.FormatFloat(report.Duration.Seconds(), 'f', 1, 64),
+		report.Error,
+	}
+}
+
+// writeReport writes the batch summary as Markdown when the file name ends
+// in .md or .markdown, and as CSV otherwise
+func writeReport(fileName string, reports []FileReport) error {
+	reportFile, createErr := os.Create(fileName)
+	if createErr != nil {
+		return createErr
+	}
+	var writeErr error
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".md", ".markdown":
+		writeErr = writeMarkdownReport(reportFile, reports)
+	default:
+		writeErr = writeCSVReport(reportFile, reports)
+	}
+	if closeErr := reportFile.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	return writeErr
+}
+
+func writeCSVReport(reportFile *os.File, reports []FileReport) error {
+	writer := csv.NewWriter(reportFile)
+	writer.Write(reportHeader)
+	for _, report := range reports {
+		writer.Write(report.row())
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeMarkdownReport(reportFile *os.File, reports []FileReport) error {
+	var content strings.Builder
+	counts := map[string]int{}
+	for _, report := range reports {
+		counts[report.Status]++
+	}
+	content.WriteString("# gmmmkvsubsextract report\n\n")
+	fmt.Fprintf(&content, "%s: %d files, %d completed, %d skipped, %d failed\n\n",
+		time.Now().Format(time.RFC3339), len(reports),
+		counts[FileStatusCompleted], counts[FileStatusSkipped], counts[FileStatusFailed])
+	content.WriteString("| " + strings.Join(reportHeader, " | ") + " |\n")
+	content.WriteString(strings.Repeat("| --- ", len(reportHeader)) + "|\n")
+	for _, report := range reports {
+		cells := report.row()
+		for i, cell := range cells {
+			cells[i] = strings.ReplaceAll(strings.ReplaceAll(cell, "|", `\|`), "\n", " ")
+		}
+		content.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	_, writeErr := reportFile.WriteString(content.String())
+	return writeErr
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const maxRetryBackoff = 30 * time.Second
+
+// withRetries runs fn and retries it up to retries times with an exponential
+// backoff, to ride out transient I/O errors on network shares
+func withRetries(retries int, description string, fn func() error) error {
+	err := fn()
+	backoff := time.Second
+	for attempt := 1; err != nil && attempt <= retries; attempt++ {
+		logrus.
+			WithError(err).
+			WithField("attempt", attempt).
+			WithField("retries", retries).
+			WithField("backoff", backoff).
+			Warnf("%s failed, retrying", description)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+		err = fn()
+	}
+	return err
+}
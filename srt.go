@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var srtTimestampPattern = regexp.MustCompile(`(\d{1,2}):(\d{2}):(\d{2})([,.])(\d{3})`)
+
+// SRTShiftOptions describes an SRT timing shift
+type SRTShiftOptions struct {
+	InputFileName  string
+	OutputFileName string
+	OffsetSeconds  float64
+}
+
+// joinNegativeOptionValues rewrites "--offset -2.3" as "--offset=-2.3", since
+// gocmd would otherwise read the negative number as an unknown flag
+func joinNegativeOptionValues(args []string, options ...string) []string {
+	joined := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if i+1 < len(args) && slices.Contains(options, args[i]) {
+			if _, parseErr := strconv.ParseFloat(args[i+1], 64); parseErr == nil && strings.HasPrefix(args[i+1], "-") {
+				joined = append(joined, args[i]+"="+args[i+1])
+				i++
+				continue
+			}
+		}
+		joined = append(joined, args[i])
+	}
+	return joined
+}
+
+func formatSRTTimestamp(milliseconds int, separator string) string {
+	if milliseconds < 0 {
+		milliseconds = 0
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d",
+		milliseconds/3600000, milliseconds/60000%60, milliseconds/1000%60, separator, milliseconds%1000)
+}
+
+// shiftSRTTiming moves every cue timing line by the offset, clamping times at zero
+// like the GUI's timing adjustment does
+func shiftSRTTiming(content string, offsetSeconds float64) string {
+	offsetMilliseconds := int(math.Round(offsetSeconds * 1000))
+	return srtTimingPattern.ReplaceAllStringFunc(content, func(timing string) string {
+		return srtTimestampPattern.ReplaceAllStringFunc(timing, func(timestamp string) string {
+			parts := srtTimestampPattern.FindStringSubmatch(timestamp)
+			hours, _ := strconv.Atoi(parts[1])
+			minutes, _ := strconv.Atoi(parts[2])
+			seconds, _ := strconv.Atoi(parts[3])
+			milliseconds, _ := strconv.Atoi(parts[5])
+			total := hours*3600000 + minutes*60000 + seconds*1000 + milliseconds
+			return formatSRTTimestamp(total+offsetMilliseconds, parts[4])
+		})
+	})
+}
+
+func shiftSRTFile(options SRTShiftOptions) error {
+	content, readErr := os.ReadFile(options.InputFileName)
+	if readErr != nil {
+		logrus.
+			WithError(readErr).
+			WithField("inputFileName", options.InputFileName).
+			Error("Error reading SRT file")
+		return readErr
+	}
+	if options.OutputFileName == "" {
+		options.OutputFileName = options.InputFileName
+	}
+	shifted := shiftSRTTiming(string(content), options.OffsetSeconds)
+	if writeErr := os.WriteFile(options.OutputFileName, []byte(shifted), 0644); writeErr != nil {
+		logrus.
+			WithError(writeErr).
+			WithField("outFileName", options.OutputFileName).
+			Error("Error writing SRT file")
+		return writeErr
+	}
+	logrus.
+		WithField("outFileName", options.OutputFileName).
+		WithField("offsetSeconds", options.OffsetSeconds).
+		WithField("cues", countSRTCues(content)).
+		Info("SRT timing shifted")
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RunState records what a batch run has already done, so an interrupted run
+// can be resumed without re-processing finished files
+type RunState struct {
+	fileName string
+	Files    map[string]*FileState `json:"files"`
+}
+
+// FileState is the progress of one input file. Size and ModTime detect files
+// that changed since they were processed.
+type FileState struct {
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"mod_time"`
+	Completed bool      `json:"completed"`
+	Tracks    []int     `json:"tracks"`
+}
+
+func newRunState(fileName string) *RunState {
+	return &RunState{fileName: fileName, Files: map[string]*FileState{}}
+}
+
+func loadRunState(fileName string) (*RunState, error) {
+	state := newRunState(fileName)
+	data, readErr := os.ReadFile(fileName)
+	if readErr != nil {
+		return nil, readErr
+	}
+	if jsonErr := json.Unmarshal(data, state); jsonErr != nil {
+		return nil, jsonErr
+	}
+	if state.Files == nil {
+		state.Files = map[string]*FileState{}
+	}
+	return state, nil
+}
+
+// Save writes the state atomically, so an interrupted run never leaves a truncated file behind
+func (state *RunState) Save() error {
+	data, jsonErr := json.MarshalIndent(state, "", "  ")
+	if jsonErr != nil {
+		return jsonErr
+	}
+	tempFileName := state.fileName + ".tmp"
+	if writeErr := os.WriteFile(tempFileName, data, 0644); writeErr != nil {
+		return writeErr
+	}
+	return os.Rename(tempFileName, state.fileName)
+}
+
+// fileState returns the state of an input file, starting over when the file changed
+func (state *RunState) fileState(inputFileName string) *FileState {
+	ifs, statErr := os.Stat(inputFileName)
+	if statErr != nil {
+		return &FileState{}
+	}
+	fileState, ok := state.Files[inputFileName]
+	if !ok || fileState.Size != ifs.Size() || !fileState.ModTime.Equal(ifs.ModTime()) {
+		fileState = &FileState{Size: ifs.Size(), ModTime: ifs.ModTime()}
+		state.Files[inputFileName] = fileState
+	}
+	return fileState
+}
+
+func (state *RunState) IsFileCompleted(inputFileName string) bool {
+	return state.fileState(inputFileName).Completed
+}
+
+func (state *RunState) IsTrackCompleted(inputFileName string, trackId int) bool {
+	for _, completedId := range state.fileState(inputFileName).Tracks {
+		if completedId == trackId {
+			return true
+		}
+	}
+	return false
+}
+
+func (state *RunState) MarkTrackCompleted(inputFileName string, trackId int) {
+	fileState := state.fileState(inputFileName)
+	fileState.Tracks = append(fileState.Tracks, trackId)
+	state.save()
+}
+
+func (state *RunState) MarkFileCompleted(inputFileName string) {
+	state.fileState(inputFileName).Completed = true
+	state.save()
+}
+
+// save persists the state after every step; a failure is logged rather than
+// aborting the extraction itself
+func (state *RunState) save() {
+	if saveErr := state.Save(); saveErr != nil {
+		logrus.
+			WithError(saveErr).
+			WithField("stateFileName", state.fileName).
+			Error("Error saving state file")
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StatsOptions describes a library scan
+type StatsOptions struct {
+	Input     string
+	Languages []string
+	Cache     *IdentifyCache
+}
+
+// LibraryStats summarizes the subtitle tracks of every MKV file below a directory
+type LibraryStats struct {
+	Files           int
+	FailedFiles     []string
+	WithoutSubs     []string
+	OnlyImageSubs   []string
+	MissingLanguage map[string][]string
+	Codecs          map[string]int
+}
+
+// sameLanguage compares language codes, treating the B and T forms (ger/deu)
+// and their 2-letter codes as equal
+func sameLanguage(a string, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if a == b {
+		return true
+	}
+	if code, ok := twoLetterLanguageCodes[a]; ok {
+		a = code
+	}
+	if code, ok := twoLetterLanguageCodes[b]; ok {
+		b = code
+	}
+	return a == b
+}
+
+func collectLibraryStats(options StatsOptions) (LibraryStats, error) {
+	stats := LibraryStats{MissingLanguage: map[string][]string{}, Codecs: map[string]int{}}
+	inputFileNames, collectErr := collectInputFiles(options.Input)
+	if collectErr != nil {
+		return stats, collectErr
+	}
+	for _, inputFileName := range inputFileNames {
+		stats.Files++
+		mkvInfo, identifyErr := identifyFile(inputFileName, 0, options.Cache)
+		if identifyErr != nil {
+			stats.FailedFiles = append(stats.FailedFiles, inputFileName)
+			continue
+		}
+		subtitleTracks := 0
+		imageTracks := 0
+		for _, track := range mkvInfo.Tracks {
+			if track.Type != "subtitles" {
+				continue
+			}
+			subtitleTracks++
+			if isImageSubtitleCodec(track.Properties.CodecId) {
+				imageTracks++
+			}
+			stats.Codecs[track.Properties.CodecId]++
+		}
+		if subtitleTracks == 0 {
+			stats.WithoutSubs = append(stats.WithoutSubs, inputFileName)
+		} else if imageTracks == subtitleTracks {
+			stats.OnlyImageSubs = append(stats.OnlyImageSubs, inputFileName)
+		}
+		for _, lang := range options.Languages {
+			found := false
+			for _, track := range mkvInfo.Tracks {
+				if track.Type == "subtitles" && sameLanguage(track.Properties.Language, lang) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				stats.MissingLanguage[lang] = append(stats.MissingLanguage[lang], inputFileName)
+			}
+		}
+	}
+	logrus.
+		WithField("files", stats.Files).
+		WithField("failed", len(stats.FailedFiles)).
+		Info("Library scanned")
+	return stats, nil
+}
+
+func writeFileList(writer io.Writer, title string, fileNames []string) {
+	fmt.Fprintf(writer, "\n%s: %d\n", title, len(fileNames))
+	for _, fileName := range fileNames {
+		fmt.Fprintf(writer, "  %s\n", fileName)
+	}
+}
+
+// Write prints the statistics as plain text
+func (stats LibraryStats) Write(writer io.Writer, languages []string) {
+	fmt.Fprintf(writer, "Files scanned: %d\n", stats.Files)
+	fmt.Fprintf(writer, "\nSubtitle codecs:\n")
+	codecs := []string{}
+	for codec := range stats.Codecs {
+		codecs = append(codecs, codec)
+	}
+	sort.Slice(codecs, func(i, j int) bool {
+		if stats.Codecs[codecs[i]] != stats.Codecs[codecs[j]] {
+			return stats.Codecs[codecs[i]] > stats.Codecs[codecs[j]]
+		}
+		return codecs[i] < codecs[j]
+	})
+	for _, codec := range codecs {
+		fmt.Fprintf(writer, "  %-16s %d tracks\n", codec, stats.Codecs[codec])
+	}
+	writeFileList(writer, "Files without subtitles", stats.WithoutSubs)
+	writeFileList(writer, "Files with only image-based subtitles (need OCR)", stats.OnlyImageSubs)
+	for _, lang := range languages {
+		writeFileList(writer, "Files without "+lang+" subtitles", stats.MissingLanguage[lang])
+	}
+	if len(stats.FailedFiles) > 0 {
+		writeFileList(writer, "Files that could not be identified", stats.FailedFiles)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StripOptions describes a remux that drops subtitle tracks
+type StripOptions struct {
+	InputFileName  string
+	OutputFileName string
+	TrackIds       []int
+	Languages      []string
+	Overwrite      bool
+}
+
+func buildStripOutputFileName(inputFileName string) string {
+	return strings.TrimSuffix(inputFileName, path.Ext(inputFileName)) + "_stripped.mkv"
+}
+
+// parseTrackIds parses a comma separated list of track ids such as "3,4"
+func parseTrackIds(list string) ([]int, error) {
+	ids := []int{}
+	for _, field := range strings.Split(list, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		id, atoiErr := strconv.Atoi(field)
+		if atoiErr != nil || id < 0 {
+			return nil, fmt.Errorf("invalid track id %q", field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// keptSubtitleTracks returns the ids of the subtitle tracks that survive the strip
+func keptSubtitleTracks(tracks []MKVTrack, options StripOptions) ([]string, error) {
+	dropIds := map[int]bool{}
+	for _, id := range options.TrackIds {
+		dropIds[id] = true
+	}
+	for id := range dropIds {
+		found := false
+		for _, track := range tracks {
+			if track.Id == id {
+				if track.Type != "subtitles" {
+					return nil, fmt.Errorf("track %d is not a subtitle track", id)
+				}
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("file has no track with id %d", id)
+		}
+	}
+	kept := []string{}
+	dropped := 0
+	for _, track := range tracks {
+		if track.Type != "subtitles" {
+			continue
+		}
+		drop := dropIds[track.Id]
+		for _, lang := range options.Languages {
+			drop = drop || sameLanguage(track.Properties.Language, lang)
+		}
+		if drop {
+			dropped++
+			logrus.
+				WithField("trackId", track.Id).
+				WithField("trackLanguage", track.Properties.Language).
+				Infof("Dropping track %d", track.Id)
+			continue
+		}
+		kept = append(kept, strconv.Itoa(track.Id))
+	}
+	if dropped == 0 {
+		return nil, errors.New("no subtitle track matches the selection")
+	}
+	return kept, nil
+}
+
+func stripSubtitles(options StripOptions) error {
+	if len(options.TrackIds) == 0 && len(options.Languages) == 0 {
+		return errors.New("select the subtitle tracks to drop with --tracks and/or --lang")
+	}
+	mkvInfo, identifyErr := identifyFile(options.InputFileName, 0, nil)
+	if identifyErr != nil {
+		return identifyErr
+	}
+	kept, keptErr := keptSubtitleTracks(mkvInfo.Tracks, options)
+	if keptErr != nil {
+		logrus.
+			WithError(keptErr).
+			WithField("inputFileName", options.InputFileName).
+			Error("Invalid track selection")
+		return keptErr
+	}
+	if options.OutputFileName == "" {
+		options.OutputFileName = buildStripOutputFileName(options.InputFileName)
+	} else if !isMKVFile(options.OutputFileName) {
+		options.OutputFileName += ".mkv"
+	}
+	if options.OutputFileName == options.InputFileName {
+		return errors.New("output file must differ from the input file")
+	}
+	policy := ConflictPolicyFail
+	if options.Overwrite {
+		policy = ConflictPolicyOverwrite
+	}
+	if _, _, conflictErr := resolveOutputFileName(options.OutputFileName, policy); conflictErr != nil {
+		logrus.WithError(conflictErr).Error("Output file already exists")
+		return conflictErr
+	}
+	args := []string{"-o", options.OutputFileName}
+	if len(kept) == 0 {
+		args = append(args, "--no-subtitles")
+	} else {
+		args = append(args, "--subtitle-tracks", strings.Join(kept, ","))
+	}
+	if muxErr := runMkvmerge(append(args, options.InputFileName)); muxErr != nil {
+		return muxErr
+	}
+	logrus.
+		WithField("outFileName", options.OutputFileName).
+		WithField("keptTracks", len(kept)).
+		Info("Subtitle tracks removed")
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRawTerminal cannot switch the terminal to single key presses on this
+// platform, so tracks are picked by number
+func makeRawTerminal(file *os.File) (func(), error) {
+	return nil, errors.New("raw terminal not supported on this platform")
+}
@@ -0,0 +1,30 @@
+//go:build linux || darwin || freebsd
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// makeRawTerminal switches the terminal on file to reading single key
+// presses without echoing them, Ctrl-C included, and returns the function
+// restoring it
+func makeRawTerminal(file *os.File) (func(), error) {
+	fd := file.Fd()
+	var saved syscall.Termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(&saved))); errno != 0 {
+		return nil, errno
+	}
+	raw := saved
+	raw.Lflag &^= syscall.ICANON | syscall.ECHO | syscall.ISIG
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&raw))); errno != 0 {
+		return nil, errno
+	}
+	return func() {
+		syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&saved)))
+	}, nil
+}
@@ -0,0 +1,47 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// Console modes of the Windows console API
+const (
+	enableProcessedInput            = 0x0001
+	enableLineInput                 = 0x0002
+	enableEchoInput                 = 0x0004
+	enableVirtualTerminalProcessing = 0x0004
+	enableVirtualTerminalInput      = 0x0200
+)
+
+var setConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// makeRawTerminal switches the console on file to reading single key
+// presses without echoing them, Ctrl-C included, and arrow keys as escape
+// sequences, and lets stderr draw with escape sequences. It returns the
+// function restoring both.
+func makeRawTerminal(file *os.File) (func(), error) {
+	input := syscall.Handle(file.Fd())
+	output := syscall.Handle(os.Stderr.Fd())
+	var savedInput, savedOutput uint32
+	if err := syscall.GetConsoleMode(input, &savedInput); err != nil {
+		return nil, err
+	}
+	if err := syscall.GetConsoleMode(output, &savedOutput); err != nil {
+		return nil, err
+	}
+	rawInput := savedInput&^(enableProcessedInput|enableLineInput|enableEchoInput) | enableVirtualTerminalInput
+	if ok, _, err := setConsoleMode.Call(uintptr(input), uintptr(rawInput)); ok == 0 {
+		return nil, err
+	}
+	if ok, _, err := setConsoleMode.Call(uintptr(output), uintptr(savedOutput|enableVirtualTerminalProcessing)); ok == 0 {
+		setConsoleMode.Call(uintptr(input), uintptr(savedInput))
+		return nil, err
+	}
+	return func() {
+		setConsoleMode.Call(uintptr(input), uintptr(savedInput))
+		setConsoleMode.Call(uintptr(output), uintptr(savedOutput))
+	}, nil
+}
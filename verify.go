@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var srtTimingPattern = regexp.MustCompile(`(?m)^\s*\d{1,2}:\d{2}:\d{2}[,.]\d{3}\s*-->\s*\d{1,2}:\d{2}:\d{2}[,.]\d{3}`)
+
+func countSRTCues(content []byte) int {
+	return len(srtTimingPattern.FindAll(content, -1))
+}
+
+func countASSCues(content []byte) (int, error) {
+	text := string(content)
+	if !strings.Contains(text, "[Script Info]") {
+		return 0, errors.New("missing [Script Info] section")
+	}
+	if !strings.Contains(text, "[Events]") {
+		return 0, errors.New("missing [Events] section")
+	}
+	cues := 0
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "Dialogue:") {
+			cues++
+		}
+	}
+	return cues, nil
+}
+
+// countPGSCues counts the presentation composition segments of a .sup file
+func countPGSCues(content []byte) (int, error) {
+	if !bytes.HasPrefix(content, []byte("PG")) {
+		return 0, errors.New("missing PG segment header")
+	}
+	cues := 0
+	for offset := 0; offset+13 <= len(content); {
+		if content[offset] != 'P' || content[offset+1] != 'G' {
+			return cues, fmt.Errorf("corrupt segment at offset %d", offset)
+		}
+		segmentType := content[offset+10]
+		segmentSize := int(content[offset+11])<<8 | int(content[offset+12])
+		if segmentType == 0x16 {
+			cues++
+		}
+		offset += 13 + segmentSize
+	}
+	return cues, nil
+}
+
+func countVobSubCues(idxFileName string, content []byte) (int, error) {
+	subFileName := replaceExtension(idxFileName, "sub")
+	if ifs, statErr := os.Stat(subFileName); statErr != nil || ifs.Size() == 0 {
+		return 0, fmt.Errorf("missing or empty %s", path.Base(subFileName))
+	}
+	cues := 0
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "timestamp:") {
+			cues++
+		}
+	}
+	return cues, nil
+}
+
+// verifySubtitlesFile checks that an extracted or converted file is non-empty,
+// has a parseable header for its format and contains at least one cue
+func verifySubtitlesFile(fileName string) error {
+	content, readErr := os.ReadFile(fileName)
+	if readErr != nil {
+		return readErr
+	}
+	if len(content) == 0 {
+		return errors.New("file is empty")
+	}
+	cues := -1
+	var parseErr error
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".srt":
+		cues = countSRTCues(content)
+	case ".ass", ".ssa":
+		cues, parseErr = countASSCues(content)
+	case ".sup":
+		cues, parseErr = countPGSCues(content)
+	case ".idx":
+		cues, parseErr = countVobSubCues(fileName, content)
+	}
+	if parseErr != nil {
+		return parseErr
+	}
+	if cues == 0 {
+		return errors.New("file contains no subtitle cues")
+	}
+	logrus.
+		WithField("fileName", fileName).
+		WithField("cues", cues).
+		Debug("Output verified")
+	return nil
+}
+
+// verifyOutputs verifies every produced file and deletes the broken ones,
+// so a successful exit status can be trusted by automation
+func verifyOutputs(fileNames []string) error {
+	var firstErr error
+	for _, fileName := range fileNames {
+		verifyErr := verifySubtitlesFile(fileName)
+		if verifyErr == nil {
+			continue
+		}
+		logrus.
+			WithField("fileName", fileName).
+			WithError(verifyErr).
+			Error("Output failed verification, deleting it")
+		os.Remove(fileName)
+		if strings.ToLower(path.Ext(fileName)) == ".idx" {
+			os.Remove(replaceExtension(fileName, "sub"))
+		}
+		if firstErr == nil {
+			firstErr = fmt.Errorf("%s failed verification: %w", fileName, verifyErr)
+		}
+	}
+	return firstErr
+}
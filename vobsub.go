@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// vobSubTick is the unit of the display dates in VobSub control sequences
+const vobSubTick = time.Second * 1024 / 90000
+
+// vobSubIndex is what the CLI reads from a VobSub .idx file: the 16 color
+// palette and, for the first subtitle stream, when each subtitle starts and
+// where its packet is in the .sub file
+type vobSubIndex struct {
+	Palette [16]color.NRGBA
+	Entries []vobSubIndexEntry
+}
+
+// vobSubIndexEntry is a subtitle listed in a VobSub .idx file
+type vobSubIndexEntry struct {
+	Start   time.Duration
+	FilePos int64
+}
+
+// readVobSubIndex reads the palette and the subtitles of a VobSub .idx file
+func readVobSubIndex(idxFileName string) (vobSubIndex, error) {
+	var index vobSubIndex
+	file, openErr := os.Open(idxFileName)
+	if openErr != nil {
+		return index, openErr
+	}
+	defer file.Close()
+
+	var delay time.Duration
+	streams := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "palette:"):
+			for i, entry := range strings.Split(strings.TrimPrefix(line, "palette:"), ",") {
+				if i >= len(index.Palette) {
+					break
+				}
+				rgb, parseErr := strconv.ParseUint(strings.TrimSpace(entry), 16, 32)
+				if parseErr != nil {
+					return index, fmt.Errorf("invalid palette entry %q in %s", entry, idxFileName)
+				}
+				index.Palette[i] = color.NRGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 255}
+			}
+		case strings.HasPrefix(line, "id:"):
+			streams++
+		case streams > 1:
+			// Only the first stream is converted
+		case strings.HasPrefix(line, "delay:"):
+			d, parseErr := parseVobSubTime(strings.TrimPrefix(line, "delay:"))
+			if parseErr != nil {
+				return index, fmt.Errorf("invalid delay %q in %s", line, idxFileName)
+			}
+			delay += d
+		case strings.HasPrefix(line, "timestamp:"):
+			// timestamp: 00:00:01:000, filepos: 000000000
+			stamp, pos, ok := strings.Cut(strings.TrimPrefix(line, "timestamp:"), ",")
+			start, parseErr := parseVobSubTime(stamp)
+			if !ok || parseErr != nil {
+				return index, fmt.Errorf("invalid timestamp %q in %s", line, idxFileName)
+			}
+			filePos, parseErr := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(pos), "filepos:")), 16, 64)
+			if parseErr != nil {
+				return index, fmt.Errorf("invalid file position %q in %s", line, idxFileName)
+			}
+			index.Entries = append(index.Entries, vobSubIndexEntry{Start: start + delay, FilePos: filePos})
+		}
+	}
+	return index, scanner.Err()
+}
+
+// parseVobSubTime parses a VobSub timestamp of the form HH:MM:SS:mmm, which
+// may be negative in delay lines
+func parseVobSubTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	sign := time.Duration(1)
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		sign, s = -1, rest
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 {
+		return 0, fmt.Errorf("invalid VobSub timestamp %q", s)
+	}
+	units := []time.Duration{time.Hour, time.Minute, time.Second, time.Millisecond}
+	var d time.Duration
+	for i, part := range parts {
+		n, atoiErr := strconv.Atoi(part)
+		if atoiErr != nil {
+			return 0, fmt.Errorf("invalid VobSub timestamp %q", s)
+		}
+		d += time.Duration(n) * units[i]
+	}
+	return sign * d, nil
+}
+
+// readVobSubPacket collects the SPU packet starting at the beginning of data,
+// a part of a VobSub .sub file, which is an MPEG program stream
+func readVobSubPacket(data []byte) []byte {
+	var packet []byte
+	stream := -1
+	for pos := 0; pos+6 <= len(data); {
+		if !bytes.HasPrefix(data[pos:], []byte{0, 0, 1}) {
+			pos++
+			continue
+		}
+		streamID := data[pos+3]
+		if streamID == 0xba {
+			// MPEG-2 pack header with stuffing
+			if pos+14 > len(data) {
+				break
+			}
+			pos += 14 + int(data[pos+13]&0x07)
+			continue
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+4:]))
+		end := pos + 6 + length
+		if end > len(data) {
+			break
+		}
+		if streamID == 0xbd && length > 3 && pos+9+int(data[pos+8]) <= end {
+			// Private stream 1: PES header, then the substream ID and SPU data
+			payload := data[pos+9+int(data[pos+8]) : end]
+			if len(payload) > 1 && (stream == -1 || int(payload[0]) == stream) {
+				stream = int(payload[0])
+				packet = append(packet, payload[1:]...)
+				if len(packet) >= 2 && len(packet) >= int(binary.BigEndian.Uint16(packet)) {
+					return packet
+				}
+			}
+		}
+		pos = end
+	}
+	return nil
+}
+
+// decodeVobSubSPU decodes a VobSub subtitle packet into a bitmap and returns
+// how long it is shown, from the dates of its start and stop display
+// commands, or 0 when it has no stop command
+func decodeVobSubSPU(spu []byte, palette *[16]color.NRGBA) (image.Image, time.Duration, error) {
+	if len(spu) < 4 {
+		return nil, 0, errors.New("subtitle packet too short")
+	}
+	var colors, alphas [4]byte
+	var x1, x2, y1, y2, topOffset, bottomOffset int
+	var start, stop time.Duration
+
+	// Walk the control sequences
+	for ctrl := int(binary.BigEndian.Uint16(spu[2:])); ctrl+4 <= len(spu); {
+		date := time.Duration(binary.BigEndian.Uint16(spu[ctrl:])) * vobSubTick
+		next := int(binary.BigEndian.Uint16(spu[ctrl+2:]))
+		i := ctrl + 4
+	commands:
+		for i < len(spu) {
+			cmd := spu[i]
+			i++
+			switch cmd {
+			case 0x01:
+				start = date
+			case 0x02:
+				stop = date
+			case 0x03, 0x04:
+				if i+2 > len(spu) {
+					break commands
+				}
+				values := [4]byte{spu[i+1] & 0x0f, spu[i+1] >> 4, spu[i] & 0x0f, spu[i] >> 4}
+				if cmd == 0x03 {
+					colors = values
+				} else {
+					alphas = values
+				}
+				i += 2
+			case 0x05:
+				if i+6 > len(spu) {
+					break commands
+				}
+				x1 = int(spu[i])<<4 | int(spu[i+1])>>4
+				x2 = int(spu[i+1]&0x0f)<<8 | int(spu[i+2])
+				y1 = int(spu[i+3])<<4 | int(spu[i+4])>>4
+				y2 = int(spu[i+4]&0x0f)<<8 | int(spu[i+5])
+				i += 6
+			case 0x06:
+				if i+4 > len(spu) {
+					break commands
+				}
+				topOffset = int(binary.BigEndian.Uint16(spu[i:]))
+				bottomOffset = int(binary.BigEndian.Uint16(spu[i+2:]))
+				i += 4
+			case 0x00:
+				// Forced display carries no arguments
+			default:
+				break commands
+			}
+		}
+		if next <= ctrl {
+			break
+		}
+		ctrl = next
+	}
+
+	width, height := x2-x1+1, y2-y1+1
+	if width <= 0 || height <= 0 || topOffset == 0 {
+		return nil, 0, errors.New("subtitle packet has no bitmap")
+	}
+	var duration time.Duration
+	if stop > start {
+		duration = stop - start
+	}
+
+	var pixelColors [4]color.NRGBA
+	for i := range pixelColors {
+		c := palette[colors[i]]
+		c.A = alphas[i] * 17
+		pixelColors[i] = c
+	}
+
+	// Even lines come from the top field, odd lines from the bottom field
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	offsets := [2]int{topOffset, bottomOffset}
+	for field := 0; field < 2; field++ {
+		nibble := offsets[field] * 2
+		readNibble := func() int {
+			if nibble/2 >= len(spu) {
+				return 0
+			}
+			b := spu[nibble/2]
+			nibble++
+			if nibble%2 == 1 {
+				return int(b >> 4)
+			}
+			return int(b & 0x0f)
+		}
+
+		for y := field; y < height; y += 2 {
+			for x := 0; x < width; {
+				v := readNibble()
+				if v < 0x4 {
+					v = v<<4 | readNibble()
+					if v < 0x10 {
+						v = v<<4 | readNibble()
+						if v < 0x40 {
+							v = v<<4 | readNibble()
+						}
+					}
+				}
+				run := v >> 2
+				if run == 0 {
+					// Fill to the end of the line
+					run = width - x
+				}
+				for ; run > 0 && x < width; run-- {
+					img.SetNRGBA(x, y, pixelColors[v&0x03])
+					x++
+				}
+			}
+			// Lines start on a byte boundary
+			nibble += nibble % 2
+		}
+	}
+	return img, duration, nil
+}
+
+// parseVobSubCues decodes the subtitles listed in a VobSub .idx file from its
+// .sub file. A subtitle without a stop command is shown until the next one
+// starts, for at most defaultCueDuration.
+func parseVobSubCues(idxFileName string) ([]BitmapCue, error) {
+	index, indexErr := readVobSubIndex(idxFileName)
+	if indexErr != nil {
+		return nil, indexErr
+	}
+	data, readErr := os.ReadFile(strings.TrimSuffix(idxFileName, ".idx") + ".sub")
+	if readErr != nil {
+		return nil, readErr
+	}
+	cues := []BitmapCue{}
+	for i, entry := range index.Entries {
+		if entry.FilePos < 0 || entry.FilePos >= int64(len(data)) {
+			continue
+		}
+		packet := readVobSubPacket(data[entry.FilePos:])
+		if packet == nil {
+			continue
+		}
+		img, duration, decodeErr := decodeVobSubSPU(packet, &index.Palette)
+		if decodeErr != nil {
+			continue
+		}
+		end := entry.Start + duration
+		if duration == 0 {
+			end = entry.Start + defaultCueDuration
+		}
+		if i+1 < len(index.Entries) && index.Entries[i+1].Start > entry.Start && end > index.Entries[i+1].Start {
+			end = index.Entries[i+1].Start
+		}
+		cues = append(cues, BitmapCue{Start: entry.Start, End: end, Image: img})
+	}
+	return cues, nil
+}